@@ -1,6 +1,6 @@
-SET @MYSQLDUMP_TEMP_LOG_BIN = @@SESSION.SQL_LOG_BIN;
-SET @@SESSION.SQL_LOG_BIN=0;
-
-SET @@GLOBAL.GTID_PURGED=/*!80000 '+'*/ '';
-
+SET @MYSQLDUMP_TEMP_LOG_BIN = @@SESSION.SQL_LOG_BIN;
+SET @@SESSION.SQL_LOG_BIN=0;
+
+SET @@GLOBAL.GTID_PURGED=/*!80000 '+'*/ '';
+
 CREATE DATABASE IF NOT EXISTS `go_course_course`;
\ No newline at end of file
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+)
+
+// runConfig: "config check" valida la configuración sin conectarse a nada y la
+// muestra con los secretos ocultos; sale con exitConfig si es inválida
+func runConfig(args []string) error {
+	fs, common := newFlagSet("config")
+	positional, err := parseFlags(fs, common, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || positional[0] != "check" {
+		return usageErrorf("config requires: check")
+	}
+
+	cfg, err := loadConfig(common)
+	if err != nil {
+		return err
+	}
+	cfg.Database.Password = redact(cfg.Database.Password)
+	cfg.Courses.AdminToken = redact(cfg.Courses.AdminToken)
+	cfg.Tenancy.JWTSecret = redact(cfg.Tenancy.JWTSecret)
+
+	t := table{headers: []string{"SETTING", "VALUE"}, rows: [][]string{
+		{"server.address", cfg.Server.Host + ":" + cfg.Server.Port},
+		{"server.tls", strconv.FormatBool(cfg.Server.TLS.CertFile != "")},
+		{"server.mtls", strconv.FormatBool(cfg.Server.TLS.ClientCAFile != "")},
+		{"server.http2", strconv.FormatBool(cfg.Server.HTTP2)},
+		{"database", cfg.Database.User + "@" + cfg.Database.Host + ":" + cfg.Database.Port + "/" + cfg.Database.Name},
+		{"database.replicas", orDash(strings.Join(cfg.Database.ReplicaHosts, ","))},
+		{"pagination", strconv.Itoa(cfg.Pagination.DefaultLimit) + " (max " + strconv.Itoa(cfg.Pagination.MaxLimit) + ")"},
+		{"search.backend", cfg.Search.Backend},
+		{"user_service.url", orDash(cfg.UserService.URL)},
+		{"nats.url", orDash(cfg.NATS.URL)},
+		{"webhooks.enabled", strconv.FormatBool(cfg.Courses.AdminToken != "")},
+		{"webhooks.allow_private_networks", strconv.FormatBool(cfg.Webhooks.AllowPrivateNetworks)},
+		{"tenancy.source", tenantSource(cfg.Tenancy)},
+		{"tenancy.required", strconv.FormatBool(cfg.Tenancy.Required)},
+		{"locales", strings.Join(cfg.Locales.Supported, ",") + " (default " + cfg.Locales.Default + ")"},
+	}}
+	return printOutput(common, cfg, t)
+}
+
+// tenantSource describe de dónde sale el tenant de cada request
+func tenantSource(cfg config.Tenancy) string {
+	if cfg.JWTSecret != "" {
+		return "jwt claim " + cfg.JWTClaim
+	}
+	return "header " + cfg.Header
+}
+
+// redact oculta un secreto configurado sin ocultar que falta
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "***"
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/NicoJCastro/gocourse_course/pkg/bootstrap"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/NicoJCastro/gocourse_course/pkg/replica"
+	"github.com/google/uuid"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// runCourses opera sobre course.Service directamente contra la base configurada,
+// con las mismas validaciones, auditoría y eventos que la API
+func runCourses(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("courses requires one of: list, get, create, delete")
+	}
+	switch action, args := args[0], args[1:]; action {
+	case "list":
+		return listCourses(args)
+	case "get":
+		return getCourse(args)
+	case "create":
+		return createCourse(args)
+	case "delete":
+		return deleteCourse(args)
+	default:
+		return usageErrorf("unknown courses action %q, must be list, get, create or delete", action)
+	}
+}
+
+func listCourses(args []string) error {
+	fs, common := newFlagSet("courses list")
+	scope := newTenantFlag(fs)
+	name := fs.String("name", "", "filter by name")
+	deleted := fs.String("deleted", "", "include or only trashed courses")
+	tags := fs.String("tags", "", "comma separated tag names")
+	tagMatch := fs.String("tag-match", string(course.TagMatchAny), "any or all")
+	category := fs.String("category", "", "category ID (includes subcategories)")
+	limit := fs.Int("limit", 50, "max courses")
+	offset := fs.Int("offset", 0, "courses to skip")
+	positional, err := parseFlags(fs, common, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("courses list takes no arguments")
+	}
+
+	filters := course.Filters{
+		Name:     *name,
+		Deleted:  course.DeletedScope(*deleted),
+		TagMatch: course.TagMatch(*tagMatch),
+		Category: *category,
+	}
+	if filters.Deleted != course.DeletedExclude && filters.Deleted != course.DeletedInclude && filters.Deleted != course.DeletedOnly {
+		return usageErrorf("%s", course.ErrInvalidDeletedScope)
+	}
+	if filters.TagMatch != course.TagMatchAny && filters.TagMatch != course.TagMatchAll {
+		return usageErrorf("%s", course.ErrInvalidTagMatch)
+	}
+	if *tags != "" {
+		filters.Tags = strings.Split(*tags, ",")
+	}
+	if *limit <= 0 || *offset < 0 {
+		return usageErrorf("-limit must be positive and -offset not negative")
+	}
+
+	svc, err := openCourses(common)
+	if err != nil {
+		return err
+	}
+	courses, err := svc.GetAll(cliContext(audit.SystemActor, scope), filters, *offset, *limit)
+	if err != nil {
+		return err
+	}
+	return printOutput(common, courses, coursesTable(courses...))
+}
+
+func getCourse(args []string) error {
+	fs, common := newFlagSet("courses get")
+	scope := newTenantFlag(fs)
+	positional, err := parseFlags(fs, common, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageErrorf("courses get requires the course ID")
+	}
+
+	svc, err := openCourses(common)
+	if err != nil {
+		return err
+	}
+	c, err := svc.Get(cliContext(audit.SystemActor, scope), positional[0])
+	if err != nil {
+		return err
+	}
+	return printOutput(common, c, coursesTable(*c))
+}
+
+func createCourse(args []string) error {
+	fs, common := newFlagSet("courses create")
+	scope := newTenantFlag(fs)
+	name := fs.String("name", "", "course name (required)")
+	start := fs.String("start", "", "start date, 2006-01-02 or RFC 3339 (required)")
+	end := fs.String("end", "", "end date, 2006-01-02 or RFC 3339 (required)")
+	timezone := fs.String("timezone", "", "IANA timezone (default UTC)")
+	category := fs.String("category", "", "category ID")
+	tags := fs.String("tags", "", "comma separated tag names")
+	instructors := fs.String("instructors", "", "comma separated instructor user IDs")
+	actor := fs.String("actor", audit.SystemActor, "user ID recorded in the audit log")
+	positional, err := parseFlags(fs, common, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("courses create takes no arguments, use flags")
+	}
+	if *name == "" || *start == "" || *end == "" {
+		return usageErrorf("-name, -start and -end are required")
+	}
+
+	var class course.Classification
+	if *category != "" {
+		class.CategoryID = category
+	}
+	if *tags != "" {
+		class.Tags = strings.Split(*tags, ",")
+	}
+	var instructorIDs []string
+	if *instructors != "" {
+		instructorIDs = strings.Split(*instructors, ",")
+	}
+
+	svc, err := openCourses(common)
+	if err != nil {
+		return err
+	}
+	c, err := svc.Create(cliContext(*actor, scope), *name, *start, *end, *timezone, class, instructorIDs)
+	if err != nil {
+		return err
+	}
+	return printOutput(common, c, coursesTable(*c))
+}
+
+func deleteCourse(args []string) error {
+	fs, common := newFlagSet("courses delete")
+	scope := newTenantFlag(fs)
+	purge := fs.Bool("purge", false, "delete permanently instead of moving to the trash")
+	actor := fs.String("actor", audit.SystemActor, "user ID recorded in the audit log")
+	positional, err := parseFlags(fs, common, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageErrorf("courses delete requires the course ID")
+	}
+	id := positional[0]
+
+	svc, err := openCourses(common)
+	if err != nil {
+		return err
+	}
+	ctx := cliContext(*actor, scope)
+	status := "deleted"
+	if *purge {
+		err = svc.Purge(ctx, id)
+		status = "purged"
+	} else {
+		err = svc.Delete(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	result := map[string]string{"id": id, "status": status}
+	return printOutput(common, result, table{headers: []string{"ID", "STATUS"}, rows: [][]string{{id, status}}})
+}
+
+// openCourses arma el servicio de cursos contra la base de la configuración; la
+// búsqueda usa MySQL porque el índice de bleve vive en memoria del servidor
+func openCourses(common *commonFlags) (course.Service, error) {
+	cfg, err := loadConfig(common)
+	if err != nil {
+		return nil, err
+	}
+	logger := cliLogger(common)
+	db, err := openDB(cfg, common)
+	if err != nil {
+		return nil, err
+	}
+	repo := course.NewResilientRepo(logger, course.NewRepo(db, logger), course.DefaultResilienceConfig())
+	return newCourseService(logger, cfg, repo, course.NewMySQLSearcher(logger, db))
+}
+
+// cliContext es el equivalente a una request: actor y request ID para la auditoría,
+// lectura del primario después de escribir y, con -tenant, la organización
+func cliContext(actor string, scope *tenantFlag) context.Context {
+	ctx := audit.WithActor(context.Background(), actor)
+	ctx = audit.WithRequestID(ctx, uuid.New().String())
+	if scope.set {
+		ctx = tenant.WithTenant(ctx, scope.id)
+	}
+	return replica.WithTracker(ctx)
+}
+
+// tenantFlag es -tenant: sin el flag los comandos ven los cursos de todas las
+// organizaciones y las altas van al tenant por defecto; -tenant "" lo selecciona
+type tenantFlag struct {
+	id  string
+	set bool
+}
+
+func newTenantFlag(fs *flag.FlagSet) *tenantFlag {
+	scope := &tenantFlag{}
+	fs.Var(scope, "tenant", "organization ID (default: every tenant)")
+	return scope
+}
+
+func (t *tenantFlag) String() string {
+	return t.id
+}
+
+func (t *tenantFlag) Set(value string) error {
+	if err := tenant.Validate(value); err != nil {
+		return err
+	}
+	t.id, t.set = value, true
+	return nil
+}
+
+// openDB conecta a la base; el logger de GORM escribe en stdout, así que sólo
+// queda activo con -verbose
+func openDB(cfg *config.Config, common *commonFlags) (*gorm.DB, error) {
+	db, err := bootstrap.DBConnection(cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	if !common.verbose {
+		db.Logger = gormlogger.Discard
+	}
+	return db, nil
+}
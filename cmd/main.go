@@ -1,78 +1,184 @@
-package main
-
-import (
-	"context"
-	"log"
-	"net/http"
-	"os"
-	"time"
-
-	"github.com/NicoJCastro/gocourse_course/internal/course"
-	"github.com/NicoJCastro/gocourse_course/pkg/bootstrap"
-	"github.com/NicoJCastro/gocourse_course/pkg/handler"
-
-	"github.com/joho/godotenv"
-)
-
-func main() {
-
-	_ = godotenv.Load(".env")
-	//logger
-	logger := bootstrap.InitLogger()
-
-	//db
-	db, err := bootstrap.DBConnection()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	pagLimitDef := os.Getenv("PAGINATION_LIMIT_DEFAUL")
-	if pagLimitDef == "" {
-		logger.Fatal("PAGINATION_LIMIT_DEFAUL is not set")
-	}
-
-	ctx := context.Background()
-
-	courseRepo := course.NewRepo(db, logger)
-	courseService := course.NewService(logger, courseRepo)
-	courseEndpoints := course.MakeEndpoint(courseService, course.Config{LimPageDef: pagLimitDef})
-
-	h := handler.NewCourseHTTPServer(ctx, courseEndpoints)
-
-	port := os.Getenv("PORT")
-	adress := "localhost:" + port
-
-	srv := &http.Server{
-		Handler:      accessControl(h),
-		Addr:         adress,
-		WriteTimeout: 5 * time.Second,
-		ReadTimeout:  5 * time.Second,
-	}
-
-	errCh := make(chan error)
-	go func() {
-		logger.Println("listen in ", adress)
-		errCh <- srv.ListenAndServe()
-	}()
-
-	err = <-errCh
-	if err != nil {
-		logger.Println("error: ", err)
-		os.Exit(1)
-	}
-
-}
-
-func accessControl(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, PUT, DELETE, OPTIONS, HEAD")
-		w.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, Cache-Control, X-Requested-With")
-
-		if r.Method == "OPTIONS" {
-			return
-		}
-
-		h.ServeHTTP(w, r)
-	})
-}
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+)
+
+// Exit codes estables para los runbooks: los scripts pueden distinguir un curso
+// inexistente o datos rechazados de una falla de infraestructura
+const (
+	exitOK       = 0
+	exitFailure  = 1 // error inesperado (base de datos, servicio de usuarios, etc.)
+	exitUsage    = 2 // comando, argumentos o flags inválidos
+	exitConfig   = 3 // la configuración no pasa la validación
+	exitNotFound = 4 // el curso no existe
+	exitRejected = 5 // datos inválidos, conflicto de horarios o curso requerido por otros
+)
+
+const usage = `Usage: course-api [command] [flags]
+
+Commands:
+  serve                              start the HTTP API (default)
+  migrate up|down|status             manage the database schema (down requires -yes)
+  migrate utc -from <zone> -yes      convert dates written before the upgrade to UTC (run once)
+  seed -file <fixture.json|yaml>     load demo courses from a fixture
+  courses list                       list courses
+  courses get <id>                   show a course
+  courses create -name <name> -start <date> -end <date>
+  courses delete <id> [-purge]       move a course to the trash (or purge it)
+  config check                       validate the configuration
+
+Courses and seed accept -tenant <id> to act on one organization's courses.
+
+Upgrading: the database connection now reads and writes dates in UTC (loc=UTC).
+If the server ran with a time zone other than UTC, stop it and run
+"migrate utc -from <that zone> -yes" once before starting the new version.
+
+Common flags:
+  -config <file>    YAML config file (default: CONFIG_FILE); env and .env override it
+  -output table|json
+  -verbose          write service logs to stderr
+
+Exit codes: 0 ok, 1 failure, 2 usage, 3 invalid config, 4 not found, 5 rejected
+`
+
+type (
+	// exitError fija el exit code de un error que no sale del servicio
+	exitError struct {
+		code int
+		err  error
+	}
+
+	// commonFlags son los flags que aceptan todos los subcomandos
+	commonFlags struct {
+		configFile string
+		output     string
+		verbose    bool
+	}
+)
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+func usageErrorf(format string, args ...interface{}) error {
+	return &exitError{code: exitUsage, err: fmt.Errorf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run ejecuta el subcomando; sin argumentos levanta el servidor como antes
+func run(args []string) int {
+	command := "serve"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "serve":
+		err = runServe(args)
+	case "migrate":
+		err = runMigrate(args)
+	case "seed":
+		err = runSeed(args)
+	case "courses":
+		err = runCourses(args)
+	case "config":
+		err = runConfig(args)
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return exitOK
+	default:
+		err = usageErrorf("unknown command %q", command)
+	}
+	return exitCode(err)
+}
+
+// exitCode informa el error en stderr y lo traduce al exit code
+func exitCode(err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	fmt.Fprintln(os.Stderr, "error:", err)
+
+	var exitErr *exitError
+	switch {
+	case errors.As(err, &exitErr):
+		if exitErr.code == exitUsage {
+			fmt.Fprintln(os.Stderr, "run 'course-api help' for usage")
+		}
+		return exitErr.code
+	case errors.Is(err, course.ErrNotFoundBase):
+		return exitNotFound
+	case course.IsInvalidInput(err), errors.Is(err, course.ErrScheduleConflictBase),
+		errors.Is(err, course.ErrCourseIsPrerequisite), errors.Is(err, course.ErrBatchRolledBack):
+		return exitRejected
+	}
+	return exitFailure
+}
+
+// newFlagSet arma los flags de un subcomando con los comunes ya registrados
+func newFlagSet(name string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	common := &commonFlags{}
+	fs.StringVar(&common.configFile, "config", "", "YAML config file (default: CONFIG_FILE)")
+	fs.StringVar(&common.output, "output", "table", "output format: table or json")
+	fs.BoolVar(&common.verbose, "verbose", false, "write service logs to stderr")
+	return fs, common
+}
+
+// parseFlags acepta flags antes y después de los argumentos posicionales
+// (ej: courses get <id> -output json) y devuelve los posicionales
+func parseFlags(fs *flag.FlagSet, common *commonFlags, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &exitError{code: exitUsage, err: err}
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if common.output != "table" && common.output != "json" {
+		return nil, usageErrorf("invalid -output %q, must be table or json", common.output)
+	}
+	return positional, nil
+}
+
+// loadConfig carga y valida la configuración; un error sale con exitConfig
+func loadConfig(common *commonFlags) (*config.Config, error) {
+	cfg, err := config.Load(common.configFile)
+	if err != nil {
+		return nil, &exitError{code: exitConfig, err: fmt.Errorf("invalid configuration:\n%w", err)}
+	}
+	return cfg, nil
+}
+
+// cliLogger descarta los logs del servicio salvo con -verbose, para no mezclarlos con la salida
+func cliLogger(common *commonFlags) *log.Logger {
+	if !common.verbose {
+		return log.New(io.Discard, "", 0)
+	}
+	return log.New(os.Stderr, "course-api ", log.LstdFlags|log.Lshortfile)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+)
+
+// quiet descarta stdout y stderr mientras corre la prueba
+func quiet(t *testing.T) {
+	t.Helper()
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = devNull, devNull
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		devNull.Close()
+	})
+}
+
+func TestRun(t *testing.T) {
+	quiet(t)
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(valid, []byte("database:\n  name: courses\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid, []byte("database:\n  name: courses\nsearch:\n  backend: elastic\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"help", []string{"help"}, exitOK},
+		{"flag help", []string{"config", "-h"}, exitOK},
+		{"unknown command", []string{"deploy"}, exitUsage},
+		{"unknown flag", []string{"config", "check", "-force"}, exitUsage},
+		{"invalid output", []string{"config", "check", "-output", "xml"}, exitUsage},
+		{"missing action", []string{"courses"}, exitUsage},
+		{"missing argument", []string{"courses", "get"}, exitUsage},
+		{"invalid filter", []string{"courses", "list", "-deleted", "all"}, exitUsage},
+		{"config check", []string{"config", "check", "-config", valid, "-output", "json"}, exitOK},
+		{"invalid config", []string{"config", "check", "-config", invalid}, exitConfig},
+		{"invalid config before the database", []string{"courses", "get", "42", "-config", invalid}, exitConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := run(tt.args); got != tt.want {
+				t.Errorf("run(%q) = %d, want %d", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	quiet(t)
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, exitOK},
+		{"help", flag.ErrHelp, exitOK},
+		{"not found", course.NewErrNotFound("42"), exitNotFound},
+		{"invalid input", fmt.Errorf("%w: %w", course.ErrFailedToCreateCourse, course.ErrInvalidStartDate), exitRejected},
+		{"schedule conflict", &course.ErrScheduleConflict{}, exitRejected},
+		{"prerequisite", course.ErrCourseIsPrerequisite, exitRejected},
+		{"batch rolled back", fmt.Errorf("seed: %w", course.ErrBatchRolledBack), exitRejected},
+		{"explicit code", fmt.Errorf("migrate: %w", &exitError{code: exitFailure, err: errors.New("2 tables pending migration")}), exitFailure},
+		{"unexpected", errors.New("connection refused"), exitFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
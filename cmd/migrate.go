@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/pkg/bootstrap"
+)
+
+// runMigrate: up crea o actualiza las tablas, down las borra (pide -yes) y status
+// muestra qué tablas o columnas faltan; status sale con exitFailure si hay pendientes.
+// utc convierte una sola vez las fechas escritas con loc=Local (ver bootstrap.ConvertToUTC).
+func runMigrate(args []string) error {
+	fs, common := newFlagSet("migrate")
+	yes := fs.Bool("yes", false, "confirm migrate down (drops every table and its data) or utc (rewrites every date)")
+	from := fs.String("from", "", "migrate utc: IANA time zone the server used before upgrading (ej: America/Argentina/Buenos_Aires)")
+	positional, err := parseFlags(fs, common, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageErrorf("migrate requires one of: up, down, status, utc")
+	}
+	action := positional[0]
+	if action != "up" && action != "down" && action != "status" && action != "utc" {
+		return usageErrorf("unknown migrate action %q, must be up, down, status or utc", action)
+	}
+	if action == "down" && !*yes {
+		return usageErrorf("migrate down drops every table, confirm with -yes")
+	}
+	var fromLoc *time.Location
+	if action == "utc" {
+		if *from == "" {
+			return usageErrorf("migrate utc requires -from <time zone>")
+		}
+		if fromLoc, err = time.LoadLocation(*from); err != nil {
+			return usageErrorf("invalid -from time zone %q", *from)
+		}
+		if !*yes {
+			return usageErrorf("migrate utc rewrites every stored date and must run only once, confirm with -yes")
+		}
+	}
+
+	cfg, err := loadConfig(common)
+	if err != nil {
+		return err
+	}
+	// El esquema lo maneja este comando, no DATABASE_MIGRATE
+	cfg.Database.Migrate = false
+	db, err := openDB(cfg, common)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "utc":
+		conversions, err := bootstrap.ConvertToUTC(db, fromLoc)
+		if err != nil {
+			return err
+		}
+		t := table{headers: []string{"TABLE", "ROWS"}}
+		for _, conversion := range conversions {
+			t.rows = append(t.rows, []string{conversion.Table, strconv.Itoa(conversion.Rows)})
+		}
+		return printOutput(common, conversions, t)
+	case "up":
+		if err := bootstrap.Migrate(db); err != nil {
+			return err
+		}
+	case "down":
+		if err := bootstrap.MigrateDown(db); err != nil {
+			return err
+		}
+	}
+
+	statuses, err := bootstrap.MigrationStatus(db)
+	if err != nil {
+		return err
+	}
+	t := table{headers: []string{"TABLE", "EXISTS", "MISSING COLUMNS"}}
+	pending := 0
+	for _, status := range statuses {
+		if !status.Exists || len(status.MissingColumns) > 0 {
+			pending++
+		}
+		t.rows = append(t.rows, []string{status.Table, strconv.FormatBool(status.Exists), orDash(strings.Join(status.MissingColumns, ","))})
+	}
+	if err := printOutput(common, statuses, t); err != nil {
+		return err
+	}
+	if action == "status" && pending > 0 {
+		return &exitError{code: exitFailure, err: fmt.Errorf("%d tables pending migration", pending)}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+)
+
+// table es una salida tabular; en JSON se escribe value tal cual
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+// printOutput escribe value como JSON indentado o como tabla alineada
+func printOutput(common *commonFlags, value interface{}, t table) error {
+	if common.output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func coursesTable(courses ...course.Course) table {
+	t := table{headers: []string{"ID", "NAME", "START", "END", "TIMEZONE", "CATEGORY", "TAGS", "DELETED"}}
+	for _, c := range courses {
+		category := "-"
+		if c.Category != nil {
+			category = c.Category.Name
+		}
+		tags := make([]string, 0, len(c.Tags))
+		for _, tag := range c.Tags {
+			tags = append(tags, tag.Name)
+		}
+		deleted := "-"
+		if c.DeletedAt.Valid {
+			deleted = c.DeletedAt.Time.Format(time.RFC3339)
+		}
+		t.rows = append(t.rows, []string{
+			c.ID,
+			c.Name,
+			c.StartDate.Format(time.RFC3339),
+			c.EndDate.Format(time.RFC3339),
+			c.Timezone,
+			category,
+			orDash(strings.Join(tags, ",")),
+			deleted,
+		})
+	}
+	return t
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"gopkg.in/yaml.v3"
+)
+
+// seedCourse es un curso del fixture; los campos son los de POST /courses
+type seedCourse struct {
+	Name          string   `json:"name" yaml:"name"`
+	StartDate     string   `json:"start_date" yaml:"start_date"`
+	EndDate       string   `json:"end_date" yaml:"end_date"`
+	Timezone      string   `json:"timezone" yaml:"timezone"`
+	CategoryID    *string  `json:"category_id" yaml:"category_id"`
+	Tags          []string `json:"tags" yaml:"tags"`
+	InstructorIDs []string `json:"instructor_ids" yaml:"instructor_ids"`
+}
+
+// runSeed carga los cursos de un fixture JSON o YAML (una lista de cursos) en un
+// solo lote: atómico por defecto, o -best-effort para omitir los que fallan
+func runSeed(args []string) error {
+	fs, common := newFlagSet("seed")
+	scope := newTenantFlag(fs)
+	file := fs.String("file", "", "fixture file, .json or .yaml (required)")
+	bestEffort := fs.Bool("best-effort", false, "skip invalid courses instead of rolling back the whole seed")
+	actor := fs.String("actor", audit.SystemActor, "user ID recorded in the audit log")
+	positional, err := parseFlags(fs, common, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 || *file == "" {
+		return usageErrorf("seed requires -file")
+	}
+
+	courses, err := readFixture(*file)
+	if err != nil {
+		return &exitError{code: exitUsage, err: err}
+	}
+	items := make([]course.BatchCreateItem, len(courses))
+	for i, c := range courses {
+		items[i] = course.BatchCreateItem{
+			Name:           c.Name,
+			StartDate:      c.StartDate,
+			EndDate:        c.EndDate,
+			Timezone:       c.Timezone,
+			Classification: course.Classification{CategoryID: c.CategoryID, Tags: c.Tags},
+			InstructorIDs:  c.InstructorIDs,
+		}
+	}
+	mode := course.BatchAtomic
+	if *bestEffort {
+		mode = course.BatchBestEffort
+	}
+
+	svc, err := openCourses(common)
+	if err != nil {
+		return err
+	}
+	results, batchErr := svc.CreateBatch(cliContext(*actor, scope), items, mode)
+	if results == nil {
+		return batchErr
+	}
+
+	t := table{headers: []string{"INDEX", "STATUS", "ID", "NAME", "ERROR"}}
+	failed := 0
+	for _, result := range results {
+		if result.Status != course.BatchStatusCreated {
+			failed++
+		}
+		t.rows = append(t.rows, []string{strconv.Itoa(result.Index), string(result.Status), orDash(result.ID), courses[result.Index].Name, orDash(result.Error)})
+	}
+	if err := printOutput(common, results, t); err != nil {
+		return err
+	}
+	if batchErr != nil {
+		return &exitError{code: exitRejected, err: batchErr}
+	}
+	if failed > 0 {
+		return &exitError{code: exitRejected, err: fmt.Errorf("%d of %d courses failed", failed, len(results))}
+	}
+	return nil
+}
+
+func readFixture(path string) ([]seedCourse, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var courses []seedCourse
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		err = json.Unmarshal(data, &courses)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &courses)
+	default:
+		return nil, fmt.Errorf("unsupported fixture format %q, must be .json, .yaml or .yml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing fixture %s: %w", path, err)
+	}
+	if len(courses) == 0 {
+		return nil, errors.New("fixture has no courses")
+	}
+	return courses, nil
+}
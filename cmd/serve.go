@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/catalog"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/outbox"
+	"github.com/NicoJCastro/gocourse_course/internal/session"
+	"github.com/NicoJCastro/gocourse_course/internal/webhook"
+	"github.com/NicoJCastro/gocourse_course/pkg/bootstrap"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/NicoJCastro/gocourse_course/pkg/handler"
+	"github.com/NicoJCastro/gocourse_course/pkg/server"
+	"github.com/NicoJCastro/gocourse_course/pkg/user"
+)
+
+// runServe levanta la API HTTP con sus procesos en segundo plano
+func runServe(args []string) error {
+	fs, common := newFlagSet("serve")
+	if _, err := parseFlags(fs, common, args); err != nil {
+		return err
+	}
+
+	//logger
+	logger := bootstrap.InitLogger()
+
+	// config: valores por defecto, YAML opcional (-config o CONFIG_FILE) y entorno/.env
+	cfg, err := loadConfig(common)
+	if err != nil {
+		return err
+	}
+
+	//db
+	db, err := bootstrap.DBConnection(cfg.Database)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	// el repositorio reintenta los errores transitorios de MySQL y, si la base no
+	// responde, abre el circuito y la API contesta 503 con Retry-After
+	courseRepo := course.NewResilientRepo(logger, course.NewRepo(db, logger), course.DefaultResilienceConfig())
+
+	// búsqueda: FULLTEXT de MySQL por defecto, SEARCH_BACKEND=bleve usa un índice embebido
+	var searcher course.Searcher
+	var bleveSearcher *course.BleveSearcher
+	switch cfg.Search.Backend {
+	case "mysql":
+		searcher = course.NewMySQLSearcher(logger, db)
+	case "bleve":
+		if bleveSearcher, err = course.NewBleveSearcher(logger); err != nil {
+			return fmt.Errorf("error creating search index: %w", err)
+		}
+		defer bleveSearcher.Close()
+		searcher = bleveSearcher
+	}
+
+	courseService, err := newCourseService(logger, cfg, courseRepo, searcher)
+	if err != nil {
+		return err
+	}
+	if bleveSearcher != nil {
+		go course.NewSearchIndexer(logger, courseService, bleveSearcher, time.Second).Run(ctx)
+	}
+	courseEndpoints := course.MakeEndpoint(courseService, course.Config{
+		Pagination:      cfg.Pagination,
+		UpsertOnReplace: cfg.Courses.UpsertOnReplace,
+		AdminToken:      cfg.Courses.AdminToken,
+	})
+
+	// purge de la papelera: sólo se activa si TRASH_RETENTION está definido (ej: 720h)
+	if cfg.Courses.TrashRetention > 0 {
+		go course.NewPurgeJob(logger, courseService, cfg.Courses.TrashRetention, cfg.Courses.TrashPurgeInterval).Run(ctx)
+	}
+
+	// catálogo: tags y categorías de cursos
+	catalogEndpoints := catalog.MakeEndpoint(
+		catalog.NewService(logger, catalog.NewRepo(db, logger)),
+		catalog.Config{Pagination: cfg.Pagination},
+	)
+
+	// sesiones: sub-recurso /courses/{id}/sessions
+	sessionEndpoints := session.MakeEndpoint(
+		session.NewService(logger, session.NewRepo(db, logger)),
+		session.Config{Pagination: cfg.Pagination},
+	)
+
+	// webhooks: el relay del outbox crea los envíos y el dispatcher los entrega
+	webhookRepo := webhook.NewRepo(db, logger)
+	webhookService := webhook.NewService(logger, webhookRepo, []string{
+		course.EventCourseCreated,
+		course.EventCourseRescheduled,
+		course.EventCourseDeleted,
+	}, cfg.Webhooks.AllowPrivateNetworks)
+	webhookEndpoints := webhook.MakeEndpoint(webhookService, webhook.Config{Pagination: cfg.Pagination})
+	dispatcherConfig := webhook.DefaultDispatcherConfig()
+	dispatcherConfig.AllowPrivateNetworks = cfg.Webhooks.AllowPrivateNetworks
+	go webhook.NewDispatcher(logger, webhookRepo, dispatcherConfig).Run(ctx)
+
+	// relay del outbox: además de los webhooks publica en NATS si NATS_URL está definido.
+	// El orden de los eventos sólo se garantiza con un único relay (ver outbox.Relay).
+	publishers := outbox.MultiPublisher{webhook.NewPublisher(webhookService)}
+	if cfg.NATS.URL != "" {
+		publisher, err := outbox.NewNATSPublisher(cfg.NATS.URL, "gocourse")
+		if err != nil {
+			return fmt.Errorf("error connecting to NATS: %w", err)
+		}
+		publishers = append(publishers, publisher)
+	}
+	defer publishers.Close()
+	relay := outbox.NewRelay(logger, outbox.NewRepo(db, logger), publishers, time.Second, 100)
+	go relay.Run(ctx)
+
+	api := newAPI(cfg, apiHandlers{
+		courses:  handler.NewCourseHTTPServer(ctx, courseEndpoints),
+		sessions: handler.NewSessionHTTPServer(ctx, sessionEndpoints),
+		catalog:  handler.NewCatalogHTTPServer(ctx, catalogEndpoints),
+		webhooks: handler.NewWebhookHTTPServer(ctx, webhookEndpoints),
+	})
+	srv, err := server.New(logger, cfg.Server, api)
+	if err != nil {
+		return err
+	}
+	return srv.ListenAndServe(ctx)
+}
+
+// apiHandlers son los routers de cada recurso de la API
+type apiHandlers struct {
+	courses  http.Handler
+	sessions http.Handler
+	catalog  http.Handler
+	webhooks http.Handler
+}
+
+// newAPI monta los routers en un único mux. Todo pasa por WithTenant: sin tenant en
+// el contexto los repositorios no filtran, así que ninguna ruta puede quedar afuera.
+func newAPI(cfg *config.Config, handlers apiHandlers) http.Handler {
+	// los webhooks son administración: piden X-Admin-Token como el purge
+	webhookHandler := handler.RequireAdmin(cfg.Courses.AdminToken, handlers.webhooks)
+	router := http.NewServeMux()
+	router.Handle("/webhooks", webhookHandler)
+	router.Handle("/webhooks/", webhookHandler)
+	router.Handle("/tags", handlers.catalog)
+	router.Handle("/tags/", handlers.catalog)
+	router.Handle("/categories", handlers.catalog)
+	router.Handle("/categories/", handlers.catalog)
+	// Los patrones con comodines tienen prioridad sobre "/", que atiende el resto de /courses
+	router.Handle("/courses/{id}/sessions", handlers.sessions)
+	router.Handle("/courses/{id}/sessions/", handlers.sessions)
+	router.Handle("/", handlers.courses)
+
+	// multi-tenancy: cada request queda ligada a la organización del JWT o del header;
+	// Accept-Language elige la traducción de los cursos
+	api := handler.WithLocale(cfg.Locales, handler.WithTenant(cfg.Tenancy, router))
+	return accessControl(cfg.Tenancy.Header, api)
+}
+
+// newCourseService arma el servicio de cursos igual para la API y para el CLI
+func newCourseService(logger *log.Logger, cfg *config.Config, repo course.Repository, searcher course.Searcher) (course.Service, error) {
+	// borrar un curso que otros requieren: restrict (por defecto) lo rechaza, cascade lo quita de sus prerequisitos
+	prerequisitePolicy, err := course.ParsePrerequisitePolicy(cfg.Courses.PrerequisiteDeletePolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	// servicio de usuarios: sin USER_SERVICE_URL no se verifican instructores ni creadores
+	var users user.Client
+	if cfg.UserService.URL != "" {
+		userConfig := user.DefaultConfig(cfg.UserService.URL)
+		userConfig.Timeout = cfg.UserService.Timeout
+		users = user.New(logger, userConfig)
+	}
+	return course.NewService(logger, repo, searcher, prerequisitePolicy, users, cfg.Locales), nil
+}
+
+func accessControl(tenantHeader string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, PUT, DELETE, OPTIONS, HEAD")
+		w.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, Cache-Control, X-Requested-With, X-Admin-Token, X-User-ID, X-Request-ID, Last-Event-ID, "+tenantHeader)
+
+		if r.Method == "OPTIONS" {
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
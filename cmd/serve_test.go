@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/catalog"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/session"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/NicoJCastro/gocourse_course/internal/webhook"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/NicoJCastro/gocourse_course/pkg/handler"
+	"github.com/gorilla/mux"
+)
+
+// probe reemplaza al router de un recurso: anota el tenant con el que llegó cada request
+type probe struct {
+	tenants map[string]string
+}
+
+func (p *probe) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := tenant.FromContext(r.Context())
+	if ok {
+		p.tenants[r.Method+" "+r.URL.Path] = tenantID
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TestEveryRouteHasTenant recorre las rutas de cada router y verifica que todas
+// lleguen con el tenant del header: sin él los repositorios no filtran por tenant
+func TestEveryRouteHasTenant(t *testing.T) {
+	ctx := context.Background()
+	routers := map[string]http.Handler{
+		"courses":  handler.NewCourseHTTPServer(ctx, course.Endpoint{}),
+		"sessions": handler.NewSessionHTTPServer(ctx, session.Endpoint{}),
+		"catalog":  handler.NewCatalogHTTPServer(ctx, catalog.Endpoint{}),
+		"webhooks": handler.NewWebhookHTTPServer(ctx, webhook.Endpoint{}),
+	}
+	probes := map[string]*probe{}
+	for name := range routers {
+		probes[name] = &probe{tenants: map[string]string{}}
+	}
+
+	cfg := config.Default()
+	cfg.Courses.AdminToken = "secret"
+	api := newAPI(&cfg, apiHandlers{
+		courses:  probes["courses"],
+		sessions: probes["sessions"],
+		catalog:  probes["catalog"],
+		webhooks: probes["webhooks"],
+	})
+
+	variable := regexp.MustCompile(`\{[^}]+\}`)
+	for name, router := range routers {
+		err := router.(*mux.Router).Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+			template, err := route.GetPathTemplate()
+			if err != nil {
+				return err
+			}
+			methods, err := route.GetMethods()
+			if err != nil {
+				return err
+			}
+			path := variable.ReplaceAllString(template, "0b6f3c1e-1d2a-4e5f-9a8b-7c6d5e4f3a2b")
+
+			for _, method := range methods {
+				r := httptest.NewRequest(method, path, nil)
+				r.Header.Set(cfg.Tenancy.Header, "tenant-a")
+				r.Header.Set(handler.HeaderAdminToken, "secret")
+				api.ServeHTTP(httptest.NewRecorder(), r)
+
+				if got := probes[name].tenants[method+" "+path]; got != "tenant-a" {
+					t.Errorf("%s %s reached the %s router with tenant %q, want tenant-a", method, template, name, got)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	// Action es el tipo de mutación registrada
+	Action string
+
+	// Entry es un registro de auditoría con el estado antes y después del cambio
+	Entry struct {
+		ID         string          `json:"id" gorm:"type:char(36);not null;primary_key"`
+		TenantID   string          `json:"-" gorm:"type:varchar(64);not null;default:'';index"`
+		EntityType string          `json:"entity_type" gorm:"type:varchar(30);not null;index:idx_audit_entity,priority:1;index:idx_audit_stream,priority:1;index:idx_audit_seq,priority:1"`
+		EntityID   string          `json:"entity_id" gorm:"type:char(36);not null;index:idx_audit_entity,priority:2"`
+		Action     Action          `json:"action" gorm:"type:varchar(20);not null"`
+		Actor      string          `json:"actor" gorm:"type:varchar(100);not null"`
+		RequestID  string          `json:"request_id" gorm:"type:varchar(64)"`
+		Before     json.RawMessage `json:"before" gorm:"type:json"`
+		After      json.RawMessage `json:"after" gorm:"type:json"`
+		Changes    json.RawMessage `json:"changes" gorm:"type:json"`
+		CreatedAt  time.Time       `json:"created_at" gorm:"index:idx_audit_entity,priority:3;index:idx_audit_stream,priority:2"`
+		// Seq ordena los registros de un tipo de entidad en el orden en que se confirmaron (ver Create)
+		Seq int64 `json:"-" gorm:"not null;default:0;index:idx_audit_seq,priority:2"`
+	}
+
+	// Sequence es el último Seq asignado a un tipo de entidad
+	Sequence struct {
+		EntityType string `gorm:"type:varchar(30);not null;primary_key"`
+		LastSeq    int64  `gorm:"not null"`
+	}
+
+	// Change es el valor anterior y nuevo de un campo
+	Change struct {
+		From interface{} `json:"from"`
+		To   interface{} `json:"to"`
+	}
+)
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+	ActionPurge   Action = "purge"
+)
+
+func (Entry) TableName() string {
+	return "audit_entries"
+}
+
+func (Sequence) TableName() string {
+	return "audit_sequences"
+}
+
+func (e *Entry) BeforeCreate(tx *gorm.DB) (err error) {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return
+}
+
+// NewEntry arma un registro con el tenant, el actor y el request ID del contexto. before o after
+// pueden ser nil (por ejemplo en un alta o en una baja).
+func NewEntry(ctx context.Context, entityType, entityID string, action Action, before, after interface{}) (*Entry, error) {
+	beforeMap, beforeJSON, err := toMap(before)
+	if err != nil {
+		return nil, err
+	}
+	afterMap, afterJSON, err := toMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := json.Marshal(diff(beforeMap, afterMap))
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID, _ := tenant.FromContext(ctx)
+	return &Entry{
+		TenantID:   tenantID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Actor:      ActorFrom(ctx),
+		RequestID:  RequestIDFrom(ctx),
+		Before:     beforeJSON,
+		After:      afterJSON,
+		Changes:    changes,
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+func toMap(v interface{}) (map[string]interface{}, json.RawMessage, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil, nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, nil, err
+	}
+	return m, raw, nil
+}
+
+// diff compara los campos de primer nivel de ambas representaciones JSON
+func diff(before, after map[string]interface{}) map[string]Change {
+	changes := map[string]Change{}
+	for k, v := range after {
+		if old, ok := before[k]; !ok || !reflect.DeepEqual(old, v) {
+			changes[k] = Change{From: before[k], To: v}
+		}
+	}
+	for k, v := range before {
+		if _, ok := after[k]; !ok {
+			changes[k] = Change{From: v, To: nil}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,35 @@
+package audit
+
+import "context"
+
+type contextKey string
+
+const (
+	actorKey     contextKey = "audit_actor"
+	requestIDKey contextKey = "audit_request_id"
+
+	// AnonymousActor se usa cuando la request no identifica a quién hace el cambio
+	AnonymousActor = "anonymous"
+	// SystemActor identifica los cambios hechos por procesos en segundo plano
+	SystemActor = "system"
+)
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
@@ -0,0 +1,180 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	Repository interface {
+		Create(ctx context.Context, entries ...*Entry) error
+		GetByEntity(ctx context.Context, entityType, entityID string, offset, limit int) ([]Entry, error)
+		CountByEntity(ctx context.Context, entityType, entityID string) (int64, error)
+		// GetAfter devuelve los registros de un tipo de entidad con Seq mayor a seq, en el
+		// orden en que se confirmaron
+		GetAfter(ctx context.Context, entityType string, seq int64, limit int) ([]Entry, error)
+		// LastSeq devuelve el Seq más alto de un tipo de entidad (0 si no hay registros)
+		LastSeq(ctx context.Context, entityType string) (int64, error)
+		// SeqAt devuelve el Seq desde el que se ven los registros creados a partir de at
+		SeqAt(ctx context.Context, entityType string, at time.Time) (int64, error)
+		GetByID(ctx context.Context, id string) (*Entry, error)
+	}
+
+	repo struct {
+		db  *gorm.DB
+		log *log.Logger
+	}
+)
+
+// NewRepo crea el repositorio de auditoría. Si db es una transacción, los
+// registros se escriben dentro de ella.
+func NewRepo(db *gorm.DB, logger *log.Logger) Repository {
+	return &repo{
+		db:  db,
+		log: logger,
+	}
+}
+
+// Create asigna a cada registro el siguiente Seq de su tipo de entidad. El contador se
+// incrementa con un upsert que bloquea su fila hasta el commit: otra escritura del mismo
+// tipo espera, así que Seq sigue el orden de commit y el feed de cambios puede avanzar
+// por Seq sin perder transacciones que confirman tarde. A cambio, esas escrituras se
+// serializan desde el registro de auditoría (el último paso de la transacción) hasta el commit.
+func (r *repo) Create(ctx context.Context, entries ...*Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := assignSeq(tx, entries); err != nil {
+			return err
+		}
+		return tx.CreateInBatches(entries, 100).Error
+	})
+	if err != nil {
+		r.log.Printf("error: %v", err)
+		return err
+	}
+	return nil
+}
+
+func assignSeq(tx *gorm.DB, entries []*Entry) error {
+	counts := make(map[string]int64)
+	for _, entry := range entries {
+		counts[entry.EntityType]++
+	}
+	// Siempre en el mismo orden, para que dos lotes con varios tipos no se bloqueen entre sí
+	entityTypes := slices.Sorted(maps.Keys(counts))
+
+	next := make(map[string]int64, len(counts))
+	for _, entityType := range entityTypes {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "entity_type"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"last_seq": gorm.Expr("last_seq + ?", counts[entityType])}),
+		}).Create(&Sequence{EntityType: entityType, LastSeq: counts[entityType]}).Error
+		if err != nil {
+			return err
+		}
+		var seq Sequence
+		if err := tx.Where("entity_type = ?", entityType).Take(&seq).Error; err != nil {
+			return err
+		}
+		next[entityType] = seq.LastSeq - counts[entityType] + 1
+	}
+	for _, entry := range entries {
+		entry.Seq = next[entry.EntityType]
+		next[entry.EntityType]++
+	}
+	return nil
+}
+
+func (r *repo) GetByEntity(ctx context.Context, entityType, entityID string, offset, limit int) ([]Entry, error) {
+	var entries []Entry
+	result := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		// Seq desempata los registros con el mismo created_at para que las páginas no se pisen
+		Order("created_at desc, seq desc").
+		Limit(limit).Offset(offset).
+		Find(&entries)
+	if result.Error != nil {
+		r.log.Println("Error getting audit entries: ", result.Error)
+		return nil, result.Error
+	}
+	return entries, nil
+}
+
+func (r *repo) CountByEntity(ctx context.Context, entityType, entityID string) (int64, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&Entry{}).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Count(&count)
+	if result.Error != nil {
+		r.log.Println("Error counting audit entries: ", result.Error)
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (r *repo) GetAfter(ctx context.Context, entityType string, seq int64, limit int) ([]Entry, error) {
+	var entries []Entry
+	result := r.db.WithContext(ctx).
+		Where("entity_type = ? AND seq > ?", entityType, seq).
+		Order("seq asc").
+		Limit(limit).
+		Find(&entries)
+	if result.Error != nil {
+		r.log.Println("Error getting audit entries: ", result.Error)
+		return nil, result.Error
+	}
+	return entries, nil
+}
+
+func (r *repo) LastSeq(ctx context.Context, entityType string) (int64, error) {
+	var seq int64
+	result := r.db.WithContext(ctx).Model(&Entry{}).
+		Select("COALESCE(MAX(seq), 0)").
+		Where("entity_type = ?", entityType).
+		Scan(&seq)
+	if result.Error != nil {
+		r.log.Println("Error getting audit sequence: ", result.Error)
+		return 0, result.Error
+	}
+	return seq, nil
+}
+
+// SeqAt devuelve el Seq anterior al primer registro creado desde at; sin registros
+// posteriores es LastSeq. created_at no sigue el orden de commit, así que el corte es aproximado.
+func (r *repo) SeqAt(ctx context.Context, entityType string, at time.Time) (int64, error) {
+	var entries []Entry
+	result := r.db.WithContext(ctx).
+		Where("entity_type = ? AND created_at >= ?", entityType, at).
+		Order("created_at asc").
+		Limit(1).
+		Find(&entries)
+	if result.Error != nil {
+		r.log.Println("Error getting audit sequence: ", result.Error)
+		return 0, result.Error
+	}
+	if len(entries) == 0 {
+		return r.LastSeq(ctx, entityType)
+	}
+	return entries[0].Seq - 1, nil
+}
+
+func (r *repo) GetByID(ctx context.Context, id string) (*Entry, error) {
+	var entry Entry
+	result := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&entry)
+	if result.Error != nil {
+		r.log.Println("Error getting audit entry: ", result.Error)
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &entry, nil
+}
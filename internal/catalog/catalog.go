@@ -0,0 +1,102 @@
+package catalog
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	// Category es un nodo de la jerarquía de categorías; ParentID nil es una raíz
+	Category struct {
+		ID        string     `json:"id" gorm:"type:char(36);not null;primary_key"`
+		Name      string     `json:"name" gorm:"type:varchar(100);not null"`
+		ParentID  *string    `json:"parent_id" gorm:"type:char(36);index"`
+		Children  []Category `json:"children,omitempty" gorm:"-"`
+		CreatedAt *time.Time `json:"created_at"`
+		UpdatedAt *time.Time `json:"updated_at"`
+		TenantID  string     `json:"-" gorm:"type:varchar(64);not null;default:'';index"`
+	}
+
+	// Tag es una etiqueta libre; el nombre se guarda normalizado y es único en cada tenant
+	Tag struct {
+		ID        string     `json:"id" gorm:"type:char(36);not null;primary_key"`
+		Name      string     `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_tenant_name,priority:2"`
+		CreatedAt *time.Time `json:"created_at"`
+		UpdatedAt *time.Time `json:"updated_at"`
+		TenantID  string     `json:"-" gorm:"type:varchar(64);not null;default:'';uniqueIndex:idx_tags_tenant_name,priority:1"`
+	}
+
+	// CourseTag relaciona cursos y tags (muchos a muchos)
+	CourseTag struct {
+		CourseID string `gorm:"type:char(36);not null;primaryKey"`
+		TagID    string `gorm:"type:char(36);not null;primaryKey;index"`
+		TenantID string `gorm:"type:varchar(64);not null;default:'';index"`
+	}
+
+	// CourseCategory asigna a lo sumo una categoría por curso
+	CourseCategory struct {
+		CourseID   string `gorm:"type:char(36);not null;primaryKey"`
+		CategoryID string `gorm:"type:char(36);not null;index"`
+		TenantID   string `gorm:"type:varchar(64);not null;default:'';index"`
+	}
+)
+
+const (
+	MaxTagLength      = 50
+	MaxCategoryLength = 100
+	// MaxCategoryDepth limita la jerarquía y el recorrido al validar ciclos
+	MaxCategoryDepth = 10
+)
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+func (CourseTag) TableName() string {
+	return "course_tags"
+}
+
+func (CourseCategory) TableName() string {
+	return "course_categories"
+}
+
+func (c *Category) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return
+}
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return
+}
+
+// NormalizeTag deja los tags en minúsculas y sin espacios alrededor
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// NormalizeTags normaliza y elimina duplicados manteniendo el orden
+func NormalizeTags(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		name = NormalizeTag(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		tags = append(tags, name)
+	}
+	return tags
+}
@@ -0,0 +1,71 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error es un error de la API con un código estable, el que reciben los clientes
+type Error struct {
+	code    string
+	message string
+}
+
+func newError(code, message string) *Error {
+	return &Error{code: code, message: message}
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Code() string {
+	return e.code
+}
+
+var ErrInvalidRequestType = newError("invalid_request_type", "invalid request type")
+var ErrIDRequired = newError("id_required", "id is required")
+var ErrNameRequired = newError("name_required", "name is required")
+var ErrNameTooLong = newError("name_too_long", "name is too long")
+var ErrAtLeastOneFieldRequired = newError("at_least_one_field_required", "at least one field is required")
+var ErrTagExists = newError("tag_exists", "a tag with that name already exists")
+var ErrParentNotFound = newError("parent_category_not_found", "parent category not found")
+var ErrCategoryCycle = newError("category_cycle", "a category cannot be moved under itself or one of its descendants")
+var ErrCategoryTooDeep = newError("category_too_deep", "category hierarchy is too deep")
+var ErrCategoryHasChildren = newError("category_has_children", "category has subcategories, move or delete them first")
+var ErrFailedToSaveTag = newError("failed_to_save_tag", "failed to save tag")
+var ErrFailedToGetTags = newError("failed_to_get_tags", "failed to get tags")
+var ErrFailedToDeleteTag = newError("failed_to_delete_tag", "failed to delete tag")
+var ErrFailedToSaveCategory = newError("failed_to_save_category", "failed to save category")
+var ErrFailedToGetCategories = newError("failed_to_get_categories", "failed to get categories")
+var ErrFailedToDeleteCategory = newError("failed_to_delete_category", "failed to delete category")
+var ErrFailedToGenerateMetadata = newError("metadata_error", "error generating metadata")
+
+// ErrNotFound indica que no existe el tag o la categoría pedida
+type ErrNotFound struct {
+	Resource string
+	ID       string
+}
+
+func (e *ErrNotFound) Error() string {
+	return fmt.Sprintf("%s with ID %s not found", e.Resource, e.ID)
+}
+
+// Code es "tag_not_found" o "category_not_found"
+func (e *ErrNotFound) Code() string {
+	return e.Resource + "_not_found"
+}
+
+func (e *ErrNotFound) Args() []any {
+	return []any{e.ID}
+}
+
+func (e *ErrNotFound) Unwrap() error {
+	return ErrNotFoundBase
+}
+
+func NewErrNotFound(resource, id string) *ErrNotFound {
+	return &ErrNotFound{Resource: resource, ID: id}
+}
+
+var ErrNotFoundBase = errors.New("catalog resource not found")
@@ -0,0 +1,289 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NicoJCastro/go_lib_response/response"
+	"github.com/NicoJCastro/gocourse_course/pkg/apierror"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/NicoJCastro/gocourse_meta/meta"
+)
+
+type (
+	Controller func(ctx context.Context, request interface{}) (interface{}, error)
+
+	Endpoint struct {
+		CreateTag Controller
+		GetTag    Controller
+		GetTags   Controller
+		UpdateTag Controller
+		DeleteTag Controller
+
+		CreateCategory Controller
+		GetCategory    Controller
+		GetCategories  Controller
+		UpdateCategory Controller
+		DeleteCategory Controller
+	}
+
+	CreateTagReq struct {
+		Name string `json:"name"`
+	}
+
+	UpdateTagReq struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	CreateCategoryReq struct {
+		Name     string  `json:"name"`
+		ParentID *string `json:"parent_id"`
+	}
+
+	// UpdateCategoryReq: parent_id "" mueve la categoría a la raíz
+	UpdateCategoryReq struct {
+		ID       string  `json:"id"`
+		Name     *string `json:"name"`
+		ParentID *string `json:"parent_id"`
+	}
+
+	GetCategoriesReq struct {
+		// ParentID nil lista todas, "" sólo las raíces
+		ParentID *string `json:"parent_id"`
+		Limit    int     `json:"limit"`
+		Page     int     `json:"page"`
+	}
+
+	GetReq struct {
+		ID string `json:"id"`
+	}
+
+	GetAllReq struct {
+		Limit int `json:"limit"`
+		Page  int `json:"page"`
+	}
+
+	DeleteReq struct {
+		ID string `json:"id"`
+	}
+
+	Config struct {
+		Pagination config.Pagination
+	}
+)
+
+func MakeEndpoint(s Service, config Config) Endpoint {
+	return Endpoint{
+		CreateTag: makeCreateTagEndpoint(s),
+		GetTag:    makeGetTagEndpoint(s),
+		GetTags:   makeGetTagsEndpoint(s, config),
+		UpdateTag: makeUpdateTagEndpoint(s),
+		DeleteTag: makeDeleteTagEndpoint(s),
+
+		CreateCategory: makeCreateCategoryEndpoint(s),
+		GetCategory:    makeGetCategoryEndpoint(s),
+		GetCategories:  makeGetCategoriesEndpoint(s, config),
+		UpdateCategory: makeUpdateCategoryEndpoint(s),
+		DeleteCategory: makeDeleteCategoryEndpoint(s),
+	}
+}
+
+func makeCreateTagEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(CreateTagReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		tag, err := s.CreateTag(ctx, req.Name)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.Created("Tag created successfully", tag, nil), nil
+	}
+}
+
+func makeGetTagEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		tag, err := s.GetTag(ctx, req.ID)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Tag retrieved successfully", tag, nil), nil
+	}
+}
+
+func makeGetTagsEndpoint(s Service, config Config) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetAllReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+
+		count, err := s.CountTags(ctx)
+		if err != nil {
+			return nil, apierror.InternalServerError(err)
+		}
+		metaData, err := newMeta(req.Page, req.Limit, count, config)
+		if err != nil {
+			return nil, apierror.InternalServerError(fmt.Errorf("%w: %w", ErrFailedToGenerateMetadata, err))
+		}
+
+		tags, err := s.GetTags(ctx, metaData.Offset(), metaData.Limit())
+		if err != nil {
+			return nil, apierror.InternalServerError(err)
+		}
+		return response.OK("Tags retrieved successfully", tags, metaData), nil
+	}
+}
+
+func makeUpdateTagEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(UpdateTagReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		tag, err := s.UpdateTag(ctx, req.ID, req.Name)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Tag updated successfully", tag, nil), nil
+	}
+}
+
+func makeDeleteTagEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(DeleteReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if err := s.DeleteTag(ctx, req.ID); err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Tag deleted successfully", nil, nil), nil
+	}
+}
+
+func makeCreateCategoryEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(CreateCategoryReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		category, err := s.CreateCategory(ctx, req.Name, req.ParentID)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.Created("Category created successfully", category, nil), nil
+	}
+}
+
+func makeGetCategoryEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		category, err := s.GetCategory(ctx, req.ID)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Category retrieved successfully", category, nil), nil
+	}
+}
+
+func makeGetCategoriesEndpoint(s Service, config Config) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetCategoriesReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+
+		count, err := s.CountCategories(ctx, req.ParentID)
+		if err != nil {
+			return nil, apierror.InternalServerError(err)
+		}
+		metaData, err := newMeta(req.Page, req.Limit, count, config)
+		if err != nil {
+			return nil, apierror.InternalServerError(fmt.Errorf("%w: %w", ErrFailedToGenerateMetadata, err))
+		}
+
+		categories, err := s.GetCategories(ctx, req.ParentID, metaData.Offset(), metaData.Limit())
+		if err != nil {
+			return nil, apierror.InternalServerError(err)
+		}
+		return response.OK("Categories retrieved successfully", categories, metaData), nil
+	}
+}
+
+func makeUpdateCategoryEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(UpdateCategoryReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if req.Name == nil && req.ParentID == nil {
+			return nil, apierror.BadRequest(ErrAtLeastOneFieldRequired)
+		}
+		category, err := s.UpdateCategory(ctx, req.ID, req.Name, req.ParentID)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Category updated successfully", category, nil), nil
+	}
+}
+
+func makeDeleteCategoryEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(DeleteReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if err := s.DeleteCategory(ctx, req.ID); err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Category deleted successfully", nil, nil), nil
+	}
+}
+
+func newMeta(page, limit int, count int64, config Config) (*meta.Meta, error) {
+	return config.Pagination.Meta(page, limit, int(count))
+}
+
+// errorResponse traduce los errores del servicio a respuestas HTTP
+func errorResponse(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFoundBase):
+		return apierror.NotFound(err)
+	case errors.Is(err, ErrNameRequired), errors.Is(err, ErrNameTooLong),
+		errors.Is(err, ErrCategoryTooDeep), errors.Is(err, ErrParentNotFound):
+		return apierror.BadRequest(err)
+	case errors.Is(err, ErrTagExists), errors.Is(err, ErrCategoryCycle),
+		errors.Is(err, ErrCategoryHasChildren):
+		return apierror.Conflict(err)
+	}
+	return apierror.InternalServerError(err)
+}
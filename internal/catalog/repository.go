@@ -0,0 +1,345 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	Repository interface {
+		CreateTag(ctx context.Context, tag *Tag) error
+		GetTag(ctx context.Context, id string) (*Tag, error)
+		GetTagByName(ctx context.Context, name string) (*Tag, error)
+		GetTags(ctx context.Context, offset, limit int) ([]Tag, error)
+		CountTags(ctx context.Context) (int64, error)
+		SaveTag(ctx context.Context, tag *Tag) error
+		// DeleteTag también quita el tag de todos los cursos
+		DeleteTag(ctx context.Context, id string) error
+		// EnsureTags crea los tags que no existan y devuelve todos los pedidos
+		EnsureTags(ctx context.Context, names []string) ([]Tag, error)
+
+		CreateCategory(ctx context.Context, category *Category) error
+		GetCategory(ctx context.Context, id string) (*Category, error)
+		GetCategories(ctx context.Context, parentID *string, offset, limit int) ([]Category, error)
+		CountCategories(ctx context.Context, parentID *string) (int64, error)
+		SaveCategory(ctx context.Context, category *Category) error
+		// DeleteCategory deja sin categoría a los cursos que la tenían
+		DeleteCategory(ctx context.Context, id string) error
+
+		SetCourseTags(ctx context.Context, courseID string, tagIDs []string) error
+		SetCourseCategory(ctx context.Context, courseID string, categoryID *string) error
+		// TagsByCourse y CategoriesByCourse cargan en una sola consulta los datos de varios cursos
+		TagsByCourse(ctx context.Context, courseIDs []string) (map[string][]Tag, error)
+		CategoriesByCourse(ctx context.Context, courseIDs []string) (map[string]*Category, error)
+		// RemoveCourses borra las relaciones de cursos eliminados definitivamente
+		RemoveCourses(ctx context.Context, courseIDs []string) error
+	}
+
+	repo struct {
+		db  *gorm.DB
+		log *log.Logger
+	}
+)
+
+func NewRepo(db *gorm.DB, logger *log.Logger) Repository {
+	return &repo{
+		db:  db,
+		log: logger,
+	}
+}
+
+func (r *repo) CreateTag(ctx context.Context, tag *Tag) error {
+	if err := r.db.WithContext(ctx).Create(tag).Error; err != nil {
+		r.log.Printf("error: %v", err)
+		return err
+	}
+	r.log.Println("tag created with id: ", tag.ID)
+	return nil
+}
+
+func (r *repo) GetTag(ctx context.Context, id string) (*Tag, error) {
+	tag := Tag{ID: id}
+	if err := r.db.WithContext(ctx).First(&tag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewErrNotFound("tag", id)
+		}
+		r.log.Println("Error getting tag: ", err)
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *repo) GetTagByName(ctx context.Context, name string) (*Tag, error) {
+	var tag Tag
+	result := r.db.WithContext(ctx).Where("name = ?", name).Limit(1).Find(&tag)
+	if result.Error != nil {
+		r.log.Println("Error getting tag: ", result.Error)
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &tag, nil
+}
+
+func (r *repo) GetTags(ctx context.Context, offset, limit int) ([]Tag, error) {
+	var tags []Tag
+	result := r.db.WithContext(ctx).Order("name").Limit(limit).Offset(offset).Find(&tags)
+	if result.Error != nil {
+		r.log.Println("Error getting tags: ", result.Error)
+		return nil, result.Error
+	}
+	return tags, nil
+}
+
+func (r *repo) CountTags(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&Tag{}).Count(&count).Error; err != nil {
+		r.log.Println("Error counting tags: ", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *repo) SaveTag(ctx context.Context, tag *Tag) error {
+	if err := r.db.WithContext(ctx).Save(tag).Error; err != nil {
+		r.log.Println("Error saving tag: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) DeleteTag(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", id).Delete(&CourseTag{}).Error; err != nil {
+			r.log.Println("Error deleting course tags: ", err)
+			return err
+		}
+		result := tx.Delete(&Tag{ID: id})
+		if result.Error != nil {
+			r.log.Println("Error deleting tag: ", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return NewErrNotFound("tag", id)
+		}
+		return nil
+	})
+}
+
+func (r *repo) EnsureTags(ctx context.Context, names []string) ([]Tag, error) {
+	if len(names) == 0 {
+		return []Tag{}, nil
+	}
+
+	tags := make([]Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, Tag{Name: name})
+	}
+	// Los nombres que el tenant ya tiene se ignoran por el índice único
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		r.log.Println("Error creating tags: ", err)
+		return nil, err
+	}
+
+	var stored []Tag
+	if err := r.db.WithContext(ctx).Where("name IN ?", names).Order("name").Find(&stored).Error; err != nil {
+		r.log.Println("Error getting tags: ", err)
+		return nil, err
+	}
+	return stored, nil
+}
+
+func (r *repo) CreateCategory(ctx context.Context, category *Category) error {
+	if err := r.db.WithContext(ctx).Create(category).Error; err != nil {
+		r.log.Printf("error: %v", err)
+		return err
+	}
+	r.log.Println("category created with id: ", category.ID)
+	return nil
+}
+
+func (r *repo) GetCategory(ctx context.Context, id string) (*Category, error) {
+	category := Category{ID: id}
+	if err := r.db.WithContext(ctx).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewErrNotFound("category", id)
+		}
+		r.log.Println("Error getting category: ", err)
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *repo) GetCategories(ctx context.Context, parentID *string, offset, limit int) ([]Category, error) {
+	var categories []Category
+	tx := applyParent(r.db.WithContext(ctx).Model(&categories), parentID)
+	result := tx.Order("name").Limit(limit).Offset(offset).Find(&categories)
+	if result.Error != nil {
+		r.log.Println("Error getting categories: ", result.Error)
+		return nil, result.Error
+	}
+	return categories, nil
+}
+
+func (r *repo) CountCategories(ctx context.Context, parentID *string) (int64, error) {
+	var count int64
+	tx := applyParent(r.db.WithContext(ctx).Model(&Category{}), parentID)
+	if err := tx.Count(&count).Error; err != nil {
+		r.log.Println("Error counting categories: ", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// applyParent filtra por padre: nil no filtra y "" son las categorías raíz
+func applyParent(tx *gorm.DB, parentID *string) *gorm.DB {
+	switch {
+	case parentID == nil:
+		return tx
+	case *parentID == "":
+		return tx.Where("parent_id IS NULL")
+	}
+	return tx.Where("parent_id = ?", *parentID)
+}
+
+func (r *repo) SaveCategory(ctx context.Context, category *Category) error {
+	if err := r.db.WithContext(ctx).Save(category).Error; err != nil {
+		r.log.Println("Error saving category: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) DeleteCategory(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", id).Delete(&CourseCategory{}).Error; err != nil {
+			r.log.Println("Error deleting course categories: ", err)
+			return err
+		}
+		result := tx.Delete(&Category{ID: id})
+		if result.Error != nil {
+			r.log.Println("Error deleting category: ", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return NewErrNotFound("category", id)
+		}
+		return nil
+	})
+}
+
+func (r *repo) SetCourseTags(ctx context.Context, courseID string, tagIDs []string) error {
+	if err := r.db.WithContext(ctx).Where("course_id = ?", courseID).Delete(&CourseTag{}).Error; err != nil {
+		r.log.Println("Error deleting course tags: ", err)
+		return err
+	}
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	links := make([]CourseTag, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		links = append(links, CourseTag{CourseID: courseID, TagID: tagID})
+	}
+	if err := r.db.WithContext(ctx).Create(&links).Error; err != nil {
+		r.log.Println("Error creating course tags: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) SetCourseCategory(ctx context.Context, courseID string, categoryID *string) error {
+	if categoryID == nil {
+		if err := r.db.WithContext(ctx).Delete(&CourseCategory{CourseID: courseID}).Error; err != nil {
+			r.log.Println("Error deleting course category: ", err)
+			return err
+		}
+		return nil
+	}
+
+	link := CourseCategory{CourseID: courseID, CategoryID: *categoryID}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"category_id"}),
+	}).Create(&link).Error
+	if err != nil {
+		r.log.Println("Error saving course category: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) TagsByCourse(ctx context.Context, courseIDs []string) (map[string][]Tag, error) {
+	tags := make(map[string][]Tag, len(courseIDs))
+	if len(courseIDs) == 0 {
+		return tags, nil
+	}
+
+	var rows []struct {
+		CourseID string
+		Tag
+	}
+	result := r.db.WithContext(ctx).Table("course_tags").
+		Select("course_tags.course_id, tags.*").
+		Joins("JOIN tags ON tags.id = course_tags.tag_id").
+		Scopes(tenant.Scope(ctx, "tags")).
+		Where("course_tags.course_id IN ?", courseIDs).
+		Order("tags.name").
+		Scan(&rows)
+	if result.Error != nil {
+		r.log.Println("Error getting course tags: ", result.Error)
+		return nil, result.Error
+	}
+	for _, row := range rows {
+		tags[row.CourseID] = append(tags[row.CourseID], row.Tag)
+	}
+	return tags, nil
+}
+
+func (r *repo) CategoriesByCourse(ctx context.Context, courseIDs []string) (map[string]*Category, error) {
+	categories := make(map[string]*Category, len(courseIDs))
+	if len(courseIDs) == 0 {
+		return categories, nil
+	}
+
+	var rows []struct {
+		CourseID string
+		Category
+	}
+	result := r.db.WithContext(ctx).Table("course_categories").
+		Select("course_categories.course_id, categories.*").
+		Joins("JOIN categories ON categories.id = course_categories.category_id").
+		Scopes(tenant.Scope(ctx, "categories")).
+		Where("course_categories.course_id IN ?", courseIDs).
+		Scan(&rows)
+	if result.Error != nil {
+		r.log.Println("Error getting course categories: ", result.Error)
+		return nil, result.Error
+	}
+	for _, row := range rows {
+		category := row.Category
+		categories[row.CourseID] = &category
+	}
+	return categories, nil
+}
+
+func (r *repo) RemoveCourses(ctx context.Context, courseIDs []string) error {
+	if len(courseIDs) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Where("course_id IN ?", courseIDs).Delete(&CourseTag{}).Error; err != nil {
+		r.log.Println("Error deleting course tags: ", err)
+		return err
+	}
+	if err := r.db.WithContext(ctx).Where("course_id IN ?", courseIDs).Delete(&CourseCategory{}).Error; err != nil {
+		r.log.Println("Error deleting course categories: ", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	Service interface {
+		CreateTag(ctx context.Context, name string) (*Tag, error)
+		GetTag(ctx context.Context, id string) (*Tag, error)
+		GetTags(ctx context.Context, offset, limit int) ([]Tag, error)
+		CountTags(ctx context.Context) (int64, error)
+		UpdateTag(ctx context.Context, id, name string) (*Tag, error)
+		DeleteTag(ctx context.Context, id string) error
+
+		CreateCategory(ctx context.Context, name string, parentID *string) (*Category, error)
+		// GetCategory incluye las subcategorías directas
+		GetCategory(ctx context.Context, id string) (*Category, error)
+		GetCategories(ctx context.Context, parentID *string, offset, limit int) ([]Category, error)
+		CountCategories(ctx context.Context, parentID *string) (int64, error)
+		// UpdateCategory mueve la categoría si parentID no es nil ("" la deja como raíz)
+		UpdateCategory(ctx context.Context, id string, name *string, parentID *string) (*Category, error)
+		DeleteCategory(ctx context.Context, id string) error
+	}
+
+	service struct {
+		log  *log.Logger
+		repo Repository
+	}
+)
+
+func NewService(log *log.Logger, repo Repository) Service {
+	return &service{
+		log:  log,
+		repo: repo,
+	}
+}
+
+func (s *service) CreateTag(ctx context.Context, name string) (*Tag, error) {
+	s.log.Println("---- Creating tag ----")
+
+	name, err := s.validateTagName(ctx, "", name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &Tag{Name: name}
+	if err := s.repo.CreateTag(ctx, tag); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToSaveTag, err)
+	}
+	return tag, nil
+}
+
+func (s *service) GetTag(ctx context.Context, id string) (*Tag, error) {
+	tag, err := s.repo.GetTag(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFoundBase) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetTags, err)
+	}
+	return tag, nil
+}
+
+func (s *service) GetTags(ctx context.Context, offset, limit int) ([]Tag, error) {
+	tags, err := s.repo.GetTags(ctx, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetTags, err)
+	}
+	return tags, nil
+}
+
+func (s *service) CountTags(ctx context.Context) (int64, error) {
+	count, err := s.repo.CountTags(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrFailedToGetTags, err)
+	}
+	return count, nil
+}
+
+func (s *service) UpdateTag(ctx context.Context, id, name string) (*Tag, error) {
+	s.log.Println("---- Updating tag ----")
+
+	tag, err := s.GetTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Name, err = s.validateTagName(ctx, id, name); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveTag(ctx, tag); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToSaveTag, err)
+	}
+	return tag, nil
+}
+
+func (s *service) DeleteTag(ctx context.Context, id string) error {
+	s.log.Println("---- Deleting tag ----")
+	if err := s.repo.DeleteTag(ctx, id); err != nil {
+		if errors.Is(err, ErrNotFoundBase) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrFailedToDeleteTag, err)
+	}
+	return nil
+}
+
+// validateTagName normaliza el nombre y verifica que no lo use otro tag que id
+func (s *service) validateTagName(ctx context.Context, id, name string) (string, error) {
+	name = NormalizeTag(name)
+	if name == "" {
+		return "", ErrNameRequired
+	}
+	if utf8.RuneCountInString(name) > MaxTagLength {
+		return "", ErrNameTooLong
+	}
+
+	existing, err := s.repo.GetTagByName(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrFailedToSaveTag, err)
+	}
+	if existing != nil && existing.ID != id {
+		return "", ErrTagExists
+	}
+	return name, nil
+}
+
+func (s *service) CreateCategory(ctx context.Context, name string, parentID *string) (*Category, error) {
+	s.log.Println("---- Creating category ----")
+
+	name, err := validateCategoryName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	category := &Category{Name: name}
+	if parentID != nil && *parentID != "" {
+		if err := s.validateParent(ctx, "", *parentID); err != nil {
+			return nil, err
+		}
+		category.ParentID = parentID
+	}
+
+	if err := s.repo.CreateCategory(ctx, category); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToSaveCategory, err)
+	}
+	return category, nil
+}
+
+func (s *service) GetCategory(ctx context.Context, id string) (*Category, error) {
+	category, err := s.getCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := s.repo.GetCategories(ctx, &id, 0, -1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetCategories, err)
+	}
+	category.Children = children
+	return category, nil
+}
+
+func (s *service) getCategory(ctx context.Context, id string) (*Category, error) {
+	category, err := s.repo.GetCategory(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFoundBase) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetCategories, err)
+	}
+	return category, nil
+}
+
+func (s *service) GetCategories(ctx context.Context, parentID *string, offset, limit int) ([]Category, error) {
+	categories, err := s.repo.GetCategories(ctx, parentID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetCategories, err)
+	}
+	return categories, nil
+}
+
+func (s *service) CountCategories(ctx context.Context, parentID *string) (int64, error) {
+	count, err := s.repo.CountCategories(ctx, parentID)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrFailedToGetCategories, err)
+	}
+	return count, nil
+}
+
+func (s *service) UpdateCategory(ctx context.Context, id string, name *string, parentID *string) (*Category, error) {
+	s.log.Println("---- Updating category ----")
+
+	category, err := s.getCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != nil {
+		if category.Name, err = validateCategoryName(*name); err != nil {
+			return nil, err
+		}
+	}
+	if parentID != nil {
+		if *parentID == "" {
+			category.ParentID = nil
+		} else {
+			if err := s.validateParent(ctx, id, *parentID); err != nil {
+				return nil, err
+			}
+			category.ParentID = parentID
+		}
+	}
+
+	if err := s.repo.SaveCategory(ctx, category); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToSaveCategory, err)
+	}
+	return category, nil
+}
+
+func (s *service) DeleteCategory(ctx context.Context, id string) error {
+	s.log.Println("---- Deleting category ----")
+
+	children, err := s.repo.CountCategories(ctx, &id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFailedToDeleteCategory, err)
+	}
+	if children > 0 {
+		return ErrCategoryHasChildren
+	}
+
+	if err := s.repo.DeleteCategory(ctx, id); err != nil {
+		if errors.Is(err, ErrNotFoundBase) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrFailedToDeleteCategory, err)
+	}
+	return nil
+}
+
+// validateParent recorre los ancestros de parentID: si aparece id habría un ciclo.
+// id vacío es una categoría nueva, que no puede formar ciclos.
+func (s *service) validateParent(ctx context.Context, id, parentID string) error {
+	current := &parentID
+	for depth := 1; current != nil; depth++ {
+		if *current == id {
+			return ErrCategoryCycle
+		}
+		if depth >= MaxCategoryDepth {
+			return ErrCategoryTooDeep
+		}
+		parent, err := s.getCategory(ctx, *current)
+		if err != nil {
+			if errors.Is(err, ErrNotFoundBase) {
+				return ErrParentNotFound
+			}
+			return err
+		}
+		current = parent.ParentID
+	}
+	return nil
+}
+
+func validateCategoryName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrNameRequired
+	}
+	if utf8.RuneCountInString(name) > MaxCategoryLength {
+		return "", ErrNameTooLong
+	}
+	return name, nil
+}
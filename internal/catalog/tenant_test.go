@@ -0,0 +1,111 @@
+package catalog_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/catalog"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	db := tenanttest.NewDB(t, &catalog.Tag{}, &catalog.Category{}, &catalog.CourseTag{}, &catalog.CourseCategory{})
+
+	ctxA, ctxB := tenanttest.Contexts()
+	repo := catalog.NewRepo(db, tenanttest.Logger())
+	svc := catalog.NewService(tenanttest.Logger(), repo)
+
+	tagA, err := svc.CreateTag(ctxA, "golang")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootA, err := svc.CreateCategory(ctxA, "Programming", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	childA, err := svc.CreateCategory(ctxA, "Backend", &rootA.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	courseID := "0b6f3c1e-1d2a-4e5f-9a8b-7c6d5e4f3a2b"
+	if err := repo.SetCourseTags(ctxA, courseID, []string{tagA.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SetCourseCategory(ctxA, courseID, &childA.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("rows are stamped with the tenant", func(t *testing.T) {
+		tenanttest.AssertStamped(t, db, "tags", "categories", "course_tags", "course_categories")
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		_, err := svc.GetTag(ctxB, tagA.ID)
+		tenanttest.AssertNotFound(t, "GetTag", err, catalog.ErrNotFoundBase)
+		tags, err := svc.GetTags(ctxB, 0, 10)
+		if err != nil || len(tags) != 0 {
+			t.Errorf("GetTags = %v, %v; want none", tags, err)
+		}
+		if count, err := svc.CountTags(ctxB); err != nil || count != 0 {
+			t.Errorf("CountTags = %d, %v; want 0", count, err)
+		}
+		_, err = svc.UpdateTag(ctxB, tagA.ID, "rust")
+		tenanttest.AssertNotFound(t, "UpdateTag", err, catalog.ErrNotFoundBase)
+		tenanttest.AssertNotFound(t, "DeleteTag", svc.DeleteTag(ctxB, tagA.ID), catalog.ErrNotFoundBase)
+
+		// El nombre es único en cada tenant, no entre tenants
+		tagB, err := svc.CreateTag(ctxB, "golang")
+		if err != nil {
+			t.Fatalf("CreateTag with a name of another tenant: %v", err)
+		}
+		ensured, err := repo.EnsureTags(ctxB, []string{"golang"})
+		if err != nil || len(ensured) != 1 || ensured[0].ID != tagB.ID {
+			t.Errorf("EnsureTags = %+v, %v; want the tag of tenant B", ensured, err)
+		}
+		if _, err := svc.CreateTag(ctxA, "golang"); !errors.Is(err, catalog.ErrTagExists) {
+			t.Errorf("CreateTag duplicated in the same tenant = %v, want %v", err, catalog.ErrTagExists)
+		}
+	})
+
+	t.Run("categories", func(t *testing.T) {
+		_, err := svc.GetCategory(ctxB, rootA.ID)
+		tenanttest.AssertNotFound(t, "GetCategory", err, catalog.ErrNotFoundBase)
+		categories, err := svc.GetCategories(ctxB, nil, 0, 10)
+		if err != nil || len(categories) != 0 {
+			t.Errorf("GetCategories = %v, %v; want none", categories, err)
+		}
+		if count, err := svc.CountCategories(ctxB, &rootA.ID); err != nil || count != 0 {
+			t.Errorf("CountCategories = %d, %v; want 0", count, err)
+		}
+		if _, err := svc.CreateCategory(ctxB, "Frontend", &rootA.ID); !errors.Is(err, catalog.ErrParentNotFound) {
+			t.Errorf("CreateCategory under a parent of another tenant = %v, want %v", err, catalog.ErrParentNotFound)
+		}
+		name := "Renamed"
+		_, err = svc.UpdateCategory(ctxB, childA.ID, &name, nil)
+		tenanttest.AssertNotFound(t, "UpdateCategory", err, catalog.ErrNotFoundBase)
+		tenanttest.AssertNotFound(t, "DeleteCategory", svc.DeleteCategory(ctxB, childA.ID), catalog.ErrNotFoundBase)
+	})
+
+	t.Run("course links", func(t *testing.T) {
+		tags, err := repo.TagsByCourse(ctxB, []string{courseID})
+		if err != nil || len(tags[courseID]) != 0 {
+			t.Errorf("TagsByCourse = %v, %v; want none", tags, err)
+		}
+		categories, err := repo.CategoriesByCourse(ctxB, []string{courseID})
+		if err != nil || categories[courseID] != nil {
+			t.Errorf("CategoriesByCourse = %v, %v; want none", categories, err)
+		}
+		if err := repo.RemoveCourses(ctxB, []string{courseID}); err != nil {
+			t.Fatal(err)
+		}
+
+		tags, err = repo.TagsByCourse(ctxA, []string{courseID})
+		if err != nil || len(tags[courseID]) != 1 {
+			t.Errorf("TagsByCourse of the owner = %v, %v; want 1", tags, err)
+		}
+		categories, err = repo.CategoriesByCourse(ctxA, []string{courseID})
+		if err != nil || categories[courseID] == nil || categories[courseID].ID != childA.ID {
+			t.Errorf("CategoriesByCourse of the owner = %v, %v; want %s", categories, err, childA.ID)
+		}
+	})
+}
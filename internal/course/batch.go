@@ -0,0 +1,194 @@
+package course
+
+import (
+	"context"
+	"errors"
+)
+
+type (
+	// BatchMode define cómo se comporta un lote ante un ítem fallido
+	BatchMode string
+
+	// BatchItemStatus es el resultado de un ítem dentro de un lote
+	BatchItemStatus string
+
+	BatchCreateItem struct {
+		Name           string
+		StartDate      string
+		EndDate        string
+		Timezone       string
+		Classification Classification
+		InstructorIDs  []string
+	}
+
+	BatchUpdateItem struct {
+		ID             string
+		Name           *string
+		StartDate      *string
+		EndDate        *string
+		Timezone       *string
+		Classification Classification
+	}
+
+	BatchResult struct {
+		Index  int             `json:"index"`
+		ID     string          `json:"id,omitempty"`
+		Status BatchItemStatus `json:"status"`
+		Error  string          `json:"error,omitempty"`
+		Course *Course         `json:"course,omitempty"`
+	}
+)
+
+const (
+	// BatchAtomic: todo o nada, un ítem fallido revierte el lote completo
+	BatchAtomic BatchMode = "atomic"
+	// BatchBestEffort: cada ítem corre en su propio SAVEPOINT y los fallidos se omiten
+	BatchBestEffort BatchMode = "best_effort"
+
+	BatchStatusCreated    BatchItemStatus = "created"
+	BatchStatusUpdated    BatchItemStatus = "updated"
+	BatchStatusDeleted    BatchItemStatus = "deleted"
+	BatchStatusFailed     BatchItemStatus = "failed"
+	BatchStatusRolledBack BatchItemStatus = "rolled_back"
+	BatchStatusSkipped    BatchItemStatus = "skipped"
+
+	MaxBatchSize = 500
+)
+
+// batchItemFunc procesa el ítem i usando un service ligado a la transacción del lote
+type batchItemFunc func(ctx context.Context, svc service, i int) (BatchResult, error)
+
+func (s service) CreateBatch(ctx context.Context, items []BatchCreateItem, mode BatchMode) ([]BatchResult, error) {
+	s.log.Println("---- Creating courses batch ----")
+	return s.runBatch(ctx, mode, len(items), func(ctx context.Context, svc service, i int) (BatchResult, error) {
+		item := items[i]
+		if item.Name == "" {
+			return BatchResult{}, ErrNameRequired
+		}
+		if item.StartDate == "" || item.EndDate == "" {
+			return BatchResult{}, ErrStartDateAndEndDateRequired
+		}
+		course, err := svc.Create(ctx, item.Name, item.StartDate, item.EndDate, item.Timezone, item.Classification, item.InstructorIDs)
+		if err != nil {
+			return BatchResult{}, err
+		}
+		return BatchResult{ID: course.ID, Status: BatchStatusCreated, Course: course}, nil
+	})
+}
+
+func (s service) UpdateBatch(ctx context.Context, items []BatchUpdateItem, mode BatchMode) ([]BatchResult, error) {
+	s.log.Println("---- Updating courses batch ----")
+	return s.runBatch(ctx, mode, len(items), func(ctx context.Context, svc service, i int) (BatchResult, error) {
+		item := items[i]
+		result := BatchResult{ID: item.ID}
+		if item.ID == "" {
+			return result, ErrIDRequired
+		}
+		if item.Name == nil && item.StartDate == nil && item.EndDate == nil && item.Timezone == nil &&
+			item.Classification.empty() {
+			return result, ErrAtLeastOneFieldRequired
+		}
+		if item.Name != nil && *item.Name == "" {
+			return result, ErrNameRequired
+		}
+		if (item.StartDate != nil && *item.StartDate == "") || (item.EndDate != nil && *item.EndDate == "") {
+			return result, ErrStartDateAndEndDateRequired
+		}
+		if err := svc.Update(ctx, item.ID, item.Name, item.StartDate, item.EndDate, item.Timezone, item.Classification); err != nil {
+			return result, err
+		}
+		result.Status = BatchStatusUpdated
+		return result, nil
+	})
+}
+
+func (s service) DeleteBatch(ctx context.Context, ids []string, mode BatchMode) ([]BatchResult, error) {
+	s.log.Println("---- Deleting courses batch ----")
+	return s.runBatch(ctx, mode, len(ids), func(ctx context.Context, svc service, i int) (BatchResult, error) {
+		result := BatchResult{ID: ids[i]}
+		if ids[i] == "" {
+			return result, ErrIDRequired
+		}
+		if err := svc.Delete(ctx, ids[i]); err != nil {
+			return result, err
+		}
+		result.Status = BatchStatusDeleted
+		return result, nil
+	})
+}
+
+// runBatch ejecuta n ítems dentro de una única transacción de GORM. En modo atómico
+// el primer error revierte todo y devuelve ErrBatchRolledBack junto a los resultados.
+func (s service) runBatch(ctx context.Context, mode BatchMode, n int, fn batchItemFunc) ([]BatchResult, error) {
+	if mode == "" {
+		mode = BatchAtomic
+	}
+	if mode != BatchAtomic && mode != BatchBestEffort {
+		return nil, ErrInvalidBatchMode
+	}
+	if n == 0 {
+		return nil, ErrBatchEmpty
+	}
+	if n > MaxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+
+	results := make([]BatchResult, n)
+	failedAt := -1
+
+	err := s.repo.Transaction(ctx, func(txRepo Repository) error {
+		for i := 0; i < n; i++ {
+			var result BatchResult
+			var err error
+
+			if mode == BatchBestEffort {
+				// 🔧 Cada ítem en su propio SAVEPOINT para no contaminar al resto
+				err = txRepo.Transaction(ctx, func(itemRepo Repository) error {
+					var itemErr error
+					result, itemErr = fn(ctx, s.withRepo(itemRepo), i)
+					return itemErr
+				})
+			} else {
+				result, err = fn(ctx, s.withRepo(txRepo), i)
+			}
+
+			result.Index = i
+			if err != nil {
+				s.log.Printf("Batch item %d failed: %v\n", i, err)
+				result.Status = BatchStatusFailed
+				result.Error = err.Error()
+				result.Course = nil
+				results[i] = result
+				if mode == BatchAtomic {
+					failedAt = i
+					return ErrBatchRolledBack
+				}
+				continue
+			}
+			results[i] = result
+		}
+		return nil
+	})
+
+	if failedAt >= 0 {
+		for i := range results {
+			switch {
+			case i < failedAt:
+				// Los cursos creados en el lote ya no existen tras el rollback
+				if results[i].Status == BatchStatusCreated {
+					results[i].ID = ""
+				}
+				results[i].Status = BatchStatusRolledBack
+				results[i].Course = nil
+			case i > failedAt:
+				results[i] = BatchResult{Index: i, Status: BatchStatusSkipped}
+			}
+		}
+		return results, ErrBatchRolledBack
+	}
+	if err != nil && !errors.Is(err, ErrBatchRolledBack) {
+		s.log.Printf("Error committing batch: %v\n", err)
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,145 @@
+package course_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+)
+
+func batchStatuses(results []course.BatchResult) []course.BatchItemStatus {
+	statuses := make([]course.BatchItemStatus, len(results))
+	for i, result := range results {
+		statuses[i] = result.Status
+	}
+	return statuses
+}
+
+func TestCreateBatch(t *testing.T) {
+	items := []course.BatchCreateItem{
+		{Name: "Go basics", StartDate: "2024-06-01", EndDate: "2024-06-30"},
+		{Name: "No dates"},
+		{Name: "Go advanced", StartDate: "2024-07-01", EndDate: "2024-07-31"},
+	}
+	all := course.Filters{Deleted: course.DeletedInclude}
+
+	t.Run("atomic", func(t *testing.T) {
+		svc, _, db := newTenantService(t)
+		ctx, _ := tenanttest.Contexts()
+
+		results, err := svc.CreateBatch(ctx, items, course.BatchAtomic)
+		if !errors.Is(err, course.ErrBatchRolledBack) {
+			t.Fatalf("CreateBatch = %v, want %v", err, course.ErrBatchRolledBack)
+		}
+		want := []course.BatchItemStatus{course.BatchStatusRolledBack, course.BatchStatusFailed, course.BatchStatusSkipped}
+		if got := batchStatuses(results); !slices.Equal(got, want) {
+			t.Errorf("statuses = %v, want %v", got, want)
+		}
+		if results[0].ID != "" || results[0].Course != nil {
+			t.Errorf("rolled back result = %+v, want no ID nor course", results[0])
+		}
+		if results[1].Error != course.ErrStartDateAndEndDateRequired.Error() {
+			t.Errorf("failed item error = %q", results[1].Error)
+		}
+
+		// Ni los cursos ni su auditoría sobreviven al rollback
+		if count, err := svc.Count(ctx, all); err != nil || count != 0 {
+			t.Errorf("courses = %d, %v; want 0", count, err)
+		}
+		var entries int64
+		if err := db.Model(&audit.Entry{}).Count(&entries).Error; err != nil || entries != 0 {
+			t.Errorf("audit entries = %d, %v; want 0", entries, err)
+		}
+	})
+
+	t.Run("best effort", func(t *testing.T) {
+		svc, _, _ := newTenantService(t)
+		ctx, _ := tenanttest.Contexts()
+
+		results, err := svc.CreateBatch(ctx, items, course.BatchBestEffort)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []course.BatchItemStatus{course.BatchStatusCreated, course.BatchStatusFailed, course.BatchStatusCreated}
+		if got := batchStatuses(results); !slices.Equal(got, want) {
+			t.Errorf("statuses = %v, want %v", got, want)
+		}
+		for _, i := range []int{0, 2} {
+			if results[i].ID == "" || results[i].Course == nil || results[i].Index != i {
+				t.Errorf("result %d = %+v, want the created course", i, results[i])
+			}
+		}
+		if count, err := svc.Count(ctx, all); err != nil || count != 2 {
+			t.Errorf("courses = %d, %v; want 2", count, err)
+		}
+	})
+}
+
+func TestUpdateAndDeleteBatch(t *testing.T) {
+	svc, _, _ := newTenantService(t)
+	ctx, _ := tenanttest.Contexts()
+	created, err := svc.Create(ctx, "Go basics", "2024-06-01", "2024-06-30", "", course.Classification{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing := "9a8b7c6d-5e4f-4a3b-2c1d-0e9f8a7b6c5d"
+	name := "Go fundamentals"
+
+	// Atómico: el ID inexistente revierte el cambio de nombre
+	updates := []course.BatchUpdateItem{{ID: created.ID, Name: &name}, {ID: missing, Name: &name}}
+	if _, err := svc.UpdateBatch(ctx, updates, course.BatchAtomic); !errors.Is(err, course.ErrBatchRolledBack) {
+		t.Fatalf("UpdateBatch = %v, want %v", err, course.ErrBatchRolledBack)
+	}
+	if got, err := svc.Get(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	} else if got.Name != "Go basics" {
+		t.Errorf("after the rollback name = %q, want Go basics", got.Name)
+	}
+
+	results, err := svc.UpdateBatch(ctx, updates, course.BatchBestEffort)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := batchStatuses(results), []course.BatchItemStatus{course.BatchStatusUpdated, course.BatchStatusFailed}; !slices.Equal(got, want) {
+		t.Errorf("update statuses = %v, want %v", got, want)
+	}
+	if got, err := svc.Get(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	} else if got.Name != name {
+		t.Errorf("name = %q, want %s", got.Name, name)
+	}
+
+	results, err = svc.DeleteBatch(ctx, []string{missing, created.ID}, course.BatchBestEffort)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := batchStatuses(results), []course.BatchItemStatus{course.BatchStatusFailed, course.BatchStatusDeleted}; !slices.Equal(got, want) {
+		t.Errorf("delete statuses = %v, want %v", got, want)
+	}
+}
+
+func TestBatchLimits(t *testing.T) {
+	svc, _, _ := newTenantService(t)
+	ctx, _ := tenanttest.Contexts()
+
+	tests := []struct {
+		name  string
+		items []course.BatchCreateItem
+		mode  course.BatchMode
+		err   error
+	}{
+		{"invalid mode", []course.BatchCreateItem{{Name: "Go"}}, "partial", course.ErrInvalidBatchMode},
+		{"empty", nil, course.BatchAtomic, course.ErrBatchEmpty},
+		{"too large", make([]course.BatchCreateItem, course.MaxBatchSize+1), course.BatchBestEffort, course.ErrBatchTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.CreateBatch(ctx, tt.items, tt.mode); !errors.Is(err, tt.err) {
+				t.Errorf("CreateBatch = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/NicoJCastro/gocourse_course/internal/catalog"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+type (
+	// Course es el curso con su zona horaria, categoría, tags y traducción, que viven en
+	// tablas propias de este servicio porque domain.Course es compartido
+	Course struct {
+		domain.Course
+		// Timezone es la zona horaria IANA en la que se expresan start_date y end_date
+		Timezone string            `json:"timezone"`
+		Category *catalog.Category `json:"category"`
+		Tags     []catalog.Tag     `json:"tags"`
+		// Locale es el idioma de name y description según Accept-Language
+		Locale      string `json:"locale,omitempty"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// Classification es la categoría y los tags a asignar. CategoryID nil deja la
+	// categoría como está y "" la quita; Tags nil no cambia los tags y vacío los quita.
+	Classification struct {
+		CategoryID *string
+		Tags       []string
+	}
+
+	// TagMatch define si un filtro con varios tags exige todos o alguno
+	TagMatch string
+)
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+
+	MaxCourseTags = 20
+)
+
+func (c Classification) empty() bool {
+	return c.CategoryID == nil && c.Tags == nil
+}
+
+// classify aplica la clasificación dentro de la transacción del repositorio de s
+func (s service) classify(ctx context.Context, courseID string, class Classification) error {
+	if class.Tags != nil {
+		tags := catalog.NormalizeTags(class.Tags)
+		if len(tags) > MaxCourseTags {
+			return ErrTooManyTags
+		}
+		for _, tag := range tags {
+			if utf8.RuneCountInString(tag) > catalog.MaxTagLength {
+				return fmt.Errorf("%w: %s", ErrInvalidTag, tag)
+			}
+		}
+		if err := s.repo.SetTags(ctx, courseID, tags); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToClassifyCourse, err)
+		}
+	}
+
+	if class.CategoryID != nil {
+		err := s.repo.SetCategory(ctx, courseID, *class.CategoryID)
+		if errors.Is(err, catalog.ErrNotFoundBase) {
+			return fmt.Errorf("%w: %s", ErrCategoryNotFound, *class.CategoryID)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToClassifyCourse, err)
+		}
+	}
+	return nil
+}
+
+// describeOne es describe para un solo curso
+func (s service) describeOne(ctx context.Context, course *domain.Course) (*Course, error) {
+	described, err := s.describe(ctx, *course)
+	if err != nil {
+		return nil, err
+	}
+	return &described[0], nil
+}
+
+// describe agrega zona horaria, categoría y tags a los cursos con una consulta por relación
+func (s service) describe(ctx context.Context, courses ...domain.Course) ([]Course, error) {
+	described, err := s.repo.Describe(ctx, courses...)
+	if err != nil {
+		s.log.Printf("Error getting course classification: %v\n", err)
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetCourse, err)
+	}
+	return described, nil
+}
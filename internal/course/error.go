@@ -1,46 +1,186 @@
-package course
-
-import (
-	"errors"
-	"fmt"
-)
-
-var ErrInvalidRequestType = errors.New("invalid request type")
-var ErrInvalidDefaultLimitConfiguration = errors.New("invalid default limit configuration")
-var ErrIDRequired = errors.New("id is required")
-var ErrAtLeastOneFieldRequired = errors.New("at least one field is required")
-var ErrNameRequired = errors.New("name is required")
-var ErrStartDateAndEndDateRequired = errors.New("start_date and end_date are required")
-var ErrFailedToCreateCourse = errors.New("failed to create course")
-var ErrFailedToGetCourse = errors.New("failed to get course")
-var ErrFailedToGetAllCourses = errors.New("failed to get all courses")
-var ErrFailedToUpdateCourse = errors.New("failed to update course")
-var ErrFailedToDeleteCourse = errors.New("failed to delete course")
-var ErrFailedToCountCourses = errors.New("failed to count courses")
-var ErrInvalidStartDate = errors.New("invalid start date format")
-var ErrInvalidEndDate = errors.New("invalid end date format")
-var ErrStartDateAfterEndDate = errors.New("start date is after end date")
-var ErrEndDateBeforeStartDate = errors.New("end date is before start date")
-
-// ErrNotFound es un error personalizado que incluye el ID del curso no encontrado
-type ErrNotFound struct {
-	CourseID string
-}
-
-// Error implementa la interfaz error
-func (e *ErrNotFound) Error() string {
-	return fmt.Sprintf("course with ID %s not found", e.CourseID)
-}
-
-// Unwrap permite usar errors.Is() con este error
-func (e *ErrNotFound) Unwrap() error {
-	return ErrNotFoundBase
-}
-
-// NewErrNotFound crea una nueva instancia de ErrNotFound
-func NewErrNotFound(courseID string) *ErrNotFound {
-	return &ErrNotFound{CourseID: courseID}
-}
-
-// ErrNotFoundBase es un error sentinela para comparaciones con errors.Is()
-var ErrNotFoundBase = errors.New("course not found")
+package course
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Error es un error de la API con un código estable: los clientes y el catálogo de
+// mensajes se guían por el código, no por el texto en inglés
+type Error struct {
+	code    string
+	message string
+}
+
+func newError(code, message string) *Error {
+	return &Error{code: code, message: message}
+}
+
+// Error implementa la interfaz error
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Code devuelve el código estable del error (ej: "name_required")
+func (e *Error) Code() string {
+	return e.code
+}
+
+var ErrInvalidRequestType = newError("invalid_request_type", "invalid request type")
+var ErrIDRequired = newError("id_required", "id is required")
+var ErrAtLeastOneFieldRequired = newError("at_least_one_field_required", "at least one field is required")
+var ErrNameRequired = newError("name_required", "name is required")
+var ErrStartDateAndEndDateRequired = newError("dates_required", "start_date and end_date are required")
+var ErrFailedToCreateCourse = newError("failed_to_create_course", "failed to create course")
+var ErrFailedToGetCourse = newError("failed_to_get_course", "failed to get course")
+var ErrFailedToGetAllCourses = newError("failed_to_get_courses", "failed to get all courses")
+var ErrFailedToUpdateCourse = newError("failed_to_update_course", "failed to update course")
+var ErrFailedToDeleteCourse = newError("failed_to_delete_course", "failed to delete course")
+var ErrFailedToCountCourses = newError("failed_to_count_courses", "failed to count courses")
+var ErrInvalidStartDate = newError("invalid_start_date", "invalid start date format, must be 2006-01-02 or RFC 3339")
+var ErrInvalidEndDate = newError("invalid_end_date", "invalid end date format, must be 2006-01-02 or RFC 3339")
+var ErrStartDateAfterEndDate = newError("start_date_after_end_date", "start date is after end date")
+var ErrEndDateBeforeStartDate = newError("end_date_before_start_date", "end date is before start date")
+var ErrInvalidID = newError("invalid_id", "invalid id format, must be a UUID")
+var ErrFailedToReplaceCourse = newError("failed_to_replace_course", "failed to replace course")
+var ErrUnsupportedPatchType = newError("unsupported_patch_type", "unsupported patch content type")
+var ErrInvalidPatch = newError("invalid_patch", "invalid patch document")
+var ErrPatchTestFailed = newError("patch_test_failed", "patch test operation failed")
+var ErrIDImmutable = newError("id_immutable", "id cannot be modified")
+var ErrBatchEmpty = newError("batch_empty", "batch must contain at least one item")
+var ErrBatchTooLarge = newError("batch_too_large", "batch exceeds the maximum number of items")
+var ErrInvalidBatchMode = newError("invalid_batch_mode", "invalid batch mode, must be atomic or best_effort")
+var ErrBatchRolledBack = newError("batch_rolled_back", "batch rolled back because an item failed")
+var ErrUnsupportedImportFormat = newError("unsupported_import_format", "unsupported import format, must be csv or xlsx")
+var ErrInvalidImportFile = newError("invalid_import_file", "invalid import file")
+var ErrImportFileEmpty = newError("import_file_empty", "import file is empty")
+var ErrImportColumnMissing = newError("import_column_missing", "import column not found")
+var ErrInvalidColumnMapping = newError("invalid_column_mapping", "invalid column mapping")
+var ErrFailedToImportCourses = newError("failed_to_import_courses", "failed to import courses")
+var ErrUnsupportedExportFormat = newError("unsupported_export_format", "unsupported export format, must be csv, ndjson or ics")
+var ErrFailedToExportCourses = newError("failed_to_export_courses", "failed to export courses")
+var ErrInvalidDeletedScope = newError("invalid_deleted_scope", "invalid deleted filter, must be only or include")
+var ErrFailedToRestoreCourse = newError("failed_to_restore_course", "failed to restore course")
+var ErrFailedToPurgeCourse = newError("failed_to_purge_course", "failed to purge course")
+var ErrPurgeForbidden = newError("purge_forbidden", "purge requires admin privileges")
+var ErrFailedToRecordAudit = newError("failed_to_record_audit", "failed to record audit entry")
+var ErrFailedToGetHistory = newError("failed_to_get_history", "failed to get course history")
+var ErrFailedToRecordEvents = newError("failed_to_record_events", "failed to record course events")
+var ErrSearchQueryRequired = newError("search_query_required", "search query is required")
+var ErrSearchQueryTooLong = newError("search_query_too_long", "search query is too long")
+var ErrFailedToSearch = newError("failed_to_search", "failed to search courses")
+var ErrSearchFilterUnsupported = newError("search_filter_unsupported", "the search backend does not support tag or category filters")
+var ErrChangeFilterUnsupported = newError("change_filter_unsupported", "the change stream does not support tag or category filters")
+var ErrCategoryNotFound = newError("category_not_found", "category not found")
+var ErrInvalidTag = newError("invalid_tag", "invalid tag")
+var ErrTooManyTags = newError("too_many_tags", "too many tags")
+var ErrInvalidTagMatch = newError("invalid_tag_match", "invalid tag_match, must be any or all")
+var ErrFailedToClassifyCourse = newError("failed_to_classify_course", "failed to set course category and tags")
+var ErrPrerequisiteIDRequired = newError("prerequisite_id_required", "prerequisite_id is required")
+var ErrSelfPrerequisite = newError("self_prerequisite", "a course cannot be its own prerequisite")
+var ErrPrerequisiteCycle = newError("prerequisite_cycle", "prerequisite would create a cycle")
+var ErrPrerequisiteNotFound = newError("prerequisite_not_found", "prerequisite not found")
+var ErrCourseIsPrerequisite = newError("course_is_prerequisite", "course is a prerequisite of other courses")
+var ErrInvalidPrerequisitePolicy = newError("invalid_prerequisite_policy", "invalid prerequisite policy, must be restrict or cascade")
+var ErrFailedToGetPrerequisites = newError("failed_to_get_prerequisites", "failed to get prerequisites")
+var ErrFailedToUpdatePrerequisites = newError("failed_to_update_prerequisites", "failed to update prerequisites")
+var ErrInvalidTimezone = newError("invalid_timezone", "invalid timezone, must be an IANA name like America/Argentina/Buenos_Aires")
+var ErrSessionsOutsideCourse = newError("sessions_outside_course", "new dates leave course sessions outside the course")
+var ErrUserIDRequired = newError("user_id_required", "user_id is required")
+var ErrInvalidUserID = newError("invalid_user_id", "invalid user id format, must be a UUID")
+var ErrInstructorNotFound = newError("instructor_not_found", "instructor not found")
+var ErrUserNotFound = newError("user_not_found", "user not found")
+var ErrCreatorNotFound = newError("creator_not_found", "creator user (X-User-ID) not found")
+var ErrFailedToGetInstructors = newError("failed_to_get_instructors", "failed to get instructors")
+var ErrFailedToUpdateInstructors = newError("failed_to_update_instructors", "failed to update instructors")
+var ErrUnsupportedLocale = newError("unsupported_locale", "unsupported locale")
+var ErrNameTooLong = newError("name_too_long", "name is too long")
+var ErrDescriptionTooLong = newError("description_too_long", "description is too long")
+var ErrTranslationNotFound = newError("translation_not_found", "translation not found")
+var ErrFailedToGetTranslations = newError("failed_to_get_translations", "failed to get translations")
+var ErrFailedToUpdateTranslations = newError("failed_to_update_translations", "failed to update translations")
+var ErrWorkbookWithoutSheets = newError("workbook_without_sheets", "workbook has no sheets")
+var ErrFailedToGenerateMetadata = newError("metadata_error", "error generating metadata")
+
+// ErrNotFound es un error personalizado que incluye el ID del curso no encontrado
+type ErrNotFound struct {
+	CourseID string
+}
+
+// Error implementa la interfaz error
+func (e *ErrNotFound) Error() string {
+	return fmt.Sprintf("course with ID %s not found", e.CourseID)
+}
+
+// Code devuelve el código estable del error
+func (e *ErrNotFound) Code() string {
+	return "course_not_found"
+}
+
+// Args son las partes variables del mensaje, para traducirlo
+func (e *ErrNotFound) Args() []any {
+	return []any{e.CourseID}
+}
+
+// Unwrap permite usar errors.Is() con este error
+func (e *ErrNotFound) Unwrap() error {
+	return ErrNotFoundBase
+}
+
+// NewErrNotFound crea una nueva instancia de ErrNotFound
+func NewErrNotFound(courseID string) *ErrNotFound {
+	return &ErrNotFound{CourseID: courseID}
+}
+
+// ErrNotFoundBase es un error sentinela para comparaciones con errors.Is()
+var ErrNotFoundBase = errors.New("course not found")
+
+// ErrScheduleConflict lista los cursos que un instructor ya da en las mismas fechas
+type ErrScheduleConflict struct {
+	Conflicts []ScheduleConflict
+}
+
+// Error implementa la interfaz error
+func (e *ErrScheduleConflict) Error() string {
+	ids := make([]string, 0, len(e.Conflicts))
+	for _, conflict := range e.Conflicts {
+		if !slices.Contains(ids, conflict.CourseID) {
+			ids = append(ids, conflict.CourseID)
+		}
+	}
+	return fmt.Sprintf("%s: %s", ErrScheduleConflictBase, strings.Join(ids, ", "))
+}
+
+// Unwrap permite usar errors.Is() con este error
+func (e *ErrScheduleConflict) Unwrap() error {
+	return ErrScheduleConflictBase
+}
+
+// ErrScheduleConflictBase es un error sentinela para comparaciones con errors.Is()
+var ErrScheduleConflictBase = newError("schedule_conflict", "instructor schedule conflict with courses")
+
+// ErrUnavailable indica que la base de datos no responde o el circuito está abierto;
+// RetryAfter (si se conoce) es cuánto falta para que vuelva a intentarse
+type ErrUnavailable struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+// Error implementa la interfaz error
+func (e *ErrUnavailable) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", ErrUnavailableBase, e.Err)
+	}
+	return ErrUnavailableBase.Error()
+}
+
+// Unwrap permite usar errors.Is() con este error
+func (e *ErrUnavailable) Unwrap() error {
+	return ErrUnavailableBase
+}
+
+// ErrUnavailableBase es un error sentinela para comparaciones con errors.Is()
+var ErrUnavailableBase = newError("database_unavailable", "database temporarily unavailable")
@@ -0,0 +1,98 @@
+package course
+
+import (
+	"context"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/outbox"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+type (
+	// CourseCreated se emite al crear un curso (alta, PUT con upsert, lote o importación)
+	CourseCreated struct {
+		CourseID   string    `json:"course_id"`
+		Name       string    `json:"name"`
+		StartDate  time.Time `json:"start_date"`
+		EndDate    time.Time `json:"end_date"`
+		OccurredAt time.Time `json:"occurred_at"`
+	}
+
+	// CourseRescheduled se emite cuando cambia start_date o end_date
+	CourseRescheduled struct {
+		CourseID          string    `json:"course_id"`
+		PreviousStartDate time.Time `json:"previous_start_date"`
+		PreviousEndDate   time.Time `json:"previous_end_date"`
+		StartDate         time.Time `json:"start_date"`
+		EndDate           time.Time `json:"end_date"`
+		OccurredAt        time.Time `json:"occurred_at"`
+	}
+
+	// CourseDeleted se emite al enviar un curso activo a la papelera o al purgarlo
+	CourseDeleted struct {
+		CourseID   string    `json:"course_id"`
+		OccurredAt time.Time `json:"occurred_at"`
+	}
+)
+
+const (
+	EventCourseCreated     = "course.created"
+	EventCourseRescheduled = "course.rescheduled"
+	EventCourseDeleted     = "course.deleted"
+)
+
+// courseEvents traduce una mutación en los eventos de dominio que corresponden
+func courseEvents(ctx context.Context, change courseChange) ([]*outbox.Message, error) {
+	now := time.Now().UTC()
+	var eventType string
+	var payload interface{}
+	var id string
+
+	switch {
+	case change.action == audit.ActionCreate && change.after != nil:
+		id = change.after.ID
+		eventType = EventCourseCreated
+		payload = newCourseCreated(change.after, now)
+
+	case change.action == audit.ActionUpdate && change.before != nil && change.after != nil:
+		if change.before.StartDate.Equal(change.after.StartDate) && change.before.EndDate.Equal(change.after.EndDate) {
+			return nil, nil
+		}
+		id = change.after.ID
+		eventType = EventCourseRescheduled
+		payload = CourseRescheduled{
+			CourseID:          id,
+			PreviousStartDate: change.before.StartDate,
+			PreviousEndDate:   change.before.EndDate,
+			StartDate:         change.after.StartDate,
+			EndDate:           change.after.EndDate,
+			OccurredAt:        now,
+		}
+
+	// Un purge sólo emite el evento si el curso no estaba ya en la papelera
+	case (change.action == audit.ActionDelete || change.action == audit.ActionPurge) && change.before != nil:
+		id = change.before.ID
+		eventType = EventCourseDeleted
+		payload = CourseDeleted{CourseID: id, OccurredAt: now}
+
+	default:
+		return nil, nil
+	}
+
+	msg, err := outbox.NewMessage(ctx, eventType, AuditEntityCourse, id, payload)
+	if err != nil {
+		return nil, err
+	}
+	return []*outbox.Message{msg}, nil
+}
+
+func newCourseCreated(course *domain.Course, now time.Time) CourseCreated {
+	return CourseCreated{
+		CourseID:   course.ID,
+		Name:       course.Name,
+		StartDate:  course.StartDate,
+		EndDate:    course.EndDate,
+		OccurredAt: now,
+	}
+}
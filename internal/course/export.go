@@ -0,0 +1,153 @@
+package course
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+type (
+	// ExportFormat identifica el formato de salida de /courses/export
+	ExportFormat string
+
+	// ExportWriter serializa cursos de a uno, sin acumularlos en memoria
+	ExportWriter interface {
+		Write(course domain.Course) error
+		Close() error
+	}
+
+	csvExportWriter struct {
+		w *csv.Writer
+	}
+
+	ndjsonExportWriter struct {
+		w   *bufio.Writer
+		enc *json.Encoder
+	}
+
+	icsExportWriter struct {
+		w     *bufio.Writer
+		stamp string
+	}
+)
+
+const (
+	ExportCSV    ExportFormat = "csv"
+	ExportNDJSON ExportFormat = "ndjson"
+	ExportICS    ExportFormat = "ics"
+)
+
+// ContentType devuelve el MIME type correspondiente al formato
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportCSV:
+		return "text/csv; charset=utf-8"
+	case ExportNDJSON:
+		return "application/x-ndjson"
+	case ExportICS:
+		return "text/calendar; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+// NewExportWriter crea el writer para el formato pedido
+func NewExportWriter(format ExportFormat, w io.Writer) (ExportWriter, error) {
+	switch format {
+	case ExportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"id", "name", "start_date", "end_date"}); err != nil {
+			return nil, err
+		}
+		return &csvExportWriter{w: cw}, nil
+	case ExportNDJSON:
+		bw := bufio.NewWriter(w)
+		return &ndjsonExportWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+	case ExportICS:
+		bw := bufio.NewWriter(w)
+		iw := &icsExportWriter{w: bw, stamp: time.Now().UTC().Format("20060102T150405Z")}
+		iw.line("BEGIN:VCALENDAR")
+		iw.line("VERSION:2.0")
+		iw.line("PRODID:-//gocourse//course-api//ES")
+		iw.line("CALSCALE:GREGORIAN")
+		return iw, nil
+	}
+	return nil, ErrUnsupportedExportFormat
+}
+
+// Export recorre todos los cursos que cumplen los filtros y los envía a fn de a uno
+func (s service) Export(ctx context.Context, filters Filters, fn func(course TenantCourse) error) error {
+	s.log.Println("---- Exporting courses ----")
+	if err := s.repo.Stream(ctx, filters, fn); err != nil {
+		s.log.Printf("Error exporting courses: %v\n", err)
+		return fmt.Errorf("%w: %w", ErrFailedToExportCourses, err)
+	}
+	return nil
+}
+
+func (c *csvExportWriter) Write(course domain.Course) error {
+	return c.w.Write([]string{
+		course.ID,
+		course.Name,
+		course.StartDate.Format("2006-01-02"),
+		course.EndDate.Format("2006-01-02"),
+	})
+}
+
+func (c *csvExportWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (n *ndjsonExportWriter) Write(course domain.Course) error {
+	return n.enc.Encode(course)
+}
+
+func (n *ndjsonExportWriter) Close() error {
+	return n.w.Flush()
+}
+
+// Write genera un VEVENT de día completo. DTEND es exclusivo en iCalendar
+// (RFC 5545), por eso se suma un día a EndDate.
+func (i *icsExportWriter) Write(course domain.Course) error {
+	i.line("BEGIN:VEVENT")
+	i.line("UID:" + course.ID + "@gocourse")
+	i.line("DTSTAMP:" + i.stamp)
+	i.line("DTSTART;VALUE=DATE:" + course.StartDate.Format("20060102"))
+	i.line("DTEND;VALUE=DATE:" + course.EndDate.AddDate(0, 0, 1).Format("20060102"))
+	i.line("SUMMARY:" + escapeICSText(course.Name))
+	i.line("END:VEVENT")
+	return nil
+}
+
+func (i *icsExportWriter) Close() error {
+	i.line("END:VCALENDAR")
+	return i.w.Flush()
+}
+
+// line escribe una línea de contenido con CRLF, plegándola a 75 octetos como pide RFC 5545
+func (i *icsExportWriter) line(content string) {
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		// No cortar en medio de un carácter UTF-8
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		_, _ = i.w.WriteString(content[:cut] + "\r\n ")
+		content = content[cut:]
+		// Las líneas de continuación empiezan con un espacio que también cuenta
+		limit = 74
+	}
+	_, _ = i.w.WriteString(content + "\r\n")
+}
+
+func escapeICSText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
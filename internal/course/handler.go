@@ -1,229 +1,1111 @@
-package course
-
-import (
-	"context"
-	"errors"
-	"strconv"
-
-	"github.com/NicoJCastro/go_lib_response/response"
-	"github.com/NicoJCastro/gocourse_meta/meta"
-)
-
-type (
-	Controller func(ctx context.Context, request interface{}) (interface{}, error)
-
-	Endpoint struct {
-		Create Controller
-		Get    Controller
-		GetAll Controller
-		Update Controller
-		Delete Controller
-	}
-
-	CreateReq struct {
-		Name      string `json:"name"`
-		StartDate string `json:"start_date"`
-		EndDate   string `json:"end_date"`
-	}
-
-	GetAllReq struct {
-		Name  string `json:"name"`
-		Limit int    `json:"limit"`
-		Page  int    `json:"page"`
-	}
-
-	GetReq struct {
-		ID string `json:"id"`
-	}
-
-	DeleteReq struct {
-		ID string `json:"id"`
-	}
-
-	UpdateReq struct {
-		ID        string  `json:"id"`
-		Name      *string `json:"name"`
-		StartDate *string `json:"start_date"`
-		EndDate   *string `json:"end_date"`
-	}
-
-	Config struct {
-		LimPageDef string
-	}
-)
-
-const (
-	ErrMsgInvalidRequestType = "invalid request type"
-)
-
-func MakeEndpoint(s Service, config Config) Endpoint {
-	return Endpoint{
-		Create: makeCreateEndpoint(s),
-		Get:    makeGetEndpoint(s),
-		GetAll: makeGetAllEndpoint(s, config),
-		Update: makeUpdateEndpoint(s),
-		Delete: makeDeleteEndpoint(s),
-	}
-}
-
-func makeCreateEndpoint(s Service) Controller {
-	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req, ok := request.(CreateReq)
-		if !ok {
-			return nil, response.BadRequest(ErrMsgInvalidRequestType)
-		}
-		if req.Name == "" {
-			return nil, response.BadRequest(ErrNameRequired.Error())
-		}
-		if req.StartDate == "" || req.EndDate == "" {
-			return nil, response.BadRequest(ErrStartDateAndEndDateRequired.Error())
-		}
-		course, err := s.Create(ctx, req.Name, req.StartDate, req.EndDate)
-		if err != nil {
-			// 🔧 Errores de validación deben ser BadRequest (400)
-			if errors.Is(err, ErrInvalidStartDate) || errors.Is(err, ErrInvalidEndDate) ||
-				errors.Is(err, ErrStartDateAfterEndDate) || errors.Is(err, ErrEndDateBeforeStartDate) {
-				return nil, response.BadRequest(err.Error())
-			}
-			return nil, response.InternalServerError(err.Error())
-		}
-		return response.Created("Course created successfully", course, nil), nil
-	}
-}
-
-func makeGetEndpoint(s Service) Controller {
-	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req, ok := request.(GetReq)
-		if !ok {
-			return nil, response.BadRequest(ErrMsgInvalidRequestType)
-		}
-		if req.ID == "" {
-			return nil, response.BadRequest(ErrIDRequired.Error())
-		}
-		course, err := s.Get(ctx, req.ID)
-		if err != nil {
-			var notFoundErr *ErrNotFound
-			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
-				return nil, response.NotFound(err.Error())
-			}
-			return nil, response.InternalServerError(err.Error())
-		}
-		return response.OK("Course retrieved successfully", course, nil), nil
-	}
-}
-
-func makeGetAllEndpoint(s Service, config Config) Controller {
-	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req, ok := request.(GetAllReq)
-		if !ok {
-			return nil, response.BadRequest(ErrMsgInvalidRequestType)
-		}
-
-		filters := Filters{
-			Name: req.Name,
-		}
-
-		// Extraemos limit y page directamente del struct GetAllReq
-		// Si los valores son 0 (no proporcionados), usaremos valores por defecto
-		limit := req.Limit
-		page := req.Page
-
-		// 🔧 Validación: si limit es 0, usamos el valor por defecto de la configuración
-		if limit <= 0 {
-			defaultLimit, err := strconv.Atoi(config.LimPageDef)
-			if err != nil {
-				return nil, response.InternalServerError("invalid default limit configuration")
-			}
-			limit = defaultLimit
-		}
-
-		// 🔧 Validación: si page es 0 o negativo, establecemos página 1
-		if page <= 0 {
-			page = 1
-		}
-
-		count, err := s.Count(ctx, filters)
-		if err != nil {
-			return nil, response.InternalServerError("error counting courses: " + err.Error())
-		}
-
-		metaData, err := meta.New(page, limit, int(count), config.LimPageDef)
-		if err != nil {
-			return nil, response.InternalServerError("error generating metadata: " + err.Error())
-		}
-
-		courses, err := s.GetAll(ctx, filters, metaData.Offset(), metaData.Limit())
-		if err != nil {
-			return nil, response.InternalServerError("error retrieving courses: " + err.Error())
-		}
-
-		return response.OK("Courses retrieved successfully", courses, metaData), nil
-	}
-}
-
-func makeUpdateEndpoint(s Service) Controller {
-	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		reqUpdate, ok := request.(UpdateReq)
-		if !ok {
-			return nil, response.BadRequest(ErrMsgInvalidRequestType)
-		}
-		if reqUpdate.ID == "" {
-			return nil, response.BadRequest(ErrIDRequired.Error())
-		}
-		if reqUpdate.Name == nil && reqUpdate.StartDate == nil && reqUpdate.EndDate == nil {
-			return nil, response.BadRequest(ErrAtLeastOneFieldRequired.Error())
-		}
-
-		// 🔧 Validación: si se proporciona un campo, no puede estar vacío
-		if reqUpdate.Name != nil && *reqUpdate.Name == "" {
-			return nil, response.BadRequest(ErrNameRequired.Error())
-		}
-
-		// 🔧 Validación: si se proporciona StartDate, debe tener un formato válido y no estar vacío
-		if reqUpdate.StartDate != nil && *reqUpdate.StartDate == "" {
-			return nil, response.BadRequest(ErrStartDateAndEndDateRequired.Error())
-		}
-
-		// 🔧 Validación: si se proporciona EndDate, debe tener un formato válido y no estar vacío
-		if reqUpdate.EndDate != nil && *reqUpdate.EndDate == "" {
-			return nil, response.BadRequest(ErrStartDateAndEndDateRequired.Error())
-		}
-
-		err := s.Update(ctx, reqUpdate.ID, reqUpdate.Name, reqUpdate.StartDate, reqUpdate.EndDate)
-		if err != nil {
-			var notFoundErr *ErrNotFound
-			// 🔧 Errores de validación deben ser BadRequest (400)
-			if errors.Is(err, ErrInvalidStartDate) || errors.Is(err, ErrInvalidEndDate) ||
-				errors.Is(err, ErrStartDateAfterEndDate) || errors.Is(err, ErrEndDateBeforeStartDate) {
-				return nil, response.BadRequest(err.Error())
-			}
-			// 🔧 Errores de recurso no encontrado deben ser NotFound (404)
-			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
-				return nil, response.NotFound(err.Error())
-			}
-			return nil, response.InternalServerError(err.Error())
-		}
-		return response.OK("Course updated successfully", nil, nil), nil
-	}
-}
-
-func makeDeleteEndpoint(s Service) Controller {
-	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req, ok := request.(DeleteReq)
-		if !ok {
-			return nil, response.BadRequest(ErrMsgInvalidRequestType)
-		}
-		if req.ID == "" {
-			return nil, response.BadRequest(ErrIDRequired.Error())
-		}
-		err := s.Delete(ctx, req.ID)
-		if err != nil {
-			var notFoundErr *ErrNotFound
-			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
-				return nil, response.NotFound(err.Error())
-			}
-			return nil, response.InternalServerError(err.Error())
-		}
-		return response.OK("Course deleted successfully", nil, nil), nil
-	}
-}
+package course
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+
+	"github.com/NicoJCastro/go_lib_response/response"
+	"github.com/NicoJCastro/gocourse_course/pkg/apierror"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+)
+
+type (
+	Controller func(ctx context.Context, request interface{}) (interface{}, error)
+
+	Endpoint struct {
+		Create  Controller
+		Get     Controller
+		GetAll  Controller
+		Update  Controller
+		Replace Controller
+		Delete  Controller
+		Restore Controller
+
+		CreateBatch Controller
+		UpdateBatch Controller
+		DeleteBatch Controller
+
+		Import Controller
+		Export Controller
+
+		History Controller
+		Events  Controller
+		Suggest Controller
+
+		AddPrerequisite    Controller
+		RemovePrerequisite Controller
+		Prerequisites      Controller
+		AssignInstructor   Controller
+		RemoveInstructor   Controller
+		Instructors        Controller
+		SetTranslation     Controller
+		RemoveTranslation  Controller
+		Translations       Controller
+	}
+
+	// CreateReq: start_date y end_date aceptan "2006-01-02" o RFC 3339; timezone
+	// es un nombre IANA (UTC por defecto) en el que se interpretan y se muestran
+	CreateReq struct {
+		Name       string   `json:"name"`
+		StartDate  string   `json:"start_date"`
+		EndDate    string   `json:"end_date"`
+		Timezone   string   `json:"timezone"`
+		CategoryID *string  `json:"category_id"`
+		Tags       []string `json:"tags"`
+		// InstructorIDs son IDs de usuarios; se rechaza si alguno ya da un curso en esas fechas
+		InstructorIDs []string `json:"instructor_ids"`
+	}
+
+	GetAllReq struct {
+		// Q activa la búsqueda de texto completo: el orden pasa a ser por relevancia
+		Q       string `json:"q"`
+		Name    string `json:"name"`
+		Limit   int    `json:"limit"`
+		Page    int    `json:"page"`
+		Deleted string `json:"deleted"`
+		// Tags se combinan según TagMatch ("any" por defecto o "all")
+		Tags     []string `json:"tags"`
+		TagMatch string   `json:"tag_match"`
+		Category string   `json:"category"`
+	}
+
+	GetReq struct {
+		ID string `json:"id"`
+	}
+
+	DeleteReq struct {
+		ID string `json:"id"`
+		// Purge elimina el curso definitivamente; requiere AdminToken
+		Purge      bool   `json:"purge"`
+		AdminToken string `json:"-"`
+	}
+
+	RestoreReq struct {
+		ID string `json:"id"`
+	}
+
+	// UpdateReq: category_id "" quita la categoría y tags [] quita todos los tags.
+	// Cambiar timezone conserva la fecha y hora de las fechas que no se envían.
+	UpdateReq struct {
+		ID         string    `json:"id"`
+		Name       *string   `json:"name"`
+		StartDate  *string   `json:"start_date"`
+		EndDate    *string   `json:"end_date"`
+		Timezone   *string   `json:"timezone"`
+		CategoryID *string   `json:"category_id"`
+		Tags       *[]string `json:"tags"`
+	}
+
+	// PatchReq transporta un documento JSON Merge Patch o JSON Patch sin decodificar
+	PatchReq struct {
+		ID    string
+		Type  PatchType
+		Patch []byte
+	}
+
+	// ReplaceReq representa un PUT: todos los campos son obligatorios y
+	// reemplazan por completo al recurso (a diferencia de UpdateReq)
+	ReplaceReq struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+		// Timezone es opcional: si falta se conserva la del curso
+		Timezone string `json:"timezone"`
+	}
+
+	BatchCreateReq struct {
+		Mode  BatchMode   `json:"mode"`
+		Items []CreateReq `json:"items"`
+	}
+
+	BatchUpdateReq struct {
+		Mode  BatchMode   `json:"mode"`
+		Items []UpdateReq `json:"items"`
+	}
+
+	BatchDeleteReq struct {
+		Mode BatchMode `json:"mode"`
+		IDs  []string  `json:"ids"`
+	}
+
+	ImportReq struct {
+		Format  ImportFormat
+		Data    []byte
+		Mapping ColumnMapping
+		DryRun  bool
+	}
+
+	ExportReq struct {
+		Name     string
+		Deleted  string
+		Tags     []string
+		TagMatch string
+		Category string
+		Format   ExportFormat
+	}
+
+	// ExportResp no es un response.Response: el handler lo escribe en streaming
+	ExportResp struct {
+		Format ExportFormat
+		Write  func(w io.Writer) error
+	}
+
+	EventsReq struct {
+		Name    string
+		Deleted string
+		// Tags y Category se aceptan como en GetAllReq para rechazarlos con un error claro
+		Tags        []string
+		TagMatch    string
+		Category    string
+		LastEventID string
+	}
+
+	// EventsResp no es un response.Response: el handler mantiene abierto el stream SSE
+	EventsResp struct {
+		Feed *ChangeFeed
+	}
+
+	SuggestReq struct {
+		Prefix string `json:"q"`
+		Limit  int    `json:"limit"`
+	}
+
+	HistoryReq struct {
+		ID    string `json:"id"`
+		Limit int    `json:"limit"`
+		Page  int    `json:"page"`
+	}
+
+	// PrerequisiteReq identifica la relación "ID requiere PrerequisiteID"
+	PrerequisiteReq struct {
+		ID             string `json:"id"`
+		PrerequisiteID string `json:"prerequisite_id"`
+	}
+
+	// InstructorReq identifica al usuario UserID como instructor del curso ID
+	InstructorReq struct {
+		ID     string `json:"id"`
+		UserID string `json:"user_id"`
+	}
+
+	// TranslationReq es la traducción del curso ID en Locale (el de la URL)
+	TranslationReq struct {
+		ID          string `json:"id"`
+		Locale      string `json:"locale"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	// UnavailableResponse es el 503 de una base de datos caída; el encoder copia
+	// RetryAfter (segundos) al header Retry-After
+	UnavailableResponse struct {
+		apierror.Response
+		RetryAfter int `json:"retry_after"`
+	}
+
+	// ScheduleConflictResponse es el 409 de un instructor con otro curso en las mismas fechas
+	ScheduleConflictResponse struct {
+		apierror.Response
+		Conflicts []ScheduleConflict `json:"conflicts"`
+	}
+
+	Config struct {
+		Pagination config.Pagination
+		// UpsertOnReplace permite que PUT cree el curso con el ID del cliente si no existe
+		UpsertOnReplace bool
+		// AdminToken habilita DELETE ?purge=true; si está vacío el purge queda deshabilitado
+		AdminToken string
+	}
+)
+
+func MakeEndpoint(s Service, config Config) Endpoint {
+	return Endpoint{
+		Create:  makeCreateEndpoint(s),
+		Get:     makeGetEndpoint(s),
+		GetAll:  makeGetAllEndpoint(s, config),
+		Update:  makeUpdateEndpoint(s),
+		Replace: makeReplaceEndpoint(s, config),
+		Delete:  makeDeleteEndpoint(s, config),
+		Restore: makeRestoreEndpoint(s),
+
+		CreateBatch: makeCreateBatchEndpoint(s),
+		UpdateBatch: makeUpdateBatchEndpoint(s),
+		DeleteBatch: makeDeleteBatchEndpoint(s),
+
+		Import: makeImportEndpoint(s),
+		Export: makeExportEndpoint(s),
+
+		History: makeHistoryEndpoint(s, config),
+		Events:  makeEventsEndpoint(s),
+		Suggest: makeSuggestEndpoint(s),
+
+		AddPrerequisite:    makeAddPrerequisiteEndpoint(s),
+		RemovePrerequisite: makeRemovePrerequisiteEndpoint(s),
+		Prerequisites:      makePrerequisitesEndpoint(s),
+		AssignInstructor:   makeAssignInstructorEndpoint(s),
+		RemoveInstructor:   makeRemoveInstructorEndpoint(s),
+		Instructors:        makeInstructorsEndpoint(s),
+		SetTranslation:     makeSetTranslationEndpoint(s),
+		RemoveTranslation:  makeRemoveTranslationEndpoint(s),
+		Translations:       makeTranslationsEndpoint(s),
+	}
+}
+
+func makeCreateEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(CreateReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.Name == "" {
+			return nil, apierror.BadRequest(ErrNameRequired)
+		}
+		if req.StartDate == "" || req.EndDate == "" {
+			return nil, apierror.BadRequest(ErrStartDateAndEndDateRequired)
+		}
+		course, err := s.Create(ctx, req.Name, req.StartDate, req.EndDate, req.Timezone, req.classification(), req.InstructorIDs)
+		if err != nil {
+			// 🔧 Errores de validación deben ser BadRequest (400)
+			if IsInvalidInput(err) {
+				return nil, apierror.BadRequest(err)
+			}
+			if resp, ok := scheduleConflict(err); ok {
+				return nil, resp
+			}
+			return nil, internalError(err)
+		}
+		return response.Created("Course created successfully", course, nil), nil
+	}
+}
+
+func (r CreateReq) classification() Classification {
+	return Classification{CategoryID: r.CategoryID, Tags: r.Tags}
+}
+
+func (r UpdateReq) classification() Classification {
+	class := Classification{CategoryID: r.CategoryID}
+	if r.Tags != nil {
+		class.Tags = *r.Tags
+		if class.Tags == nil {
+			class.Tags = []string{}
+		}
+	}
+	return class
+}
+
+// isClassificationError indica errores de categoría o tags enviados por el cliente
+func isClassificationError(err error) bool {
+	return errors.Is(err, ErrCategoryNotFound) || errors.Is(err, ErrInvalidTag) || errors.Is(err, ErrTooManyTags)
+}
+
+// IsInvalidInput indica si Create rechazó los datos del curso (400 en la API,
+// exit code de validación en el CLI)
+func IsInvalidInput(err error) bool {
+	return errors.Is(err, ErrInvalidStartDate) || errors.Is(err, ErrInvalidEndDate) ||
+		errors.Is(err, ErrStartDateAfterEndDate) || errors.Is(err, ErrEndDateBeforeStartDate) ||
+		errors.Is(err, ErrInvalidTimezone) || errors.Is(err, ErrInvalidUserID) ||
+		errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrCreatorNotFound) ||
+		isClassificationError(err)
+}
+
+func makeGetEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		course, err := s.Get(ctx, req.ID)
+		if err != nil {
+			var notFoundErr *ErrNotFound
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Course retrieved successfully", course, nil), nil
+	}
+}
+
+func makeGetAllEndpoint(s Service, config Config) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetAllReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+
+		filters, err := listFilters(req.Name, req.Deleted, req.Tags, req.TagMatch, req.Category)
+		if err != nil {
+			return nil, err
+		}
+
+		// Extraemos limit y page directamente del struct GetAllReq
+		// 🔧 Sin limit se usa el default de la configuración, y nunca más que el máximo
+		limit := config.Pagination.Limit(req.Limit)
+		page := req.Page
+
+		// 🔧 Validación: si page es 0 o negativo, establecemos página 1
+		if page <= 0 {
+			page = 1
+		}
+
+		if req.Q != "" {
+			return searchCourses(ctx, s, config, req.Q, filters, page, limit)
+		}
+
+		count, err := s.Count(ctx, filters)
+		if err != nil {
+			return nil, internalError(fmt.Errorf("error counting courses: %w", err))
+		}
+
+		metaData, err := config.Pagination.Meta(page, limit, int(count))
+		if err != nil {
+			return nil, apierror.InternalServerError(fmt.Errorf("%w: %w", ErrFailedToGenerateMetadata, err))
+		}
+
+		courses, err := s.GetAll(ctx, filters, metaData.Offset(), metaData.Limit())
+		if err != nil {
+			return nil, internalError(fmt.Errorf("error retrieving courses: %w", err))
+		}
+
+		return response.OK("Courses retrieved successfully", courses, metaData), nil
+	}
+}
+
+// listFilters arma y valida los filtros de GET /courses, que también usa la exportación
+func listFilters(name, deleted string, tags []string, tagMatch, category string) (Filters, error) {
+	filters := Filters{
+		Name:     name,
+		Deleted:  DeletedScope(deleted),
+		Tags:     tags,
+		TagMatch: TagMatch(tagMatch),
+		Category: category,
+	}
+	if filters.Deleted != DeletedExclude && filters.Deleted != DeletedInclude && filters.Deleted != DeletedOnly {
+		return Filters{}, apierror.BadRequest(ErrInvalidDeletedScope)
+	}
+	if filters.TagMatch == "" {
+		filters.TagMatch = TagMatchAny
+	}
+	if filters.TagMatch != TagMatchAny && filters.TagMatch != TagMatchAll {
+		return Filters{}, apierror.BadRequest(ErrInvalidTagMatch)
+	}
+	return filters, nil
+}
+
+// searchCourses resuelve GET /courses?q=: el total sale del backend de búsqueda, así
+// que la metadata se arma después de la consulta
+func searchCourses(ctx context.Context, s Service, config Config, q string, filters Filters, page, limit int) (interface{}, error) {
+	pageMeta, err := config.Pagination.Meta(page, limit, 0)
+	if err != nil {
+		return nil, apierror.InternalServerError(fmt.Errorf("%w: %w", ErrFailedToGenerateMetadata, err))
+	}
+
+	hits, total, err := s.Search(ctx, q, filters, (page-1)*pageMeta.Limit(), pageMeta.Limit())
+	if err != nil {
+		if errors.Is(err, ErrSearchQueryRequired) || errors.Is(err, ErrSearchQueryTooLong) || errors.Is(err, ErrSearchFilterUnsupported) {
+			return nil, apierror.BadRequest(err)
+		}
+		return nil, internalError(err)
+	}
+
+	metaData, err := config.Pagination.Meta(page, limit, int(total))
+	if err != nil {
+		return nil, apierror.InternalServerError(fmt.Errorf("%w: %w", ErrFailedToGenerateMetadata, err))
+	}
+	return response.OK("Courses retrieved successfully", hits, metaData), nil
+}
+
+func makeUpdateEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		// 🔧 Los documentos RFC 7386 / RFC 6902 se aplican sobre el curso almacenado
+		if reqPatch, ok := request.(PatchReq); ok {
+			return patchCourse(ctx, s, reqPatch)
+		}
+
+		reqUpdate, ok := request.(UpdateReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if reqUpdate.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if reqUpdate.Name == nil && reqUpdate.StartDate == nil && reqUpdate.EndDate == nil &&
+			reqUpdate.Timezone == nil && reqUpdate.classification().empty() {
+			return nil, apierror.BadRequest(ErrAtLeastOneFieldRequired)
+		}
+
+		// 🔧 Validación: si se proporciona un campo, no puede estar vacío
+		if reqUpdate.Name != nil && *reqUpdate.Name == "" {
+			return nil, apierror.BadRequest(ErrNameRequired)
+		}
+
+		// 🔧 Validación: si se proporciona StartDate, debe tener un formato válido y no estar vacío
+		if reqUpdate.StartDate != nil && *reqUpdate.StartDate == "" {
+			return nil, apierror.BadRequest(ErrStartDateAndEndDateRequired)
+		}
+
+		// 🔧 Validación: si se proporciona EndDate, debe tener un formato válido y no estar vacío
+		if reqUpdate.EndDate != nil && *reqUpdate.EndDate == "" {
+			return nil, apierror.BadRequest(ErrStartDateAndEndDateRequired)
+		}
+
+		err := s.Update(ctx, reqUpdate.ID, reqUpdate.Name, reqUpdate.StartDate, reqUpdate.EndDate, reqUpdate.Timezone, reqUpdate.classification())
+		if err != nil {
+			var notFoundErr *ErrNotFound
+			// 🔧 Errores de validación deben ser BadRequest (400)
+			if errors.Is(err, ErrInvalidStartDate) || errors.Is(err, ErrInvalidEndDate) ||
+				errors.Is(err, ErrStartDateAfterEndDate) || errors.Is(err, ErrEndDateBeforeStartDate) ||
+				errors.Is(err, ErrInvalidTimezone) || isClassificationError(err) {
+				return nil, apierror.BadRequest(err)
+			}
+			// 🔧 Mover las fechas dejando sesiones afuera es un conflicto con el estado actual
+			if errors.Is(err, ErrSessionsOutsideCourse) {
+				return nil, apierror.Conflict(err)
+			}
+			if resp, ok := scheduleConflict(err); ok {
+				return nil, resp
+			}
+			// 🔧 Errores de recurso no encontrado deben ser NotFound (404)
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Course updated successfully", nil, nil), nil
+	}
+}
+
+func patchCourse(ctx context.Context, s Service, req PatchReq) (interface{}, error) {
+	if req.ID == "" {
+		return nil, apierror.BadRequest(ErrIDRequired)
+	}
+	if len(req.Patch) == 0 {
+		return nil, apierror.BadRequest(ErrInvalidPatch)
+	}
+
+	course, err := s.Patch(ctx, req.ID, req.Type, req.Patch)
+	if err != nil {
+		var notFoundErr *ErrNotFound
+		// 🔧 Un "test" fallido indica que el recurso cambió: 409 Conflict
+		if errors.Is(err, ErrPatchTestFailed) || errors.Is(err, ErrSessionsOutsideCourse) {
+			return nil, apierror.Conflict(err)
+		}
+		if resp, ok := scheduleConflict(err); ok {
+			return nil, resp
+		}
+		if errors.Is(err, ErrInvalidStartDate) || errors.Is(err, ErrInvalidEndDate) ||
+			errors.Is(err, ErrStartDateAfterEndDate) || errors.Is(err, ErrEndDateBeforeStartDate) ||
+			errors.Is(err, ErrInvalidPatch) || errors.Is(err, ErrUnsupportedPatchType) ||
+			errors.Is(err, ErrIDImmutable) || errors.Is(err, ErrNameRequired) ||
+			errors.Is(err, ErrStartDateAndEndDateRequired) {
+			return nil, apierror.BadRequest(err)
+		}
+		if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+			return nil, apierror.NotFound(err)
+		}
+		return nil, internalError(err)
+	}
+	return response.OK("Course updated successfully", course, nil), nil
+}
+
+// internalError responde 503 si la base de datos no está disponible y 500 en otro caso
+func internalError(err error) response.Response {
+	var unavailable *ErrUnavailable
+	if !errors.As(err, &unavailable) {
+		return apierror.InternalServerError(err)
+	}
+	retryAfter := int(math.Ceil(unavailable.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	return &UnavailableResponse{
+		Response:   *apierror.New(http.StatusServiceUnavailable, err),
+		RetryAfter: retryAfter,
+	}
+}
+
+// GetBody incluye retry_after, que ErrorResponse.GetBody dejaría afuera
+func (r *UnavailableResponse) GetBody() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// scheduleConflict traduce un ErrScheduleConflict a un 409 que lista los cursos en conflicto
+func scheduleConflict(err error) (response.Response, bool) {
+	var conflictErr *ErrScheduleConflict
+	if !errors.As(err, &conflictErr) {
+		return nil, false
+	}
+	return &ScheduleConflictResponse{
+		Response:  *apierror.Conflict(err),
+		Conflicts: conflictErr.Conflicts,
+	}, true
+}
+
+// GetBody incluye los conflictos, que ErrorResponse.GetBody dejaría afuera
+func (r *ScheduleConflictResponse) GetBody() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// makeReplaceEndpoint implementa PUT: a diferencia de PATCH, exige name,
+// start_date y end_date y sobrescribe el recurso completo.
+func makeReplaceEndpoint(s Service, config Config) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(ReplaceReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if req.Name == "" {
+			return nil, apierror.BadRequest(ErrNameRequired)
+		}
+		if req.StartDate == "" || req.EndDate == "" {
+			return nil, apierror.BadRequest(ErrStartDateAndEndDateRequired)
+		}
+
+		course, created, err := s.Replace(ctx, req.ID, req.Name, req.StartDate, req.EndDate, req.Timezone, config.UpsertOnReplace)
+		if err != nil {
+			var notFoundErr *ErrNotFound
+			// 🔧 Errores de validación deben ser BadRequest (400)
+			if errors.Is(err, ErrInvalidStartDate) || errors.Is(err, ErrInvalidEndDate) ||
+				errors.Is(err, ErrStartDateAfterEndDate) || errors.Is(err, ErrInvalidID) ||
+				errors.Is(err, ErrInvalidTimezone) || errors.Is(err, ErrCreatorNotFound) {
+				return nil, apierror.BadRequest(err)
+			}
+			if errors.Is(err, ErrSessionsOutsideCourse) {
+				return nil, apierror.Conflict(err)
+			}
+			if resp, ok := scheduleConflict(err); ok {
+				return nil, resp
+			}
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		if created {
+			return response.Created("Course created successfully", course, nil), nil
+		}
+		return response.OK("Course replaced successfully", course, nil), nil
+	}
+}
+
+func makeDeleteEndpoint(s Service, config Config) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(DeleteReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+
+		if req.Purge {
+			// 🔧 El borrado definitivo sólo está permitido con el token de administrador
+			if config.AdminToken == "" ||
+				subtle.ConstantTimeCompare([]byte(req.AdminToken), []byte(config.AdminToken)) != 1 {
+				return nil, apierror.Forbidden(ErrPurgeForbidden)
+			}
+			if err := s.Purge(ctx, req.ID); err != nil {
+				var notFoundErr *ErrNotFound
+				if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+					return nil, apierror.NotFound(err)
+				}
+				if errors.Is(err, ErrCourseIsPrerequisite) {
+					return nil, apierror.Conflict(err)
+				}
+				return nil, internalError(err)
+			}
+			return response.OK("Course purged successfully", nil, nil), nil
+		}
+
+		err := s.Delete(ctx, req.ID)
+		if err != nil {
+			var notFoundErr *ErrNotFound
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			// 🔧 Con la política restrict, borrar un prerequisito en uso es un conflicto
+			if errors.Is(err, ErrCourseIsPrerequisite) {
+				return nil, apierror.Conflict(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Course deleted successfully", nil, nil), nil
+	}
+}
+
+func makeRestoreEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(RestoreReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		course, err := s.Restore(ctx, req.ID)
+		if err != nil {
+			var notFoundErr *ErrNotFound
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Course restored successfully", course, nil), nil
+	}
+}
+
+func makeCreateBatchEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(BatchCreateReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+
+		items := make([]BatchCreateItem, len(req.Items))
+		for i, item := range req.Items {
+			items[i] = BatchCreateItem{Name: item.Name, StartDate: item.StartDate, EndDate: item.EndDate, Timezone: item.Timezone, Classification: item.classification(), InstructorIDs: item.InstructorIDs}
+		}
+
+		results, err := s.CreateBatch(ctx, items, req.Mode)
+		return batchResponse("Courses created successfully", http.StatusCreated, results, err)
+	}
+}
+
+func makeUpdateBatchEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(BatchUpdateReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+
+		items := make([]BatchUpdateItem, len(req.Items))
+		for i, item := range req.Items {
+			items[i] = BatchUpdateItem{ID: item.ID, Name: item.Name, StartDate: item.StartDate, EndDate: item.EndDate, Timezone: item.Timezone, Classification: item.classification()}
+		}
+
+		results, err := s.UpdateBatch(ctx, items, req.Mode)
+		return batchResponse("Courses updated successfully", http.StatusOK, results, err)
+	}
+}
+
+func makeDeleteBatchEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(BatchDeleteReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+
+		results, err := s.DeleteBatch(ctx, req.IDs, req.Mode)
+		return batchResponse("Courses deleted successfully", http.StatusOK, results, err)
+	}
+}
+
+// batchResponse traduce el resultado de un lote: okStatus si todo salió bien,
+// 207 si en best_effort falló algún ítem y 422 si el lote atómico se revirtió.
+// En todos los casos el cuerpo incluye el resultado por ítem.
+func batchResponse(msg string, okStatus int, results []BatchResult, err error) (interface{}, error) {
+	if err != nil {
+		if errors.Is(err, ErrBatchRolledBack) {
+			return &response.SuccessResponse{
+				Message: err.Error(),
+				Status:  http.StatusUnprocessableEntity,
+				Data:    results,
+			}, nil
+		}
+		if errors.Is(err, ErrBatchEmpty) || errors.Is(err, ErrBatchTooLarge) || errors.Is(err, ErrInvalidBatchMode) {
+			return nil, apierror.BadRequest(err)
+		}
+		return nil, internalError(err)
+	}
+
+	for _, result := range results {
+		if result.Status == BatchStatusFailed {
+			return &response.SuccessResponse{
+				Message: "Batch processed with errors",
+				Status:  http.StatusMultiStatus,
+				Data:    results,
+			}, nil
+		}
+	}
+	return &response.SuccessResponse{Message: msg, Status: okStatus, Data: results}, nil
+}
+
+func makeImportEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(ImportReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+
+		rows, err := ParseImport(req.Format, bytes.NewReader(req.Data), req.Mapping)
+		if err != nil {
+			return nil, apierror.BadRequest(err)
+		}
+
+		report, err := s.Import(ctx, rows, req.DryRun)
+		if err != nil {
+			if errors.Is(err, ErrCreatorNotFound) {
+				return nil, apierror.BadRequest(err)
+			}
+			return nil, internalError(err)
+		}
+		if req.DryRun {
+			return response.OK("Import validated successfully", report, nil), nil
+		}
+		return response.Created("Courses imported successfully", report, nil), nil
+	}
+}
+
+func makeExportEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(ExportReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+
+		switch req.Format {
+		case ExportCSV, ExportNDJSON, ExportICS:
+		default:
+			return nil, apierror.BadRequest(ErrUnsupportedExportFormat)
+		}
+
+		filters, err := listFilters(req.Name, req.Deleted, req.Tags, req.TagMatch, req.Category)
+		if err != nil {
+			return nil, err
+		}
+
+		// La consulta se ejecuta recién cuando el handler escribe la respuesta
+		return ExportResp{
+			Format: req.Format,
+			Write: func(w io.Writer) error {
+				ew, err := NewExportWriter(req.Format, w)
+				if err != nil {
+					return err
+				}
+				err = s.Export(ctx, filters, func(course TenantCourse) error {
+					return ew.Write(course.Course)
+				})
+				if err != nil {
+					return err
+				}
+				return ew.Close()
+			},
+		}, nil
+	}
+}
+
+func makeHistoryEndpoint(s Service, config Config) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(HistoryReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+
+		limit := config.Pagination.Limit(req.Limit)
+		page := req.Page
+		if page <= 0 {
+			page = 1
+		}
+
+		count, err := s.CountHistory(ctx, req.ID)
+		if err != nil {
+			return nil, internalError(err)
+		}
+
+		metaData, err := config.Pagination.Meta(page, limit, int(count))
+		if err != nil {
+			return nil, apierror.InternalServerError(fmt.Errorf("%w: %w", ErrFailedToGenerateMetadata, err))
+		}
+
+		entries, err := s.History(ctx, req.ID, metaData.Offset(), metaData.Limit())
+		if err != nil {
+			return nil, internalError(err)
+		}
+		return response.OK("Course history retrieved successfully", entries, metaData), nil
+	}
+}
+
+func makeEventsEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(EventsReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+
+		filters, err := listFilters(req.Name, req.Deleted, req.Tags, req.TagMatch, req.Category)
+		if err != nil {
+			return nil, err
+		}
+
+		feed, err := s.Changes(ctx, req.LastEventID, filters)
+		if err != nil {
+			if errors.Is(err, ErrChangeFilterUnsupported) {
+				return nil, apierror.BadRequest(err)
+			}
+			return nil, internalError(err)
+		}
+		return EventsResp{Feed: feed}, nil
+	}
+}
+
+func makeSuggestEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(SuggestReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+
+		suggestions, err := s.Suggest(ctx, req.Prefix, req.Limit)
+		if err != nil {
+			if errors.Is(err, ErrSearchQueryRequired) || errors.Is(err, ErrSearchQueryTooLong) {
+				return nil, apierror.BadRequest(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Suggestions retrieved successfully", suggestions, nil), nil
+	}
+}
+
+func makeAddPrerequisiteEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(PrerequisiteReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if req.PrerequisiteID == "" {
+			return nil, apierror.BadRequest(ErrPrerequisiteIDRequired)
+		}
+
+		tree, err := s.AddPrerequisite(ctx, req.ID, req.PrerequisiteID)
+		if err != nil {
+			var notFoundErr *ErrNotFound
+			if errors.Is(err, ErrSelfPrerequisite) {
+				return nil, apierror.BadRequest(err)
+			}
+			if errors.Is(err, ErrPrerequisiteCycle) {
+				return nil, apierror.Conflict(err)
+			}
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.Created("Prerequisite added successfully", tree, nil), nil
+	}
+}
+
+func makeRemovePrerequisiteEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(PrerequisiteReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if req.PrerequisiteID == "" {
+			return nil, apierror.BadRequest(ErrPrerequisiteIDRequired)
+		}
+
+		if err := s.RemovePrerequisite(ctx, req.ID, req.PrerequisiteID); err != nil {
+			if errors.Is(err, ErrPrerequisiteNotFound) || errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Prerequisite removed successfully", nil, nil), nil
+	}
+}
+
+func makePrerequisitesEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+
+		tree, err := s.Prerequisites(ctx, req.ID)
+		if err != nil {
+			var notFoundErr *ErrNotFound
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Prerequisites retrieved successfully", tree, nil), nil
+	}
+}
+
+func makeAssignInstructorEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(InstructorReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if req.UserID == "" {
+			return nil, apierror.BadRequest(ErrUserIDRequired)
+		}
+
+		instructors, err := s.AssignInstructor(ctx, req.ID, req.UserID)
+		if err != nil {
+			var notFoundErr *ErrNotFound
+			if errors.Is(err, ErrInvalidUserID) {
+				return nil, apierror.BadRequest(err)
+			}
+			// 🔧 El instructor ya da otro curso en esas fechas: 409 con los cursos en conflicto
+			if resp, ok := scheduleConflict(err); ok {
+				return nil, resp
+			}
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) ||
+				errors.Is(err, ErrUserNotFound) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.Created("Instructor assigned successfully", instructors, nil), nil
+	}
+}
+
+func makeRemoveInstructorEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(InstructorReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if req.UserID == "" {
+			return nil, apierror.BadRequest(ErrUserIDRequired)
+		}
+
+		if err := s.RemoveInstructor(ctx, req.ID, req.UserID); err != nil {
+			if errors.Is(err, ErrInstructorNotFound) || errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Instructor removed successfully", nil, nil), nil
+	}
+}
+
+func makeInstructorsEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+
+		instructors, err := s.Instructors(ctx, req.ID)
+		if err != nil {
+			var notFoundErr *ErrNotFound
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Instructors retrieved successfully", instructors, nil), nil
+	}
+}
+
+func makeSetTranslationEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(TranslationReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+
+		translation, err := s.SetTranslation(ctx, req.ID, req.Locale, req.Name, req.Description)
+		if err != nil {
+			if isTranslationError(err) {
+				return nil, apierror.BadRequest(err)
+			}
+			if errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Translation saved successfully", translation, nil), nil
+	}
+}
+
+func makeRemoveTranslationEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(TranslationReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+
+		if err := s.RemoveTranslation(ctx, req.ID, req.Locale); err != nil {
+			if errors.Is(err, ErrUnsupportedLocale) {
+				return nil, apierror.BadRequest(err)
+			}
+			if errors.Is(err, ErrTranslationNotFound) || errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Translation removed successfully", nil, nil), nil
+	}
+}
+
+func makeTranslationsEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+
+		translations, err := s.Translations(ctx, req.ID)
+		if err != nil {
+			if errors.Is(err, ErrNotFoundBase) {
+				return nil, apierror.NotFound(err)
+			}
+			return nil, internalError(err)
+		}
+		return response.OK("Translations retrieved successfully", translations, nil), nil
+	}
+}
+
+func isTranslationError(err error) bool {
+	return errors.Is(err, ErrUnsupportedLocale) || errors.Is(err, ErrNameRequired) ||
+		errors.Is(err, ErrNameTooLong) || errors.Is(err, ErrDescriptionTooLong)
+}
@@ -0,0 +1,77 @@
+package course_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/NicoJCastro/go_lib_response/response"
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+)
+
+func TestHistoryPagination(t *testing.T) {
+	svc, _, _ := newTenantService(t)
+	ctxA, ctxB := tenanttest.Contexts()
+	created, err := svc.Create(ctxA, "Go 0", "2024-06-01", "2024-06-30", "", course.Classification{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 4; i++ {
+		name := fmt.Sprintf("Go %d", i)
+		if err := svc.Update(ctxA, created.ID, &name, nil, nil, nil, course.Classification{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	endpoints := course.MakeEndpoint(svc, course.Config{Pagination: config.Pagination{DefaultLimit: 2, MaxLimit: 100}})
+	history := func(t *testing.T, page int) ([]audit.Entry, int, int) {
+		t.Helper()
+		resp, err := endpoints.History(ctxA, course.HistoryReq{ID: created.ID, Page: page})
+		if err != nil {
+			t.Fatal(err)
+		}
+		success := resp.(*response.SuccessResponse)
+		return success.Data.([]audit.Entry), success.Meta.TotalCount, success.Meta.Page
+	}
+
+	// Del más nuevo al más viejo: la última página tiene sólo el alta
+	var names []string
+	for page := 1; page <= 3; page++ {
+		entries, total, _ := history(t, page)
+		if total != 5 {
+			t.Errorf("page %d total = %d, want 5", page, total)
+		}
+		for _, entry := range entries {
+			var after struct{ Name string }
+			if err := json.Unmarshal(entry.After, &after); err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, after.Name)
+		}
+	}
+	if want := "[Go 4 Go 3 Go 2 Go 1 Go 0]"; fmt.Sprint(names) != want {
+		t.Errorf("names = %v, want %s", names, want)
+	}
+
+	entries, _, page := history(t, 9)
+	if page != 3 || len(entries) != 1 || entries[0].Action != audit.ActionCreate {
+		t.Errorf("page 9 = page %d with %+v, want the last page", page, entries)
+	}
+
+	// Cada actualización registra sólo lo que cambió
+	var changes map[string]audit.Change
+	first, _, _ := history(t, 1)
+	if err := json.Unmarshal(first[0].Changes, &changes); err != nil {
+		t.Fatal(err)
+	}
+	if change, ok := changes["name"]; !ok || change.From != "Go 3" || change.To != "Go 4" || len(changes) != 1 {
+		t.Errorf("changes = %+v, want only name from Go 3 to Go 4", changes)
+	}
+
+	if count, err := svc.CountHistory(ctxB, created.ID); err != nil || count != 0 {
+		t.Errorf("history from another tenant = %d, %v; want 0", count, err)
+	}
+}
@@ -0,0 +1,298 @@
+package course
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/outbox"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+	"github.com/xuri/excelize/v2"
+)
+
+type (
+	// ImportFormat identifica el tipo de archivo recibido en /courses/import
+	ImportFormat string
+
+	// ColumnMapping asocia cada campo del curso (name, start_date, end_date)
+	// con el encabezado de la columna en el archivo
+	ColumnMapping map[string]string
+
+	ImportRow struct {
+		Row       int
+		Name      string
+		StartDate string
+		EndDate   string
+	}
+
+	ImportRowError struct {
+		Row   int    `json:"row"`
+		Error string `json:"error"`
+	}
+
+	ImportReport struct {
+		DryRun       bool             `json:"dry_run"`
+		TotalRows    int              `json:"total_rows"`
+		ValidRows    int              `json:"valid_rows"`
+		ImportedRows int              `json:"imported_rows"`
+		Errors       []ImportRowError `json:"errors"`
+	}
+)
+
+const (
+	ImportCSV  ImportFormat = "csv"
+	ImportXLSX ImportFormat = "xlsx"
+
+	// ImportBatchSize es la cantidad de filas insertadas por sentencia INSERT
+	ImportBatchSize = 100
+)
+
+var importFields = []string{"name", "start_date", "end_date"}
+
+// ParseImport lee un archivo CSV o XLSX y devuelve sus filas según el mapeo de columnas.
+// La primera fila debe ser el encabezado. Row es el número de fila del archivo (1 = encabezado).
+func ParseImport(format ImportFormat, r io.Reader, mapping ColumnMapping) ([]ImportRow, error) {
+	var records [][]string
+	// lines es la fila del archivo de cada registro, si no coincide con su posición
+	var lines []int
+	var err error
+
+	switch format {
+	case ImportCSV:
+		records, lines, err = readCSV(r)
+	case ImportXLSX:
+		records, err = readXLSX(r)
+	default:
+		return nil, ErrUnsupportedImportFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+	}
+	if len(records) == 0 {
+		return nil, ErrImportFileEmpty
+	}
+
+	columns, err := resolveColumns(records[0], mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]ImportRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+		line := i + 2
+		if lines != nil {
+			line = lines[i+1]
+		}
+		row := ImportRow{
+			Row:       line,
+			Name:      cell(record, columns["name"]),
+			StartDate: cell(record, columns["start_date"]),
+			EndDate:   cell(record, columns["end_date"]),
+		}
+		if format == ImportXLSX {
+			row.StartDate = normalizeXLSXDate(row.StartDate)
+			row.EndDate = normalizeXLSXDate(row.EndDate)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Import valida cada fila con las reglas de Create e inserta las válidas en lotes.
+// En dryRun sólo se valida y no se escribe nada en la base de datos.
+func (s service) Import(ctx context.Context, rows []ImportRow, dryRun bool) (*ImportReport, error) {
+	s.log.Println("---- Importing courses ----")
+	if err := s.verifyCreator(ctx); err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		Errors:    []ImportRowError{},
+	}
+
+	courses := make([]*domain.Course, 0, len(rows))
+	for _, row := range rows {
+		if row.Name == "" {
+			report.Errors = append(report.Errors, ImportRowError{Row: row.Row, Error: ErrNameRequired.Error()})
+			continue
+		}
+		if row.StartDate == "" || row.EndDate == "" {
+			report.Errors = append(report.Errors, ImportRowError{Row: row.Row, Error: ErrStartDateAndEndDateRequired.Error()})
+			continue
+		}
+		// Los cursos importados quedan en UTC, igual que un curso creado sin timezone
+		course, err := s.buildCourse(row.Name, row.StartDate, row.EndDate, time.UTC)
+		if err != nil {
+			report.Errors = append(report.Errors, ImportRowError{Row: row.Row, Error: err.Error()})
+			continue
+		}
+		courses = append(courses, course)
+	}
+	report.ValidRows = len(courses)
+
+	if dryRun || len(courses) == 0 {
+		return report, nil
+	}
+
+	err := s.repo.Transaction(ctx, func(txRepo Repository) error {
+		if err := txRepo.CreateInBatches(ctx, courses, ImportBatchSize); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToImportCourses, err)
+		}
+
+		entries := make([]*audit.Entry, 0, len(courses))
+		events := make([]*outbox.Message, 0, len(courses))
+		now := time.Now().UTC()
+		for _, course := range courses {
+			entry, err := audit.NewEntry(ctx, AuditEntityCourse, course.ID, audit.ActionCreate, nil, course)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrFailedToRecordAudit, err)
+			}
+			entries = append(entries, entry)
+
+			event, err := outbox.NewMessage(ctx, EventCourseCreated, AuditEntityCourse, course.ID, newCourseCreated(course, now))
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrFailedToRecordEvents, err)
+			}
+			events = append(events, event)
+		}
+		if err := txRepo.RecordAudit(ctx, entries...); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToRecordAudit, err)
+		}
+		if err := txRepo.RecordEvents(ctx, events...); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToRecordEvents, err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Printf("Error importing courses: %v\n", err)
+		return nil, err
+	}
+	report.ImportedRows = len(courses)
+	return report, nil
+}
+
+// ParseColumnMapping interpreta "name:Nombre,start_date:Inicio" como un ColumnMapping
+func ParseColumnMapping(value string) (ColumnMapping, error) {
+	mapping := ColumnMapping{}
+	if strings.TrimSpace(value) == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		field, header, ok := strings.Cut(pair, ":")
+		field = strings.TrimSpace(field)
+		if !ok || !isImportField(field) || strings.TrimSpace(header) == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidColumnMapping, pair)
+		}
+		mapping[field] = strings.TrimSpace(header)
+	}
+	return mapping, nil
+}
+
+func resolveColumns(header []string, mapping ColumnMapping) (map[string]int, error) {
+	columns := make(map[string]int, len(importFields))
+	for _, field := range importFields {
+		want := field
+		if custom, ok := mapping[field]; ok {
+			want = custom
+		}
+		idx := -1
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")), want) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrImportColumnMissing, want)
+		}
+		columns[field] = idx
+	}
+	return columns, nil
+}
+
+// readCSV devuelve también la línea de cada registro: el lector de CSV salta las líneas vacías
+func readCSV(r io.Reader) ([][]string, []int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, lines, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, ErrWorkbookWithoutSheets
+	}
+
+	// 🔧 Valores crudos para que las fechas lleguen como número de serie y no
+	// con el formato regional de la planilla
+	return f.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+}
+
+// normalizeXLSXDate convierte números de serie de Excel a "2006-01-02"
+func normalizeXLSXDate(value string) string {
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil || serial <= 0 {
+		return value
+	}
+	date, err := excelize.ExcelDateToTime(serial, false)
+	if err != nil {
+		return value
+	}
+	return date.UTC().Truncate(24 * time.Hour).Format("2006-01-02")
+}
+
+func cell(record []string, idx int) string {
+	if idx >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[idx])
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func isImportField(field string) bool {
+	for _, f := range importFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
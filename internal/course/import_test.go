@@ -0,0 +1,138 @@
+package course_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+	"github.com/xuri/excelize/v2"
+)
+
+// importFile tiene BOM, columnas renombradas, una línea en blanco y una fila por cada error
+const importFile = "\ufeffNombre,Inicio,Fin\n" +
+	"Go basics,2024-06-01,2024-06-30\n" +
+	",2024-06-01,2024-06-30\n" +
+	"\n" +
+	"No end,2024-06-01,\n" +
+	"Bad date,2024-13-01,2024-06-30\n" +
+	"Go advanced,2024-07-01,2024-07-31\n"
+
+func TestImport(t *testing.T) {
+	mapping, err := course.ParseColumnMapping("name:Nombre, start_date:Inicio,end_date:Fin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := course.ParseImport(course.ImportCSV, strings.NewReader(importFile), mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 || rows[4].Row != 7 {
+		t.Fatalf("rows = %+v, want 5 without the blank line, numbered as in the file", rows)
+	}
+
+	svc, _, db := newTenantService(t)
+	ctx, _ := tenanttest.Contexts()
+
+	t.Run("dry run", func(t *testing.T) {
+		report, err := svc.Import(ctx, rows, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.DryRun || report.TotalRows != 5 || report.ValidRows != 2 || report.ImportedRows != 0 {
+			t.Errorf("report = %+v, want 5 rows, 2 valid and none imported", report)
+		}
+		wantErrors := map[int]string{
+			3: course.ErrNameRequired.Error(),
+			5: course.ErrStartDateAndEndDateRequired.Error(),
+			6: course.ErrInvalidStartDate.Error(),
+		}
+		if len(report.Errors) != len(wantErrors) {
+			t.Fatalf("errors = %+v, want rows 3, 5 and 6", report.Errors)
+		}
+		for _, rowErr := range report.Errors {
+			want, ok := wantErrors[rowErr.Row]
+			if !ok || !strings.HasPrefix(rowErr.Error, want) {
+				t.Errorf("row %d error = %q, want %q", rowErr.Row, rowErr.Error, want)
+			}
+		}
+		if count, err := svc.Count(ctx, course.Filters{Deleted: course.DeletedInclude}); err != nil || count != 0 {
+			t.Errorf("courses after a dry run = %d, %v; want 0", count, err)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		report, err := svc.Import(ctx, rows, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.DryRun || report.ValidRows != 2 || report.ImportedRows != 2 || len(report.Errors) != 3 {
+			t.Errorf("report = %+v, want 2 imported and 3 errors", report)
+		}
+		if count, err := svc.Count(ctx, course.Filters{Deleted: course.DeletedInclude}); err != nil || count != 2 {
+			t.Errorf("courses = %d, %v; want 2", count, err)
+		}
+		var entries int64
+		if err := db.Model(&audit.Entry{}).Where("action = ?", audit.ActionCreate).Count(&entries).Error; err != nil || entries != 2 {
+			t.Errorf("audit entries = %d, %v; want one per imported course", entries, err)
+		}
+	})
+}
+
+func TestParseImport(t *testing.T) {
+	t.Run("xlsx serial dates", func(t *testing.T) {
+		f := excelize.NewFile()
+		sheet := f.GetSheetName(0)
+		for cell, value := range map[string]interface{}{
+			"A1": "name", "B1": "start_date", "C1": "end_date",
+			"A2": "Go basics", "B2": 45444, "C2": "2024-06-30",
+		} {
+			if err := f.SetCellValue(sheet, cell, value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var buf bytes.Buffer
+		if err := f.Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		rows, err := course.ParseImport(course.ImportXLSX, &buf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 1 || rows[0].StartDate != "2024-06-01" || rows[0].EndDate != "2024-06-30" {
+			t.Errorf("rows = %+v, want the serial date as 2024-06-01", rows)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		format  course.ImportFormat
+		content string
+		mapping string
+		err     error
+	}{
+		{"missing column", course.ImportCSV, "name,start_date\nGo,2024-06-01\n", "", course.ErrImportColumnMissing},
+		{"mapped column missing", course.ImportCSV, "name,start_date,end_date\n", "name:Nombre", course.ErrImportColumnMissing},
+		{"empty file", course.ImportCSV, "", "", course.ErrImportFileEmpty},
+		{"unsupported format", "json", "[]", "", course.ErrUnsupportedImportFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := course.ParseColumnMapping(tt.mapping)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := course.ParseImport(tt.format, strings.NewReader(tt.content), mapping); !errors.Is(err, tt.err) {
+				t.Errorf("ParseImport = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	if _, err := course.ParseColumnMapping("title:Nombre"); !errors.Is(err, course.ErrInvalidColumnMapping) {
+		t.Errorf("ParseColumnMapping(title) = %v, want %v", err, course.ErrInvalidColumnMapping)
+	}
+}
@@ -0,0 +1,171 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Instructor asigna un usuario (ID del servicio de usuarios) como instructor del curso
+	Instructor struct {
+		CourseID  string     `json:"course_id" gorm:"type:char(36);not null;primaryKey"`
+		UserID    string     `json:"user_id" gorm:"type:char(36);not null;primaryKey;index"`
+		CreatedAt *time.Time `json:"created_at"`
+		TenantID  string     `json:"-" gorm:"type:varchar(64);not null;default:'';index"`
+	}
+
+	// ScheduleConflict es otro curso activo del instructor cuyas fechas se superponen
+	ScheduleConflict struct {
+		UserID    string    `json:"user_id"`
+		CourseID  string    `json:"course_id"`
+		Name      string    `json:"name"`
+		StartDate time.Time `json:"start_date"`
+		EndDate   time.Time `json:"end_date"`
+	}
+)
+
+func (Instructor) TableName() string {
+	return "course_instructors"
+}
+
+// AssignInstructor asigna userID al curso id si no tiene otro curso en las mismas
+// fechas y devuelve los instructores del curso
+func (s service) AssignInstructor(ctx context.Context, id, userID string) ([]Instructor, error) {
+	s.log.Println("---- Assigning instructor ----")
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUserID, userID)
+	}
+	if err := s.verifyUsers(ctx, ErrUserNotFound, userID); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.Transaction(ctx, func(txRepo Repository) error {
+		// 🔧 Bloquear el curso evita que sus fechas cambien mientras se valida la agenda
+		if err := txRepo.LockCourses(ctx, id); err != nil {
+			if errors.Is(err, ErrNotFoundBase) {
+				return err
+			}
+			return fmt.Errorf("%w: %w", ErrFailedToUpdateInstructors, err)
+		}
+		course, err := txRepo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.withRepo(txRepo).checkSchedule(ctx, id, []string{userID}, course.StartDate, course.EndDate); err != nil {
+			return err
+		}
+		if err := txRepo.AddInstructors(ctx, id, []string{userID}); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToUpdateInstructors, err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Printf("Error assigning instructor: %v\n", err)
+		return nil, err
+	}
+	return s.Instructors(ctx, id)
+}
+
+func (s service) RemoveInstructor(ctx context.Context, id, userID string) error {
+	s.log.Println("---- Removing instructor ----")
+	if _, err := s.get(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.RemoveInstructor(ctx, id, userID); err != nil {
+		if errors.Is(err, ErrInstructorNotFound) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrFailedToUpdateInstructors, err)
+	}
+	return nil
+}
+
+// Instructors devuelve los instructores del curso por orden de asignación
+func (s service) Instructors(ctx context.Context, id string) ([]Instructor, error) {
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+	instructors, err := s.repo.Instructors(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetInstructors, err)
+	}
+	return instructors, nil
+}
+
+// assignInstructors asigna los instructores de un curso recién creado; userIDs
+// ya pasó por normalizeUserIDs y verifyUsers
+func (s service) assignInstructors(ctx context.Context, id string, userIDs []string, startDate, endDate time.Time) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	if err := s.checkSchedule(ctx, id, userIDs, startDate, endDate); err != nil {
+		return err
+	}
+	if err := s.repo.AddInstructors(ctx, id, userIDs); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToUpdateInstructors, err)
+	}
+	return nil
+}
+
+// checkSchedule rechaza las fechas si alguno de los instructores (los del curso
+// si userIDs es nil) ya da otro curso activo que se superpone, ambas fechas inclusive
+func (s service) checkSchedule(ctx context.Context, id string, userIDs []string, startDate, endDate time.Time) error {
+	if userIDs == nil {
+		instructors, err := s.repo.Instructors(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToGetInstructors, err)
+		}
+		for _, instructor := range instructors {
+			userIDs = append(userIDs, instructor.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	conflicts, err := s.repo.ScheduleConflicts(ctx, id, userIDs, startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToGetInstructors, err)
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	// Las fechas en conflicto se muestran en la zona horaria de cada curso
+	ids := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		ids = append(ids, conflict.CourseID)
+	}
+	timezones, err := s.repo.Timezones(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToGetInstructors, err)
+	}
+	for i := range conflicts {
+		if _, loc, err := loadTimezone(timezones[conflicts[i].CourseID]); err == nil {
+			conflicts[i].StartDate = conflicts[i].StartDate.In(loc)
+			conflicts[i].EndDate = conflicts[i].EndDate.In(loc)
+		}
+	}
+	return &ErrScheduleConflict{Conflicts: conflicts}
+}
+
+// normalizeUserIDs quita espacios y repetidos conservando el orden; cada ID debe ser un UUID
+func normalizeUserIDs(userIDs []string) ([]string, error) {
+	normalized := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		userID = strings.TrimSpace(userID)
+		if _, err := uuid.Parse(userID); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidUserID, userID)
+		}
+		if !slices.Contains(normalized, userID) {
+			normalized = append(normalized, userID)
+		}
+	}
+	return normalized, nil
+}
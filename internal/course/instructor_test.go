@@ -0,0 +1,89 @@
+package course_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/NicoJCastro/gocourse_course/pkg/handler"
+)
+
+func TestScheduleConflict(t *testing.T) {
+	const instructor = "3f2c1d4e-5b6a-4c7d-8e9f-0a1b2c3d4e5f"
+	svc, _, _ := newTenantService(t)
+	ctx, _ := tenanttest.Contexts()
+	busy, err := svc.Create(ctx, "Go basics", "2024-06-01", "2024-06-30", "America/Argentina/Buenos_Aires", course.Classification{}, []string{instructor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	free, err := svc.Create(ctx, "Go advanced", "2024-07-01", "2024-07-31", "", course.Classification{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := handler.WithTenant(
+		config.Tenancy{Header: "X-Tenant-ID", Required: true},
+		handler.NewCourseHTTPServer(context.Background(), course.MakeEndpoint(svc, course.Config{Pagination: config.Default().Pagination})),
+	)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("X-Tenant-ID", tenanttest.A)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		return w
+	}
+
+	// Go advanced empezaba el día después de Go basics: lo movemos encima
+	if w := do(http.MethodPatch, "/courses/"+free.ID, `{"start_date":"2024-06-30"}`); w.Code != http.StatusOK {
+		t.Fatalf("PATCH = %d: %s", w.Code, w.Body)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"create", http.MethodPost, "/courses", `{"name":"Go for teams","start_date":"2024-06-30","end_date":"2024-07-15","instructor_ids":["` + instructor + `"]}`},
+		{"assign", http.MethodPost, "/courses/" + free.ID + "/instructors", `{"user_id":"` + instructor + `"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(tt.method, tt.path, tt.body)
+			if w.Code != http.StatusConflict {
+				t.Fatalf("status = %d, want 409: %s", w.Code, w.Body)
+			}
+			var body struct {
+				Code      string                    `json:"code"`
+				Conflicts []course.ScheduleConflict `json:"conflicts"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Code != "schedule_conflict" || len(body.Conflicts) != 1 {
+				t.Fatalf("body = %+v, want the schedule_conflict code and one conflict", body)
+			}
+			conflict := body.Conflicts[0]
+			if conflict.CourseID != busy.ID || conflict.UserID != instructor || conflict.Name != "Go basics" {
+				t.Errorf("conflict = %+v, want Go basics of the instructor", conflict)
+			}
+			// Las fechas salen en la zona horaria del curso en conflicto
+			if _, offset := conflict.StartDate.Zone(); offset != -3*60*60 {
+				t.Errorf("start_date = %s, want it in America/Argentina/Buenos_Aires", conflict.StartDate)
+			}
+		})
+	}
+
+	// Empezar el día después de que termina Go basics no se superpone
+	w := do(http.MethodPost, "/courses", `{"name":"Go testing","start_date":"2024-07-01","end_date":"2024-07-15","instructor_ids":["`+instructor+`"]}`)
+	if w.Code != http.StatusCreated {
+		t.Errorf("adjacent dates = %d: %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,225 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type (
+	// Prerequisite indica que CourseID requiere haber cursado PrerequisiteID
+	Prerequisite struct {
+		CourseID       string     `json:"course_id" gorm:"type:char(36);not null;primaryKey"`
+		PrerequisiteID string     `json:"prerequisite_id" gorm:"type:char(36);not null;primaryKey;index"`
+		CreatedAt      *time.Time `json:"created_at"`
+		TenantID       string     `json:"-" gorm:"type:varchar(64);not null;default:'';index"`
+	}
+
+	// PrerequisiteLock es la fila que serializa los cambios de prerequisitos de un
+	// tenant: un ciclo puede cerrarse con altas de cursos que no tienen nada en común
+	PrerequisiteLock struct {
+		TenantID string    `gorm:"type:varchar(64);primaryKey"`
+		LockedAt time.Time `gorm:"not null"`
+	}
+
+	// PrerequisiteNode es un curso con su árbol transitivo de prerequisitos
+	PrerequisiteNode struct {
+		ID            string             `json:"id"`
+		Name          string             `json:"name"`
+		Prerequisites []PrerequisiteNode `json:"prerequisites"`
+	}
+
+	// PrerequisitePolicy decide qué pasa al borrar un curso que otros requieren
+	PrerequisitePolicy string
+)
+
+const (
+	// PrerequisiteRestrict rechaza el borrado mientras otros cursos lo requieran
+	PrerequisiteRestrict PrerequisitePolicy = "restrict"
+	// PrerequisiteCascade quita el curso de los prerequisitos de los demás
+	PrerequisiteCascade PrerequisitePolicy = "cascade"
+)
+
+func (Prerequisite) TableName() string {
+	return "course_prerequisites"
+}
+
+func (PrerequisiteLock) TableName() string {
+	return "course_prerequisite_locks"
+}
+
+// ParsePrerequisitePolicy valida la política; vacío es PrerequisiteRestrict
+func ParsePrerequisitePolicy(value string) (PrerequisitePolicy, error) {
+	switch policy := PrerequisitePolicy(value); policy {
+	case "":
+		return PrerequisiteRestrict, nil
+	case PrerequisiteRestrict, PrerequisiteCascade:
+		return policy, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidPrerequisitePolicy, value)
+}
+
+// AddPrerequisite hace que id requiera prerequisiteID y devuelve el árbol actualizado
+func (s service) AddPrerequisite(ctx context.Context, id, prerequisiteID string) (*PrerequisiteNode, error) {
+	s.log.Println("---- Adding prerequisite ----")
+	if id == prerequisiteID {
+		return nil, ErrSelfPrerequisite
+	}
+
+	err := s.repo.Transaction(ctx, func(txRepo Repository) error {
+		// 🔧 Bloquear sólo id y prerequisiteID no alcanza: con B->C y D->A, A->B y C->D
+		// concurrentes forman un ciclo sin tocar los mismos cursos. El lock es del tenant.
+		if err := txRepo.LockPrerequisites(ctx); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToUpdatePrerequisites, err)
+		}
+		if err := txRepo.LockCourses(ctx, id, prerequisiteID); err != nil {
+			if errors.Is(err, ErrNotFoundBase) {
+				return err
+			}
+			return fmt.Errorf("%w: %w", ErrFailedToUpdatePrerequisites, err)
+		}
+
+		// Hay ciclo si id ya es prerequisito (directo o transitivo) de prerequisiteID
+		edges, err := s.withRepo(txRepo).prerequisiteEdges(ctx, prerequisiteID)
+		if err != nil {
+			return err
+		}
+		if _, ok := edges.reachable(prerequisiteID)[id]; ok {
+			return ErrPrerequisiteCycle
+		}
+
+		if err := txRepo.AddPrerequisite(ctx, id, prerequisiteID); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToUpdatePrerequisites, err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Printf("Error adding prerequisite: %v\n", err)
+		return nil, err
+	}
+	return s.Prerequisites(ctx, id)
+}
+
+func (s service) RemovePrerequisite(ctx context.Context, id, prerequisiteID string) error {
+	s.log.Println("---- Removing prerequisite ----")
+	if _, err := s.get(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.RemovePrerequisite(ctx, id, prerequisiteID); err != nil {
+		if errors.Is(err, ErrPrerequisiteNotFound) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrFailedToUpdatePrerequisites, err)
+	}
+	return nil
+}
+
+// Prerequisites devuelve el árbol completo de prerequisitos del curso. Un curso
+// requerido por varias ramas aparece en cada una de ellas.
+func (s service) Prerequisites(ctx context.Context, id string) (*PrerequisiteNode, error) {
+	root, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	edges, err := s.prerequisiteEdges(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(edges))
+	for courseID := range edges.reachable(id) {
+		ids = append(ids, courseID)
+	}
+	courses, err := s.repo.GetByIDs(ctx, ids, Filters{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetPrerequisites, err)
+	}
+
+	names := make(map[string]string, len(courses)+1)
+	names[root.ID] = root.Name
+	for _, course := range courses {
+		names[course.ID] = course.Name
+	}
+	node := edges.tree(id, names, map[string]bool{})
+	return &node, nil
+}
+
+// prerequisiteGraph son las aristas curso -> prerequisitos directos
+type prerequisiteGraph map[string][]string
+
+// prerequisiteEdges carga nivel por nivel las aristas alcanzables desde id:
+// una consulta por nivel de profundidad, no por curso
+func (s service) prerequisiteEdges(ctx context.Context, id string) (prerequisiteGraph, error) {
+	graph := prerequisiteGraph{}
+	pending := []string{id}
+	for len(pending) > 0 {
+		level, err := s.repo.Prerequisites(ctx, pending)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToGetPrerequisites, err)
+		}
+
+		next := []string{}
+		for _, courseID := range pending {
+			graph[courseID] = level[courseID]
+			for _, prerequisiteID := range level[courseID] {
+				if _, loaded := graph[prerequisiteID]; !loaded && !slices.Contains(next, prerequisiteID) {
+					next = append(next, prerequisiteID)
+				}
+			}
+		}
+		pending = next
+	}
+	return graph, nil
+}
+
+// reachable devuelve los prerequisitos transitivos de id, sin incluirlo
+func (g prerequisiteGraph) reachable(id string) map[string]struct{} {
+	seen := map[string]struct{}{}
+	stack := append([]string{}, g[id]...)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := seen[current]; ok {
+			continue
+		}
+		seen[current] = struct{}{}
+		stack = append(stack, g[current]...)
+	}
+	return seen
+}
+
+// tree arma el árbol desde id; path corta la recursión si la tabla tuviera un ciclo.
+// Los cursos sin nombre están en la papelera y no se muestran.
+func (g prerequisiteGraph) tree(id string, names map[string]string, path map[string]bool) PrerequisiteNode {
+	node := PrerequisiteNode{ID: id, Name: names[id], Prerequisites: []PrerequisiteNode{}}
+	path[id] = true
+	for _, prerequisiteID := range g[id] {
+		if _, ok := names[prerequisiteID]; !ok || path[prerequisiteID] {
+			continue
+		}
+		node.Prerequisites = append(node.Prerequisites, g.tree(prerequisiteID, names, path))
+	}
+	delete(path, id)
+	return node
+}
+
+// releasePrerequisite aplica la política de prerequisitos antes de borrar id
+func (s service) releasePrerequisite(ctx context.Context, txRepo Repository, id string) error {
+	dependents, err := txRepo.Dependents(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToGetPrerequisites, err)
+	}
+	if len(dependents) == 0 {
+		return nil
+	}
+	if s.prerequisitePolicy == PrerequisiteCascade {
+		if err := txRepo.RemoveDependents(ctx, id); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToUpdatePrerequisites, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: required by %s", ErrCourseIsPrerequisite, strings.Join(dependents, ", "))
+}
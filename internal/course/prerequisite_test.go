@@ -0,0 +1,72 @@
+package course_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+)
+
+func TestAddPrerequisite(t *testing.T) {
+	svc, _, db := newTenantService(t)
+	ctx, _ := tenanttest.Contexts()
+
+	ids := map[string]string{}
+	for _, name := range []string{"A", "B", "C", "D"} {
+		created, err := svc.Create(ctx, name, "2024-06-01", "2024-06-30", "", course.Classification{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[name] = created.ID
+	}
+	add := func(id, prerequisiteID string) error {
+		_, err := svc.AddPrerequisite(ctx, ids[id], ids[prerequisiteID])
+		return err
+	}
+
+	if err := add("A", "A"); !errors.Is(err, course.ErrSelfPrerequisite) {
+		t.Errorf("A requires A = %v, want %v", err, course.ErrSelfPrerequisite)
+	}
+
+	// B->C y D->A ya existen: A->B está bien, pero después C->D cerraría A->B->C->D->A
+	for _, edge := range [][2]string{{"B", "C"}, {"D", "A"}, {"A", "B"}} {
+		if err := add(edge[0], edge[1]); err != nil {
+			t.Fatalf("%s requires %s: %v", edge[0], edge[1], err)
+		}
+	}
+	tests := []struct {
+		name               string
+		id, prerequisiteID string
+		err                error
+	}{
+		{"direct cycle", "B", "A", course.ErrPrerequisiteCycle},
+		{"transitive cycle", "C", "D", course.ErrPrerequisiteCycle},
+		{"existing prerequisite", "A", "B", nil},
+		{"no cycle", "D", "C", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := add(tt.id, tt.prerequisiteID); !errors.Is(err, tt.err) {
+				t.Errorf("%s requires %s = %v, want %v", tt.id, tt.prerequisiteID, err, tt.err)
+			}
+		})
+	}
+
+	// Cada alta tomó el lock del tenant
+	var locks []course.PrerequisiteLock
+	if err := db.Find(&locks).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(locks) != 1 || locks[0].TenantID != tenanttest.A {
+		t.Errorf("prerequisite locks = %+v, want one for %s", locks, tenanttest.A)
+	}
+
+	node, err := svc.Prerequisites(ctx, ids["D"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(node.Prerequisites) != 2 {
+		t.Errorf("D has %d direct prerequisites, want 2 (A and C)", len(node.Prerequisites))
+	}
+}
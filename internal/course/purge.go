@@ -0,0 +1,54 @@
+package course
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+)
+
+// PurgeJob elimina definitivamente, cada Interval, los cursos que llevan en la
+// papelera más tiempo que Retention
+type PurgeJob struct {
+	log       *log.Logger
+	service   Service
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewPurgeJob(logger *log.Logger, service Service, retention, interval time.Duration) *PurgeJob {
+	return &PurgeJob{
+		log:       logger,
+		service:   service,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Run bloquea hasta que se cancele ctx; conviene lanzarlo en una goroutine
+func (j *PurgeJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.purge(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *PurgeJob) purge(ctx context.Context) {
+	before := time.Now().Add(-j.retention)
+	purged, err := j.service.PurgeDeletedBefore(audit.WithActor(ctx, audit.SystemActor), before)
+	if err != nil {
+		j.log.Println("error purging trash: ", err)
+		return
+	}
+	if purged > 0 {
+		j.log.Printf("purged %d courses deleted before %s\n", purged, before.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,124 @@
+package course_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+)
+
+func TestRestore(t *testing.T) {
+	svc, _, _ := newTenantService(t)
+	ctx, _ := tenanttest.Contexts()
+	created, err := svc.Create(ctx, "Go basics", "2024-06-01", "2024-06-30", "", course.Classification{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.Restore(ctx, created.ID); !errors.Is(err, course.ErrNotFoundBase) {
+		t.Errorf("Restore of an active course = %v, want %v", err, course.ErrNotFoundBase)
+	}
+	if err := svc.Delete(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Get(ctx, created.ID); !errors.Is(err, course.ErrNotFoundBase) {
+		t.Errorf("Get of a deleted course = %v, want %v", err, course.ErrNotFoundBase)
+	}
+	trash, err := svc.GetAll(ctx, course.Filters{Deleted: course.DeletedOnly}, 0, 10)
+	if err != nil || len(trash) != 1 || trash[0].ID != created.ID {
+		t.Errorf("trash = %+v, %v; want the deleted course", trash, err)
+	}
+
+	restored, err := svc.Restore(ctx, created.ID)
+	if err != nil || restored.ID != created.ID {
+		t.Fatalf("Restore = %+v, %v", restored, err)
+	}
+	if _, err := svc.Get(ctx, created.ID); err != nil {
+		t.Errorf("Get after Restore = %v", err)
+	}
+	if trash, err := svc.GetAll(ctx, course.Filters{Deleted: course.DeletedOnly}, 0, 10); err != nil || len(trash) != 0 {
+		t.Errorf("trash after Restore = %+v, %v; want empty", trash, err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	svc, _, db := newTenantService(t)
+	ctx, _ := tenanttest.Contexts()
+	created, err := svc.Create(ctx, "Go basics", "2024-06-01", "2024-06-30", "America/Argentina/Buenos_Aires", course.Classification{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetTranslation(ctx, created.ID, "es", "Go básico", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Purge no necesita que el curso esté en la papelera
+	if err := svc.Purge(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Restore(ctx, created.ID); !errors.Is(err, course.ErrNotFoundBase) {
+		t.Errorf("Restore after Purge = %v, want %v", err, course.ErrNotFoundBase)
+	}
+	if err := svc.Purge(ctx, created.ID); !errors.Is(err, course.ErrNotFoundBase) {
+		t.Errorf("second Purge = %v, want %v", err, course.ErrNotFoundBase)
+	}
+	for _, table := range []string{"courses", "course_translations", "course_timezones"} {
+		var count int64
+		if err := db.Table(table).Count(&count).Error; err != nil || count != 0 {
+			t.Errorf("%s = %d rows, %v; want none after Purge", table, count, err)
+		}
+	}
+
+	history, err := svc.History(ctx, created.ID, 0, 10)
+	if err != nil || len(history) == 0 || history[0].Action != audit.ActionPurge {
+		t.Errorf("history = %+v, %v; want the purge first", history, err)
+	}
+}
+
+func TestPurgeDeletedBefore(t *testing.T) {
+	svc, _, db := newTenantService(t)
+	ctxA, ctxB := tenanttest.Contexts()
+
+	ids := map[string]string{}
+	for name, ctx := range map[string]context.Context{"old": ctxA, "old B": ctxB, "recent": ctxA, "active": ctxA} {
+		created, err := svc.Create(ctx, name, "2024-06-01", "2024-06-30", "", course.Classification{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[name] = created.ID
+		if name != "active" {
+			if err := svc.Delete(ctx, created.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	old := time.Now().UTC().Add(-48 * time.Hour)
+	err := db.Table("courses").Where("id IN ?", []string{ids["old"], ids["old B"]}).Update("deleted_at", old).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// El job corre sin tenant y purga los de todas las organizaciones
+	purged, err := svc.PurgeDeletedBefore(audit.WithActor(context.Background(), audit.SystemActor), time.Now().Add(-24*time.Hour))
+	if err != nil || purged != 2 {
+		t.Fatalf("PurgeDeletedBefore = %d, %v; want 2", purged, err)
+	}
+	if trash, err := svc.GetAll(ctxA, course.Filters{Deleted: course.DeletedOnly}, 0, 10); err != nil || len(trash) != 1 || trash[0].ID != ids["recent"] {
+		t.Errorf("trash = %+v, %v; want only the recent one", trash, err)
+	}
+	if _, err := svc.Get(ctxA, ids["active"]); err != nil {
+		t.Errorf("active course = %v", err)
+	}
+
+	// Cada purga queda en el historial del tenant del curso
+	for name, ctx := range map[string]context.Context{"old": ctxA, "old B": ctxB} {
+		history, err := svc.History(ctx, ids[name], 0, 10)
+		if err != nil || len(history) == 0 || history[0].Action != audit.ActionPurge || history[0].Actor != audit.SystemActor {
+			t.Errorf("%s history = %+v, %v; want a purge by %s", name, history, err, audit.SystemActor)
+		}
+	}
+}
@@ -1,131 +1,815 @@
-package course
-
-import (
-	"context"
-	"errors"
-	"fmt"
-	"log"
-	"strings"
-	"time"
-
-	"github.com/NicoJCastro/gocourse_domain/domain"
-
-	"gorm.io/gorm"
-)
-
-type (
-	Repository interface {
-		Create(ctx context.Context, course *domain.Course) error
-		GetAll(ctx context.Context, filter Filters, offset, limit int) ([]domain.Course, error)
-		Get(ctx context.Context, id string) (*domain.Course, error)
-		Delete(ctx context.Context, id string) error
-		Update(ctx context.Context, id string, name *string, startDate *time.Time, endDate *time.Time) error
-		Count(ctx context.Context, filters Filters) (int64, error)
-	}
-
-	repo struct {
-		db  *gorm.DB
-		log *log.Logger
-	}
-)
-
-func NewRepo(db *gorm.DB, logger *log.Logger) Repository {
-	return &repo{
-		db:  db,
-		log: logger,
-	}
-}
-
-func (r *repo) Create(ctx context.Context, course *domain.Course) error {
-	if err := r.db.WithContext(ctx).Create(course).Error; err != nil {
-		r.log.Printf("error: %v", err)
-		return err
-	}
-
-	r.log.Println("course created with id: ", course.ID)
-	return nil
-}
-
-func (r *repo) GetAll(ctx context.Context, filters Filters, offset, limit int) ([]domain.Course, error) {
-	var courses []domain.Course
-	tx := r.db.WithContext(ctx).Model(&courses)
-	tx = applyFilters(tx, filters)
-	tx = tx.Limit(limit).Offset(offset)
-	result := tx.Order("created_at desc").Find(&courses)
-	if result.Error != nil {
-		r.log.Println("Error getting courses: ", result.Error)
-		return nil, result.Error
-	}
-
-	return courses, nil
-}
-
-func (r *repo) Get(ctx context.Context, id string) (*domain.Course, error) {
-	course := domain.Course{ID: id}
-	result := r.db.WithContext(ctx).First(&course)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, NewErrNotFound(id)
-		}
-		r.log.Println("Error getting course: ", result.Error)
-		return nil, result.Error
-	}
-	return &course, nil
-}
-
-func (r *repo) Delete(ctx context.Context, id string) error {
-	course := domain.Course{ID: id}
-	result := r.db.WithContext(ctx).Delete(&course)
-	if result.Error != nil {
-		r.log.Println("Error deleting course: ", result.Error)
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return NewErrNotFound(id)
-	}
-	return nil
-}
-
-func (r *repo) Update(ctx context.Context, id string, name *string, startDate *time.Time, endDate *time.Time) error {
-
-	updates := make(map[string]interface{})
-	if name != nil && *name != "" {
-		updates["name"] = *name
-	}
-	if startDate != nil {
-		updates["start_date"] = *startDate
-	}
-	if endDate != nil {
-		updates["end_date"] = *endDate
-	}
-	result := r.db.WithContext(ctx).Model(&domain.Course{}).Where("id = ?", id).Updates(updates)
-	if result.Error != nil {
-		r.log.Println("Error updating course: ", result.Error)
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return NewErrNotFound(id)
-	}
-	return nil
-}
-
-func applyFilters(tx *gorm.DB, filters Filters) *gorm.DB {
-
-	if filters.Name != "" {
-		filters.Name = fmt.Sprintf("%%%s%%", strings.ToLower(filters.Name))
-		tx = tx.Where("LOWER(name) LIKE ?", filters.Name)
-	}
-	return tx
-}
-
-func (r *repo) Count(ctx context.Context, filters Filters) (int64, error) {
-	var count int64
-	tx := r.db.WithContext(ctx).Model(&domain.Course{})
-	tx = applyFilters(tx, filters)
-	result := tx.Count(&count)
-	if result.Error != nil {
-		r.log.Println("Error counting courses: ", result.Error)
-		return 0, result.Error
-	}
-	return count, nil
-}
+package course
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/catalog"
+	"github.com/NicoJCastro/gocourse_course/internal/locale"
+	"github.com/NicoJCastro/gocourse_course/internal/outbox"
+	"github.com/NicoJCastro/gocourse_course/internal/session"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/NicoJCastro/gocourse_course/pkg/replica"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	Repository interface {
+		Create(ctx context.Context, course *domain.Course) error
+		CreateInBatches(ctx context.Context, courses []*domain.Course, batchSize int) error
+		// GetAll carga también la zona horaria, la categoría y los tags con una consulta por relación
+		GetAll(ctx context.Context, filter Filters, offset, limit int) ([]Course, error)
+		// Describe expresa las fechas en la zona horaria de cada curso
+		Describe(ctx context.Context, courses ...domain.Course) ([]Course, error)
+		// Timezones devuelve la zona horaria guardada de cada curso; sin entrada es UTC
+		Timezones(ctx context.Context, courseIDs []string) (map[string]string, error)
+		SetTimezone(ctx context.Context, courseID, timezone string) error
+		// SetTags reemplaza los tags del curso, creando los que no existan
+		SetTags(ctx context.Context, courseID string, names []string) error
+		// SetCategory asigna la categoría; categoryID vacío la quita
+		SetCategory(ctx context.Context, courseID, categoryID string) error
+		Get(ctx context.Context, id string) (*domain.Course, error)
+		// GetByIDs carga los cursos indicados que cumplen los filtros, sin un orden garantizado
+		GetByIDs(ctx context.Context, ids []string, filters Filters) ([]domain.Course, error)
+		Delete(ctx context.Context, id string) error
+		Update(ctx context.Context, id string, name *string, startDate *time.Time, endDate *time.Time) error
+		Replace(ctx context.Context, course *domain.Course) error
+		Count(ctx context.Context, filters Filters) (int64, error)
+		// Stream recorre los cursos filtrados fila por fila con GORM Rows, sin cargarlos todos en memoria.
+		// Las fechas llegan en la zona horaria de cada curso.
+		Stream(ctx context.Context, filters Filters, fn func(course TenantCourse) error) error
+		Restore(ctx context.Context, id string) error
+		Purge(ctx context.Context, id string) error
+		// PurgeDeletedBefore devuelve el tenant de cada curso eliminado definitivamente, por ID
+		PurgeDeletedBefore(ctx context.Context, before time.Time) (map[string]string, error)
+		RecordAudit(ctx context.Context, entries ...*audit.Entry) error
+		RecordEvents(ctx context.Context, messages ...*outbox.Message) error
+		History(ctx context.Context, id string, offset, limit int) ([]audit.Entry, error)
+		CountHistory(ctx context.Context, id string) (int64, error)
+		HistoryEntry(ctx context.Context, entryID string) (*audit.Entry, error)
+		// HistoryAfter, LastHistorySeq y HistorySeqAt recorren la auditoría de cursos por Seq,
+		// que sigue el orden de commit (ver audit.Repository.Create)
+		HistoryAfter(ctx context.Context, seq int64, limit int) ([]audit.Entry, error)
+		LastHistorySeq(ctx context.Context) (int64, error)
+		HistorySeqAt(ctx context.Context, at time.Time) (int64, error)
+		// Sessions devuelve las sesiones del curso para revalidarlas al cambiar sus fechas
+		Sessions(ctx context.Context, courseID string) ([]session.Session, error)
+		// LockCourses bloquea las filas de los cursos (SELECT ... FOR UPDATE) hasta el fin de la transacción
+		LockCourses(ctx context.Context, ids ...string) error
+		// LockPrerequisites bloquea los cambios de prerequisitos del tenant hasta el fin de la transacción
+		LockPrerequisites(ctx context.Context) error
+		AddPrerequisite(ctx context.Context, courseID, prerequisiteID string) error
+		RemovePrerequisite(ctx context.Context, courseID, prerequisiteID string) error
+		// Prerequisites devuelve los prerequisitos directos de cada curso en una sola consulta
+		Prerequisites(ctx context.Context, courseIDs []string) (map[string][]string, error)
+		// Dependents devuelve los cursos activos que requieren courseID
+		Dependents(ctx context.Context, courseID string) ([]string, error)
+		// RemoveDependents quita courseID de los prerequisitos de los demás cursos
+		RemoveDependents(ctx context.Context, courseID string) error
+		// AddInstructors ignora los usuarios que ya son instructores del curso
+		AddInstructors(ctx context.Context, courseID string, userIDs []string) error
+		RemoveInstructor(ctx context.Context, courseID, userID string) error
+		Instructors(ctx context.Context, courseID string) ([]Instructor, error)
+		// SetTranslation crea o reemplaza la traducción del curso en su idioma
+		SetTranslation(ctx context.Context, translation *Translation) error
+		RemoveTranslation(ctx context.Context, courseID, locale string) error
+		// Translations devuelve las traducciones de cada curso en una sola consulta
+		Translations(ctx context.Context, courseIDs []string) (map[string][]Translation, error)
+		// ScheduleConflicts devuelve los otros cursos activos de userIDs que se superponen
+		// con [startDate, endDate]. Dentro de una transacción bloquea las asignaciones
+		// de esos usuarios hasta que termine.
+		ScheduleConflicts(ctx context.Context, courseID string, userIDs []string, startDate, endDate time.Time) ([]ScheduleConflict, error)
+		// Transaction ejecuta fn con un Repository ligado a una transacción de GORM.
+		// Las transacciones anidadas se resuelven con SAVEPOINTs.
+		Transaction(ctx context.Context, fn func(txRepo Repository) error) error
+	}
+
+	repo struct {
+		db  *gorm.DB
+		log *log.Logger
+	}
+)
+
+func NewRepo(db *gorm.DB, logger *log.Logger) Repository {
+	return &repo{
+		db:  db,
+		log: logger,
+	}
+}
+
+func (r *repo) Create(ctx context.Context, course *domain.Course) error {
+	row := newTenantCourse(ctx, *course)
+	if err := r.db.WithContext(ctx).Create(row).Error; err != nil {
+		r.log.Printf("error: %v", err)
+		return err
+	}
+	*course = row.Course
+
+	r.log.Println("course created with id: ", course.ID)
+	return nil
+}
+
+func (r *repo) CreateInBatches(ctx context.Context, courses []*domain.Course, batchSize int) error {
+	rows := make([]*TenantCourse, len(courses))
+	for i, course := range courses {
+		rows[i] = newTenantCourse(ctx, *course)
+	}
+	if err := r.db.WithContext(ctx).CreateInBatches(rows, batchSize).Error; err != nil {
+		r.log.Printf("error: %v", err)
+		return err
+	}
+	for i, row := range rows {
+		*courses[i] = row.Course
+	}
+
+	r.log.Println("courses created: ", len(courses))
+	return nil
+}
+
+func (r *repo) GetAll(ctx context.Context, filters Filters, offset, limit int) ([]Course, error) {
+	var courses []domain.Course
+	tx := replica.Read(r.db.WithContext(ctx)).Model(&courses)
+	tx = applyFilters(tx, filters)
+	tx = tx.Limit(limit).Offset(offset)
+	result := tx.Order("created_at desc").Find(&courses)
+	if result.Error != nil {
+		r.log.Println("Error getting courses: ", result.Error)
+		return nil, result.Error
+	}
+
+	return r.Describe(ctx, courses...)
+}
+
+func (r *repo) Describe(ctx context.Context, courses ...domain.Course) ([]Course, error) {
+	ids := make([]string, 0, len(courses))
+	for _, course := range courses {
+		ids = append(ids, course.ID)
+	}
+
+	timezones, err := r.Timezones(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	catalogRepo := catalog.NewRepo(r.db, r.log)
+	tags, err := catalogRepo.TagsByCourse(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := catalogRepo.CategoriesByCourse(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	// Las traducciones sólo se cargan si la request indicó sus idiomas
+	preference, translated := locale.FromContext(ctx)
+	var translations map[string][]Translation
+	if translated {
+		if translations, err = r.Translations(ctx, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	described := make([]Course, 0, len(courses))
+	for _, course := range courses {
+		courseTags := tags[course.ID]
+		if courseTags == nil {
+			courseTags = []catalog.Tag{}
+		}
+		timezone := timezones[course.ID]
+		if timezone == "" {
+			timezone = DefaultTimezone
+		}
+		localize(&course, timezone)
+		describedCourse := Course{
+			Course:   course,
+			Timezone: timezone,
+			Category: categories[course.ID],
+			Tags:     courseTags,
+		}
+		if translated {
+			translate(&describedCourse, translations[course.ID], preference)
+		}
+		described = append(described, describedCourse)
+	}
+	return described, nil
+}
+
+func (r *repo) Timezones(ctx context.Context, courseIDs []string) (map[string]string, error) {
+	timezones := make(map[string]string, len(courseIDs))
+	if len(courseIDs) == 0 {
+		return timezones, nil
+	}
+
+	var rows []CourseTimezone
+	if err := r.db.WithContext(ctx).Where("course_id IN ?", courseIDs).Find(&rows).Error; err != nil {
+		r.log.Println("Error getting course timezones: ", err)
+		return nil, err
+	}
+	for _, row := range rows {
+		timezones[row.CourseID] = row.Timezone
+	}
+	return timezones, nil
+}
+
+func (r *repo) SetTimezone(ctx context.Context, courseID, timezone string) error {
+	row := CourseTimezone{CourseID: courseID, Timezone: timezone}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"timezone"}),
+	}).Create(&row).Error
+	if err != nil {
+		r.log.Println("Error saving course timezone: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) SetTags(ctx context.Context, courseID string, names []string) error {
+	catalogRepo := catalog.NewRepo(r.db, r.log)
+	tags, err := catalogRepo.EnsureTags(ctx, names)
+	if err != nil {
+		return err
+	}
+	tagIDs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	return catalogRepo.SetCourseTags(ctx, courseID, tagIDs)
+}
+
+func (r *repo) SetCategory(ctx context.Context, courseID, categoryID string) error {
+	catalogRepo := catalog.NewRepo(r.db, r.log)
+	if categoryID == "" {
+		return catalogRepo.SetCourseCategory(ctx, courseID, nil)
+	}
+	if _, err := catalogRepo.GetCategory(ctx, categoryID); err != nil {
+		return err
+	}
+	return catalogRepo.SetCourseCategory(ctx, courseID, &categoryID)
+}
+
+func (r *repo) GetByIDs(ctx context.Context, ids []string, filters Filters) ([]domain.Course, error) {
+	var courses []domain.Course
+	tx := r.db.WithContext(ctx).Model(&courses)
+	tx = applyFilters(tx, filters)
+	result := tx.Where("id IN ?", ids).Find(&courses)
+	if result.Error != nil {
+		r.log.Println("Error getting courses by ID: ", result.Error)
+		return nil, result.Error
+	}
+	return courses, nil
+}
+
+func (r *repo) Get(ctx context.Context, id string) (*domain.Course, error) {
+	course := domain.Course{ID: id}
+	result := replica.Read(r.db.WithContext(ctx)).First(&course)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, NewErrNotFound(id)
+		}
+		r.log.Println("Error getting course: ", result.Error)
+		return nil, result.Error
+	}
+	return &course, nil
+}
+
+func (r *repo) Delete(ctx context.Context, id string) error {
+	course := domain.Course{ID: id}
+	result := r.db.WithContext(ctx).Delete(&course)
+	if result.Error != nil {
+		r.log.Println("Error deleting course: ", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewErrNotFound(id)
+	}
+	return nil
+}
+
+func (r *repo) Update(ctx context.Context, id string, name *string, startDate *time.Time, endDate *time.Time) error {
+
+	updates := make(map[string]interface{})
+	if name != nil && *name != "" {
+		updates["name"] = *name
+	}
+	if startDate != nil {
+		updates["start_date"] = *startDate
+	}
+	if endDate != nil {
+		updates["end_date"] = *endDate
+	}
+	result := r.db.WithContext(ctx).Model(&domain.Course{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		r.log.Println("Error updating course: ", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewErrNotFound(id)
+	}
+	return nil
+}
+
+// Replace sobrescribe todos los campos editables del curso, incluso si vienen vacíos
+func (r *repo) Replace(ctx context.Context, course *domain.Course) error {
+	result := r.db.WithContext(ctx).Model(&domain.Course{}).Where("id = ?", course.ID).
+		Updates(map[string]interface{}{
+			"name":       course.Name,
+			"start_date": course.StartDate,
+			"end_date":   course.EndDate,
+		})
+	if result.Error != nil {
+		r.log.Println("Error replacing course: ", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewErrNotFound(course.ID)
+	}
+	return nil
+}
+
+func (r *repo) Restore(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Unscoped().Model(&domain.Course{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		r.log.Println("Error restoring course: ", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewErrNotFound(id)
+	}
+	return nil
+}
+
+func (r *repo) Purge(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Unscoped().Delete(&domain.Course{ID: id})
+	if result.Error != nil {
+		r.log.Println("Error purging course: ", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewErrNotFound(id)
+	}
+	if err := r.removePrerequisites(ctx, []string{id}); err != nil {
+		return err
+	}
+	if err := r.removeInstructors(ctx, []string{id}); err != nil {
+		return err
+	}
+	if err := r.removeTranslations(ctx, []string{id}); err != nil {
+		return err
+	}
+	if err := session.NewRepo(r.db, r.log).RemoveCourses(ctx, []string{id}); err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Where("course_id = ?", id).Delete(&CourseTimezone{}).Error; err != nil {
+		r.log.Println("Error deleting course timezone: ", err)
+		return err
+	}
+	return catalog.NewRepo(r.db, r.log).RemoveCourses(ctx, []string{id})
+}
+
+func (r *repo) PurgeDeletedBefore(ctx context.Context, before time.Time) (map[string]string, error) {
+	var rows []TenantCourse
+	tx := r.db.WithContext(ctx).Unscoped().Model(&domain.Course{}).
+		Select("id, tenant_id").
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+	if err := tx.Find(&rows).Error; err != nil {
+		r.log.Println("Error getting deleted courses: ", err)
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	purged := make(map[string]string, len(rows))
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		purged[row.ID] = row.TenantID
+		ids = append(ids, row.ID)
+	}
+
+	result := r.db.WithContext(ctx).Unscoped().Where("id IN ?", ids).Delete(&domain.Course{})
+	if result.Error != nil {
+		r.log.Println("Error purging deleted courses: ", result.Error)
+		return nil, result.Error
+	}
+	if err := r.removePrerequisites(ctx, ids); err != nil {
+		return nil, err
+	}
+	if err := r.removeInstructors(ctx, ids); err != nil {
+		return nil, err
+	}
+	if err := r.removeTranslations(ctx, ids); err != nil {
+		return nil, err
+	}
+	if err := session.NewRepo(r.db, r.log).RemoveCourses(ctx, ids); err != nil {
+		return nil, err
+	}
+	if err := r.db.WithContext(ctx).Where("course_id IN ?", ids).Delete(&CourseTimezone{}).Error; err != nil {
+		r.log.Println("Error deleting course timezones: ", err)
+		return nil, err
+	}
+	if err := catalog.NewRepo(r.db, r.log).RemoveCourses(ctx, ids); err != nil {
+		return nil, err
+	}
+	return purged, nil
+}
+
+// RecordAudit escribe en la misma conexión (o transacción) que el repositorio
+func (r *repo) RecordAudit(ctx context.Context, entries ...*audit.Entry) error {
+	return audit.NewRepo(r.db, r.log).Create(ctx, entries...)
+}
+
+// RecordEvents escribe en el outbox usando la misma conexión (o transacción)
+func (r *repo) RecordEvents(ctx context.Context, messages ...*outbox.Message) error {
+	return outbox.NewRepo(r.db, r.log).Create(ctx, messages...)
+}
+
+func (r *repo) History(ctx context.Context, id string, offset, limit int) ([]audit.Entry, error) {
+	return audit.NewRepo(r.db, r.log).GetByEntity(ctx, AuditEntityCourse, id, offset, limit)
+}
+
+func (r *repo) CountHistory(ctx context.Context, id string) (int64, error) {
+	return audit.NewRepo(r.db, r.log).CountByEntity(ctx, AuditEntityCourse, id)
+}
+
+func (r *repo) HistoryEntry(ctx context.Context, entryID string) (*audit.Entry, error) {
+	entry, err := audit.NewRepo(r.db, r.log).GetByID(ctx, entryID)
+	if err != nil || entry == nil || entry.EntityType != AuditEntityCourse {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// HistoryAfter devuelve los registros de auditoría de todos los cursos posteriores a seq
+func (r *repo) HistoryAfter(ctx context.Context, seq int64, limit int) ([]audit.Entry, error) {
+	return audit.NewRepo(r.db, r.log).GetAfter(ctx, AuditEntityCourse, seq, limit)
+}
+
+func (r *repo) LastHistorySeq(ctx context.Context) (int64, error) {
+	return audit.NewRepo(r.db, r.log).LastSeq(ctx, AuditEntityCourse)
+}
+
+func (r *repo) HistorySeqAt(ctx context.Context, at time.Time) (int64, error) {
+	return audit.NewRepo(r.db, r.log).SeqAt(ctx, AuditEntityCourse, at)
+}
+
+// applyFilters filtra la consulta de cursos. Las subconsultas no pasan por el callback
+// de tenant (sólo la consulta principal), así que cada una se filtra con el del contexto.
+func applyFilters(tx *gorm.DB, filters Filters) *gorm.DB {
+	ctx := tx.Statement.Context
+
+	switch filters.Deleted {
+	case DeletedInclude:
+		tx = tx.Unscoped()
+	case DeletedOnly:
+		tx = tx.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+
+	// El nombre se busca en el canónico y en todas las traducciones
+	if filters.Name != "" {
+		filters.Name = fmt.Sprintf("%%%s%%", strings.ToLower(filters.Name))
+		translated := tx.Session(&gorm.Session{NewDB: true}).
+			Model(&Translation{}).
+			Scopes(tenant.Scope(ctx, "course_translations")).
+			Select("course_translations.course_id").
+			Where("LOWER(course_translations.name) LIKE ?", filters.Name)
+		tx = tx.Where("LOWER(courses.name) LIKE ? OR courses.id IN (?)", filters.Name, translated)
+	}
+
+	// 🔧 Tags y categoría se filtran con subconsultas para no duplicar filas con JOINs
+	if len(filters.Tags) > 0 {
+		tags := catalog.NormalizeTags(filters.Tags)
+		tagged := tx.Session(&gorm.Session{NewDB: true}).
+			Table("course_tags").
+			Select("course_tags.course_id").
+			Joins("JOIN tags ON tags.id = course_tags.tag_id").
+			Scopes(tenant.Scope(ctx, "course_tags"), tenant.Scope(ctx, "tags")).
+			Where("tags.name IN ?", tags)
+		if filters.TagMatch == TagMatchAll {
+			tagged = tagged.Group("course_tags.course_id").Having("COUNT(DISTINCT tags.id) = ?", len(tags))
+		}
+		tx = tx.Where("courses.id IN (?)", tagged)
+	}
+
+	// La categoría incluye todas sus subcategorías. El CTE es SQL crudo: con tenant en
+	// el contexto, sus dos ramas y los vínculos se filtran por él.
+	if filters.Category != "" {
+		categories, links := "TRUE", "TRUE"
+		args := map[string]interface{}{"category": filters.Category}
+		if tenantID, ok := tenant.FromContext(ctx); ok {
+			categories, links = "categories.tenant_id = @tenant", "course_categories.tenant_id = @tenant"
+			args["tenant"] = tenantID
+		}
+		tx = tx.Where(fmt.Sprintf(`courses.id IN (
+			SELECT course_categories.course_id FROM course_categories
+			WHERE %[2]s AND course_categories.category_id IN (
+				WITH RECURSIVE tree AS (
+					SELECT id FROM categories WHERE id = @category AND %[1]s
+					UNION ALL
+					SELECT categories.id FROM categories JOIN tree ON categories.parent_id = tree.id WHERE %[1]s
+				)
+				SELECT id FROM tree
+			)
+		)`, categories, links), args)
+	}
+	return tx
+}
+
+func (r *repo) Count(ctx context.Context, filters Filters) (int64, error) {
+	var count int64
+	tx := replica.Read(r.db.WithContext(ctx)).Model(&domain.Course{})
+	tx = applyFilters(tx, filters)
+	result := tx.Count(&count)
+	if result.Error != nil {
+		r.log.Println("Error counting courses: ", result.Error)
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (r *repo) Stream(ctx context.Context, filters Filters, fn func(course TenantCourse) error) error {
+	// La zona horaria viene en la misma fila para no consultar por cada curso
+	tx := r.db.WithContext(ctx).Model(&domain.Course{}).
+		Select("courses.*, course_timezones.timezone").
+		Joins("LEFT JOIN course_timezones ON course_timezones.course_id = courses.id")
+	tx = applyFilters(tx, filters)
+	rows, err := tx.Order("courses.created_at desc").Rows()
+	if err != nil {
+		r.log.Println("Error streaming courses: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row struct {
+			TenantCourse
+			Timezone *string
+		}
+		if err := tx.ScanRows(rows, &row); err != nil {
+			r.log.Println("Error scanning course: ", err)
+			return err
+		}
+		timezone := DefaultTimezone
+		if row.Timezone != nil {
+			timezone = *row.Timezone
+		}
+		localize(&row.Course, timezone)
+		if err := fn(row.TenantCourse); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *repo) Sessions(ctx context.Context, courseID string) ([]session.Session, error) {
+	return session.NewRepo(r.db, r.log).GetByCourse(ctx, courseID)
+}
+
+func (r *repo) LockCourses(ctx context.Context, ids ...string) error {
+	// Orden fijo de bloqueo para no generar deadlocks entre transacciones
+	sorted := append([]string{}, ids...)
+	sort.Strings(sorted)
+
+	var locked []string
+	result := r.db.WithContext(ctx).Model(&domain.Course{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", sorted).Order("id").Pluck("id", &locked)
+	if result.Error != nil {
+		r.log.Println("Error locking courses: ", result.Error)
+		return result.Error
+	}
+	for _, id := range sorted {
+		if !slices.Contains(locked, id) {
+			return NewErrNotFound(id)
+		}
+	}
+	return nil
+}
+
+func (r *repo) LockPrerequisites(ctx context.Context) error {
+	tenantID, _ := tenant.FromContext(ctx)
+	now := time.Now().UTC()
+	// 🔧 El upsert crea la fila del tenant o toma un lock exclusivo sobre ella. Con
+	// INSERT IGNORE + FOR UPDATE dos transacciones podrían quedar en deadlock.
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"locked_at": now}),
+	}).Create(&PrerequisiteLock{TenantID: tenantID, LockedAt: now}).Error
+	if err != nil {
+		r.log.Println("Error locking prerequisites: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) AddPrerequisite(ctx context.Context, courseID, prerequisiteID string) error {
+	prerequisite := Prerequisite{CourseID: courseID, PrerequisiteID: prerequisiteID}
+	// Agregar un prerequisito existente no es un error
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&prerequisite).Error; err != nil {
+		r.log.Println("Error adding prerequisite: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) RemovePrerequisite(ctx context.Context, courseID, prerequisiteID string) error {
+	result := r.db.WithContext(ctx).
+		Where("course_id = ? AND prerequisite_id = ?", courseID, prerequisiteID).
+		Delete(&Prerequisite{})
+	if result.Error != nil {
+		r.log.Println("Error removing prerequisite: ", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPrerequisiteNotFound
+	}
+	return nil
+}
+
+func (r *repo) Prerequisites(ctx context.Context, courseIDs []string) (map[string][]string, error) {
+	var rows []Prerequisite
+	result := r.db.WithContext(ctx).Where("course_id IN ?", courseIDs).
+		Order("course_id, created_at, prerequisite_id").Find(&rows)
+	if result.Error != nil {
+		r.log.Println("Error getting prerequisites: ", result.Error)
+		return nil, result.Error
+	}
+
+	prerequisites := make(map[string][]string, len(courseIDs))
+	for _, row := range rows {
+		prerequisites[row.CourseID] = append(prerequisites[row.CourseID], row.PrerequisiteID)
+	}
+	return prerequisites, nil
+}
+
+func (r *repo) Dependents(ctx context.Context, courseID string) ([]string, error) {
+	var ids []string
+	result := r.db.WithContext(ctx).Model(&Prerequisite{}).
+		Joins("JOIN courses ON courses.id = course_prerequisites.course_id AND courses.deleted_at IS NULL").
+		Scopes(tenant.Scope(ctx, "courses")).
+		Where("course_prerequisites.prerequisite_id = ?", courseID).
+		Order("course_prerequisites.course_id").
+		Pluck("course_prerequisites.course_id", &ids)
+	if result.Error != nil {
+		r.log.Println("Error getting dependent courses: ", result.Error)
+		return nil, result.Error
+	}
+	return ids, nil
+}
+
+func (r *repo) RemoveDependents(ctx context.Context, courseID string) error {
+	if err := r.db.WithContext(ctx).Where("prerequisite_id = ?", courseID).Delete(&Prerequisite{}).Error; err != nil {
+		r.log.Println("Error removing dependent prerequisites: ", err)
+		return err
+	}
+	return nil
+}
+
+// removePrerequisites borra las relaciones de cursos eliminados definitivamente
+func (r *repo) removePrerequisites(ctx context.Context, ids []string) error {
+	result := r.db.WithContext(ctx).
+		Where("course_id IN ? OR prerequisite_id IN ?", ids, ids).
+		Delete(&Prerequisite{})
+	if result.Error != nil {
+		r.log.Println("Error removing prerequisites: ", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (r *repo) AddInstructors(ctx context.Context, courseID string, userIDs []string) error {
+	instructors := make([]Instructor, 0, len(userIDs))
+	for _, userID := range userIDs {
+		instructors = append(instructors, Instructor{CourseID: courseID, UserID: userID})
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&instructors).Error; err != nil {
+		r.log.Println("Error adding instructors: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) RemoveInstructor(ctx context.Context, courseID, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("course_id = ? AND user_id = ?", courseID, userID).
+		Delete(&Instructor{})
+	if result.Error != nil {
+		r.log.Println("Error removing instructor: ", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInstructorNotFound
+	}
+	return nil
+}
+
+func (r *repo) Instructors(ctx context.Context, courseID string) ([]Instructor, error) {
+	var instructors []Instructor
+	result := r.db.WithContext(ctx).Where("course_id = ?", courseID).
+		Order("created_at, user_id").Find(&instructors)
+	if result.Error != nil {
+		r.log.Println("Error getting instructors: ", result.Error)
+		return nil, result.Error
+	}
+	return instructors, nil
+}
+
+func (r *repo) ScheduleConflicts(ctx context.Context, courseID string, userIDs []string, startDate, endDate time.Time) ([]ScheduleConflict, error) {
+	var conflicts []ScheduleConflict
+	// 🔧 FOR UPDATE recorre el índice por user_id: bloquea también el hueco, así dos
+	// asignaciones concurrentes del mismo instructor no pueden validarse a la vez
+	result := r.db.WithContext(ctx).Model(&Instructor{}).
+		Select("course_instructors.user_id, courses.id AS course_id, courses.name, courses.start_date, courses.end_date").
+		Joins("JOIN courses ON courses.id = course_instructors.course_id AND courses.deleted_at IS NULL").
+		Scopes(tenant.Scope(ctx, "courses")).
+		Where("course_instructors.user_id IN ? AND course_instructors.course_id <> ?", userIDs, courseID).
+		Where("courses.start_date <= ? AND courses.end_date >= ?", endDate, startDate).
+		Order("course_instructors.user_id, courses.start_date, courses.id").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Scan(&conflicts)
+	if result.Error != nil {
+		r.log.Println("Error getting schedule conflicts: ", result.Error)
+		return nil, result.Error
+	}
+	return conflicts, nil
+}
+
+func (r *repo) SetTranslation(ctx context.Context, translation *Translation) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "course_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+	}).Create(translation).Error
+	if err != nil {
+		r.log.Println("Error setting translation: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) RemoveTranslation(ctx context.Context, courseID, locale string) error {
+	result := r.db.WithContext(ctx).
+		Where("course_id = ? AND locale = ?", courseID, locale).
+		Delete(&Translation{})
+	if result.Error != nil {
+		r.log.Println("Error removing translation: ", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTranslationNotFound
+	}
+	return nil
+}
+
+func (r *repo) Translations(ctx context.Context, courseIDs []string) (map[string][]Translation, error) {
+	translations := make(map[string][]Translation, len(courseIDs))
+	if len(courseIDs) == 0 {
+		return translations, nil
+	}
+
+	var rows []Translation
+	result := r.db.WithContext(ctx).Where("course_id IN ?", courseIDs).Order("course_id, locale").Find(&rows)
+	if result.Error != nil {
+		r.log.Println("Error getting translations: ", result.Error)
+		return nil, result.Error
+	}
+	for _, row := range rows {
+		translations[row.CourseID] = append(translations[row.CourseID], row)
+	}
+	return translations, nil
+}
+
+// removeTranslations borra las traducciones de cursos eliminados definitivamente
+func (r *repo) removeTranslations(ctx context.Context, ids []string) error {
+	if err := r.db.WithContext(ctx).Where("course_id IN ?", ids).Delete(&Translation{}).Error; err != nil {
+		r.log.Println("Error removing translations: ", err)
+		return err
+	}
+	return nil
+}
+
+// removeInstructors borra las asignaciones de cursos eliminados definitivamente
+func (r *repo) removeInstructors(ctx context.Context, ids []string) error {
+	if err := r.db.WithContext(ctx).Where("course_id IN ?", ids).Delete(&Instructor{}).Error; err != nil {
+		r.log.Println("Error removing instructors: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) Transaction(ctx context.Context, fn func(txRepo Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repo{
+			db:  tx,
+			log: r.log,
+		})
+	})
+}
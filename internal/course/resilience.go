@@ -0,0 +1,349 @@
+package course
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/outbox"
+	"github.com/NicoJCastro/gocourse_course/internal/session"
+	"github.com/NicoJCastro/gocourse_course/pkg/breaker"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+	"github.com/go-sql-driver/mysql"
+)
+
+type (
+	// ResilienceConfig controla los reintentos, timeouts y el circuit breaker de NewResilientRepo
+	ResilienceConfig struct {
+		// Timeout de cada llamada al repositorio
+		Timeout time.Duration
+		// TxTimeout de una transacción completa
+		TxTimeout time.Duration
+		// MaxAttempts incluye el primer intento
+		MaxAttempts int
+		// BaseBackoff se duplica en cada reintento hasta MaxBackoff, con jitter completo
+		BaseBackoff time.Duration
+		MaxBackoff  time.Duration
+		// FailureThreshold son los fallos seguidos que abren el circuito
+		FailureThreshold int
+		// OpenTimeout es cuánto responde 503 el circuito abierto antes de volver a probar
+		OpenTimeout time.Duration
+	}
+
+	// resilientRepo decora un Repository: reintenta los errores transitorios de MySQL,
+	// corta cada llamada con un timeout y, con el circuito abierto, falla rápido con
+	// ErrUnavailable en lugar de acumular goroutines esperando a la base de datos
+	resilientRepo struct {
+		log     *log.Logger
+		repo    Repository
+		config  ResilienceConfig
+		breaker *breaker.Breaker
+	}
+)
+
+// mysqlTransientErrors son los códigos de MySQL que vale la pena reintentar
+var mysqlTransientErrors = map[uint16]bool{
+	1040: true, // ER_CON_COUNT_ERROR: demasiadas conexiones
+	1053: true, // ER_SERVER_SHUTDOWN
+	1205: true, // ER_LOCK_WAIT_TIMEOUT
+	1213: true, // ER_LOCK_DEADLOCK
+}
+
+func DefaultResilienceConfig() ResilienceConfig {
+	return ResilienceConfig{
+		Timeout:          5 * time.Second,
+		TxTimeout:        15 * time.Second,
+		MaxAttempts:      3,
+		BaseBackoff:      50 * time.Millisecond,
+		MaxBackoff:       time.Second,
+		FailureThreshold: 5,
+		OpenTimeout:      10 * time.Second,
+	}
+}
+
+func NewResilientRepo(logger *log.Logger, repo Repository, config ResilienceConfig) Repository {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+	return &resilientRepo{
+		log:     logger,
+		repo:    repo,
+		config:  config,
+		breaker: breaker.New(logger, "database", config.FailureThreshold, config.OpenTimeout),
+	}
+}
+
+// isTransient indica si err es un fallo pasajero de MySQL o de la conexión
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlTransientErrors[mysqlErr.Number]
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) ||
+		errors.As(err, &netErr)
+}
+
+// do ejecuta fn con timeout, reintentando los errores transitorios con backoff
+func (r *resilientRepo) do(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		if ok, wait := r.breaker.Allow(); !ok {
+			return &ErrUnavailable{RetryAfter: wait}
+		}
+
+		callCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout > 0 {
+			callCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		err := fn(callCtx)
+		// Sólo cuenta el timeout propio: si el cliente cortó la request no es culpa de la base
+		timedOut := err != nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+		cancel()
+
+		transient := isTransient(err)
+		r.breaker.Record(!transient && !timedOut)
+		if !transient && !timedOut {
+			return err
+		}
+		if timedOut || attempt >= r.config.MaxAttempts {
+			r.log.Printf("Database unavailable after %d attempts: %v\n", attempt, err)
+			return &ErrUnavailable{Err: err}
+		}
+
+		wait := r.backoff(attempt)
+		r.log.Printf("Transient database error (attempt %d of %d), retrying in %s: %v\n", attempt, r.config.MaxAttempts, wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// backoff: jitter completo sobre BaseBackoff * 2^(attempt-1), hasta MaxBackoff
+func (r *resilientRepo) backoff(attempt int) time.Duration {
+	limit := r.config.BaseBackoff << (attempt - 1)
+	if limit <= 0 || limit > r.config.MaxBackoff {
+		limit = r.config.MaxBackoff
+	}
+	if limit <= 0 {
+		return 0
+	}
+	return rand.N(limit) + 1
+}
+
+// call es do para los métodos que devuelven un resultado
+func call[T any](r *resilientRepo, ctx context.Context, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := r.do(ctx, r.config.Timeout, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (r *resilientRepo) exec(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.do(ctx, r.config.Timeout, fn)
+}
+
+func (r *resilientRepo) Create(ctx context.Context, course *domain.Course) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.Create(ctx, course) })
+}
+
+func (r *resilientRepo) CreateInBatches(ctx context.Context, courses []*domain.Course, batchSize int) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.CreateInBatches(ctx, courses, batchSize) })
+}
+
+func (r *resilientRepo) GetAll(ctx context.Context, filters Filters, offset, limit int) ([]Course, error) {
+	return call(r, ctx, func(ctx context.Context) ([]Course, error) { return r.repo.GetAll(ctx, filters, offset, limit) })
+}
+
+func (r *resilientRepo) Describe(ctx context.Context, courses ...domain.Course) ([]Course, error) {
+	return call(r, ctx, func(ctx context.Context) ([]Course, error) { return r.repo.Describe(ctx, courses...) })
+}
+
+func (r *resilientRepo) Timezones(ctx context.Context, courseIDs []string) (map[string]string, error) {
+	return call(r, ctx, func(ctx context.Context) (map[string]string, error) { return r.repo.Timezones(ctx, courseIDs) })
+}
+
+func (r *resilientRepo) SetTimezone(ctx context.Context, courseID, timezone string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.SetTimezone(ctx, courseID, timezone) })
+}
+
+func (r *resilientRepo) SetTags(ctx context.Context, courseID string, names []string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.SetTags(ctx, courseID, names) })
+}
+
+func (r *resilientRepo) SetCategory(ctx context.Context, courseID, categoryID string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.SetCategory(ctx, courseID, categoryID) })
+}
+
+func (r *resilientRepo) Get(ctx context.Context, id string) (*domain.Course, error) {
+	return call(r, ctx, func(ctx context.Context) (*domain.Course, error) { return r.repo.Get(ctx, id) })
+}
+
+func (r *resilientRepo) GetByIDs(ctx context.Context, ids []string, filters Filters) ([]domain.Course, error) {
+	return call(r, ctx, func(ctx context.Context) ([]domain.Course, error) { return r.repo.GetByIDs(ctx, ids, filters) })
+}
+
+func (r *resilientRepo) Delete(ctx context.Context, id string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.Delete(ctx, id) })
+}
+
+func (r *resilientRepo) Update(ctx context.Context, id string, name *string, startDate *time.Time, endDate *time.Time) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.Update(ctx, id, name, startDate, endDate) })
+}
+
+func (r *resilientRepo) Replace(ctx context.Context, course *domain.Course) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.Replace(ctx, course) })
+}
+
+func (r *resilientRepo) Count(ctx context.Context, filters Filters) (int64, error) {
+	return call(r, ctx, func(ctx context.Context) (int64, error) { return r.repo.Count(ctx, filters) })
+}
+
+// Stream no tiene timeout ni reintentos: una exportación puede durar y fn ya pudo
+// haber escrito filas. Sólo respeta el circuito.
+func (r *resilientRepo) Stream(ctx context.Context, filters Filters, fn func(course TenantCourse) error) error {
+	if ok, wait := r.breaker.Allow(); !ok {
+		return &ErrUnavailable{RetryAfter: wait}
+	}
+	err := r.repo.Stream(ctx, filters, fn)
+	transient := isTransient(err)
+	r.breaker.Record(!transient)
+	if transient {
+		return &ErrUnavailable{Err: err}
+	}
+	return err
+}
+
+func (r *resilientRepo) Restore(ctx context.Context, id string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.Restore(ctx, id) })
+}
+
+func (r *resilientRepo) Purge(ctx context.Context, id string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.Purge(ctx, id) })
+}
+
+func (r *resilientRepo) PurgeDeletedBefore(ctx context.Context, before time.Time) (map[string]string, error) {
+	return call(r, ctx, func(ctx context.Context) (map[string]string, error) { return r.repo.PurgeDeletedBefore(ctx, before) })
+}
+
+func (r *resilientRepo) RecordAudit(ctx context.Context, entries ...*audit.Entry) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.RecordAudit(ctx, entries...) })
+}
+
+func (r *resilientRepo) RecordEvents(ctx context.Context, messages ...*outbox.Message) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.RecordEvents(ctx, messages...) })
+}
+
+func (r *resilientRepo) History(ctx context.Context, id string, offset, limit int) ([]audit.Entry, error) {
+	return call(r, ctx, func(ctx context.Context) ([]audit.Entry, error) { return r.repo.History(ctx, id, offset, limit) })
+}
+
+func (r *resilientRepo) CountHistory(ctx context.Context, id string) (int64, error) {
+	return call(r, ctx, func(ctx context.Context) (int64, error) { return r.repo.CountHistory(ctx, id) })
+}
+
+func (r *resilientRepo) HistoryEntry(ctx context.Context, entryID string) (*audit.Entry, error) {
+	return call(r, ctx, func(ctx context.Context) (*audit.Entry, error) { return r.repo.HistoryEntry(ctx, entryID) })
+}
+
+func (r *resilientRepo) HistoryAfter(ctx context.Context, seq int64, limit int) ([]audit.Entry, error) {
+	return call(r, ctx, func(ctx context.Context) ([]audit.Entry, error) { return r.repo.HistoryAfter(ctx, seq, limit) })
+}
+
+func (r *resilientRepo) LastHistorySeq(ctx context.Context) (int64, error) {
+	return call(r, ctx, func(ctx context.Context) (int64, error) { return r.repo.LastHistorySeq(ctx) })
+}
+
+func (r *resilientRepo) HistorySeqAt(ctx context.Context, at time.Time) (int64, error) {
+	return call(r, ctx, func(ctx context.Context) (int64, error) { return r.repo.HistorySeqAt(ctx, at) })
+}
+
+func (r *resilientRepo) Sessions(ctx context.Context, courseID string) ([]session.Session, error) {
+	return call(r, ctx, func(ctx context.Context) ([]session.Session, error) { return r.repo.Sessions(ctx, courseID) })
+}
+
+// LockCourses sólo tiene sentido dentro de Transaction, que recibe el repositorio sin envolver
+func (r *resilientRepo) LockCourses(ctx context.Context, ids ...string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.LockCourses(ctx, ids...) })
+}
+
+func (r *resilientRepo) LockPrerequisites(ctx context.Context) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.LockPrerequisites(ctx) })
+}
+
+func (r *resilientRepo) AddPrerequisite(ctx context.Context, courseID, prerequisiteID string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.AddPrerequisite(ctx, courseID, prerequisiteID) })
+}
+
+func (r *resilientRepo) RemovePrerequisite(ctx context.Context, courseID, prerequisiteID string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.RemovePrerequisite(ctx, courseID, prerequisiteID) })
+}
+
+func (r *resilientRepo) Prerequisites(ctx context.Context, courseIDs []string) (map[string][]string, error) {
+	return call(r, ctx, func(ctx context.Context) (map[string][]string, error) { return r.repo.Prerequisites(ctx, courseIDs) })
+}
+
+func (r *resilientRepo) Dependents(ctx context.Context, courseID string) ([]string, error) {
+	return call(r, ctx, func(ctx context.Context) ([]string, error) { return r.repo.Dependents(ctx, courseID) })
+}
+
+func (r *resilientRepo) RemoveDependents(ctx context.Context, courseID string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.RemoveDependents(ctx, courseID) })
+}
+
+func (r *resilientRepo) AddInstructors(ctx context.Context, courseID string, userIDs []string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.AddInstructors(ctx, courseID, userIDs) })
+}
+
+func (r *resilientRepo) RemoveInstructor(ctx context.Context, courseID, userID string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.RemoveInstructor(ctx, courseID, userID) })
+}
+
+func (r *resilientRepo) Instructors(ctx context.Context, courseID string) ([]Instructor, error) {
+	return call(r, ctx, func(ctx context.Context) ([]Instructor, error) { return r.repo.Instructors(ctx, courseID) })
+}
+
+func (r *resilientRepo) SetTranslation(ctx context.Context, translation *Translation) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.SetTranslation(ctx, translation) })
+}
+
+func (r *resilientRepo) RemoveTranslation(ctx context.Context, courseID, locale string) error {
+	return r.exec(ctx, func(ctx context.Context) error { return r.repo.RemoveTranslation(ctx, courseID, locale) })
+}
+
+func (r *resilientRepo) Translations(ctx context.Context, courseIDs []string) (map[string][]Translation, error) {
+	return call(r, ctx, func(ctx context.Context) (map[string][]Translation, error) {
+		return r.repo.Translations(ctx, courseIDs)
+	})
+}
+
+func (r *resilientRepo) ScheduleConflicts(ctx context.Context, courseID string, userIDs []string, startDate, endDate time.Time) ([]ScheduleConflict, error) {
+	return call(r, ctx, func(ctx context.Context) ([]ScheduleConflict, error) {
+		return r.repo.ScheduleConflicts(ctx, courseID, userIDs, startDate, endDate)
+	})
+}
+
+// Transaction reintenta la transacción completa, porque un deadlock la revierte
+// entera. fn recibe el repositorio sin envolver: dentro de la transacción no se
+// puede reintentar una sola llamada, y el timeout de la transacción ya la cubre.
+func (r *resilientRepo) Transaction(ctx context.Context, fn func(txRepo Repository) error) error {
+	return r.do(ctx, r.config.TxTimeout, func(ctx context.Context) error {
+		return r.repo.Transaction(ctx, fn)
+	})
+}
@@ -0,0 +1,181 @@
+package course_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/NicoJCastro/gocourse_course/pkg/handler"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+	"github.com/go-sql-driver/mysql"
+)
+
+const courseID = "0b6f3c1e-1d2a-4e5f-9a8b-7c6d5e4f3a2b"
+
+// fakeRepo responde Get con errs, uno por llamada, y después con un curso; el resto
+// de los métodos no se usa en estas pruebas
+type fakeRepo struct {
+	course.Repository
+	errs  []error
+	block bool
+	calls atomic.Int32
+}
+
+func (f *fakeRepo) Get(ctx context.Context, id string) (*domain.Course, error) {
+	call := int(f.calls.Add(1))
+	if f.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if call <= len(f.errs) {
+		return nil, f.errs[call-1]
+	}
+	return &domain.Course{ID: id, Name: "Go basics"}, nil
+}
+
+func resilienceConfig() course.ResilienceConfig {
+	return course.ResilienceConfig{
+		Timeout:          20 * time.Millisecond,
+		MaxAttempts:      3,
+		BaseBackoff:      time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		FailureThreshold: 2,
+		OpenTimeout:      50 * time.Millisecond,
+	}
+}
+
+func TestResilientRetries(t *testing.T) {
+	duplicate := &mysql.MySQLError{Number: 1062}
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int32
+		wantErr   error
+	}{
+		{"lock wait timeout", []error{&mysql.MySQLError{Number: 1205}}, 2, nil},
+		{"deadlock", []error{&mysql.MySQLError{Number: 1213}, &mysql.MySQLError{Number: 1213}}, 3, nil},
+		{"duplicate entry is not retried", []error{duplicate}, 1, duplicate},
+		{"gives up after MaxAttempts", []error{&mysql.MySQLError{Number: 1213}, &mysql.MySQLError{Number: 1213}, &mysql.MySQLError{Number: 1213}}, 3, course.ErrUnavailableBase},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeRepo{errs: tt.errs}
+			cfg := resilienceConfig()
+			cfg.FailureThreshold = 10
+			repo := course.NewResilientRepo(tenanttest.Logger(), fake, cfg)
+
+			if _, err := repo.Get(context.Background(), courseID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Get = %v, want %v", err, tt.wantErr)
+			}
+			if calls := fake.calls.Load(); calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestResilientBreaker(t *testing.T) {
+	deadlock := &mysql.MySQLError{Number: 1213}
+	fake := &fakeRepo{errs: []error{deadlock, deadlock, deadlock}}
+	cfg := resilienceConfig()
+	cfg.MaxAttempts = 1
+	repo := course.NewResilientRepo(tenanttest.Logger(), fake, cfg)
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := repo.Get(ctx, courseID); !errors.Is(err, course.ErrUnavailableBase) {
+			t.Fatalf("Get = %v, want %v", err, course.ErrUnavailableBase)
+		}
+	}
+
+	// Abierto: falla rápido sin llamar al repositorio e indica cuándo reintentar
+	_, err := repo.Get(ctx, courseID)
+	var unavailable *course.ErrUnavailable
+	if !errors.As(err, &unavailable) || unavailable.RetryAfter <= 0 {
+		t.Fatalf("Get with the circuit open = %v, want ErrUnavailable with RetryAfter", err)
+	}
+	if calls := fake.calls.Load(); calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+
+	// Half-open: la llamada de prueba falla y el circuito vuelve a abrirse
+	time.Sleep(cfg.OpenTimeout)
+	if _, err := repo.Get(ctx, courseID); !errors.Is(err, course.ErrUnavailableBase) {
+		t.Fatalf("half-open Get = %v, want %v", err, course.ErrUnavailableBase)
+	}
+	if _, err := repo.Get(ctx, courseID); !errors.As(err, &unavailable) || fake.calls.Load() != 3 {
+		t.Fatalf("Get after a failed probe = %v (%d calls), want the circuit open again", err, fake.calls.Load())
+	}
+
+	// La siguiente prueba sale bien y lo cierra
+	time.Sleep(cfg.OpenTimeout)
+	for range 2 {
+		if _, err := repo.Get(ctx, courseID); err != nil {
+			t.Fatalf("Get after recovery = %v", err)
+		}
+	}
+	if calls := fake.calls.Load(); calls != 5 {
+		t.Errorf("calls = %d, want 5", calls)
+	}
+}
+
+func TestResilientTimeout(t *testing.T) {
+	fake := &fakeRepo{block: true}
+	cfg := resilienceConfig()
+	cfg.FailureThreshold = 1
+	repo := course.NewResilientRepo(tenanttest.Logger(), fake, cfg)
+
+	// El timeout propio no se reintenta y cuenta como fallo: abre el circuito
+	if _, err := repo.Get(context.Background(), courseID); !errors.Is(err, course.ErrUnavailableBase) {
+		t.Fatalf("Get = %v, want %v", err, course.ErrUnavailableBase)
+	}
+	if calls := fake.calls.Load(); calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	if _, err := repo.Get(context.Background(), courseID); !errors.Is(err, course.ErrUnavailableBase) || fake.calls.Load() != 1 {
+		t.Errorf("Get after the timeout = %v, want the circuit open", err)
+	}
+
+	t.Run("the client cancels", func(t *testing.T) {
+		fake := &fakeRepo{block: true}
+		repo := course.NewResilientRepo(tenanttest.Logger(), fake, cfg)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := repo.Get(ctx, courseID); errors.Is(err, course.ErrUnavailableBase) {
+			t.Errorf("Get = %v, want the cancellation, not a database failure", err)
+		}
+		if _, err := repo.Get(context.Background(), courseID); !errors.Is(err, course.ErrUnavailableBase) || fake.calls.Load() != 2 {
+			t.Errorf("Get after a cancelled request = %v, want the circuit still closed", err)
+		}
+	})
+}
+
+func TestUnavailableResponse(t *testing.T) {
+	fake := &fakeRepo{errs: []error{&mysql.MySQLError{Number: 1040}}}
+	cfg := resilienceConfig()
+	cfg.MaxAttempts = 1
+	cfg.FailureThreshold = 1
+	cfg.OpenTimeout = 3 * time.Second
+	repo := course.NewResilientRepo(tenanttest.Logger(), fake, cfg)
+	svc := course.NewService(tenanttest.Logger(), repo, nil, course.PrerequisiteRestrict, nil, config.Locales{Default: "en", Supported: []string{"en"}})
+	server := handler.NewCourseHTTPServer(context.Background(), course.MakeEndpoint(svc, course.Config{Pagination: config.Default().Pagination}))
+
+	for _, want := range []string{"1", "3"} {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/courses/"+courseID, nil))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want 503: %s", w.Code, w.Body)
+		}
+		// Sin RetryAfter conocido se sugiere 1s; con el circuito abierto, lo que falta para reabrirlo
+		if got := w.Header().Get("Retry-After"); got != want {
+			t.Errorf("Retry-After = %q, want %s", got, want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type (
+	// Searcher es el backend de búsqueda de texto completo (MySQL FULLTEXT o Bleve).
+	// Devuelve IDs ordenados por relevancia; el servicio carga los cursos de la base.
+	Searcher interface {
+		Search(ctx context.Context, q string, filters Filters, offset, limit int) ([]SearchMatch, int64, error)
+		Suggest(ctx context.Context, prefix string, limit int) ([]string, error)
+	}
+
+	// SearchMatch es un resultado del backend antes de cargar el curso
+	SearchMatch struct {
+		ID         string
+		Score      float64
+		Highlights map[string][]string
+	}
+
+	// SearchHit es un curso encontrado con su puntaje y los fragmentos resaltados con <mark>
+	SearchHit struct {
+		Course     Course              `json:"course"`
+		Score      float64             `json:"score"`
+		Highlights map[string][]string `json:"highlights,omitempty"`
+	}
+)
+
+const (
+	// MaxSearchQueryLength evita consultas desmedidas contra el índice
+	MaxSearchQueryLength = 200
+	MaxSuggestions       = 20
+	DefaultSuggestions   = 10
+
+	highlightPre  = "<mark>"
+	highlightPost = "</mark>"
+)
+
+// Search busca cursos por relevancia; Filters se aplica igual que en GetAll
+func (s service) Search(ctx context.Context, q string, filters Filters, offset, limit int) ([]SearchHit, int64, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return nil, 0, ErrSearchQueryRequired
+	}
+	if len(q) > MaxSearchQueryLength {
+		return nil, 0, ErrSearchQueryTooLong
+	}
+
+	matches, total, err := s.searcher.Search(ctx, q, filters, offset, limit)
+	if err != nil {
+		s.log.Println(err)
+		if errors.Is(err, ErrSearchFilterUnsupported) {
+			return nil, 0, err
+		}
+		return nil, 0, fmt.Errorf("%w: %w", ErrFailedToSearch, err)
+	}
+	if len(matches) == 0 {
+		return []SearchHit{}, total, nil
+	}
+
+	ids := make([]string, 0, len(matches))
+	for _, m := range matches {
+		ids = append(ids, m.ID)
+	}
+	found, err := s.repo.GetByIDs(ctx, ids, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrFailedToSearch, err)
+	}
+	courses, err := s.describe(ctx, found...)
+	if err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[string]Course, len(courses))
+	for _, c := range courses {
+		byID[c.ID] = c
+	}
+
+	// Se respeta el orden del backend; un índice desactualizado puede traer IDs que ya no existen
+	hits := make([]SearchHit, 0, len(matches))
+	for _, m := range matches {
+		course, ok := byID[m.ID]
+		if !ok {
+			continue
+		}
+		hits = append(hits, SearchHit{
+			Course:     course,
+			Score:      m.Score,
+			Highlights: m.Highlights,
+		})
+	}
+	return hits, total, nil
+}
+
+// Suggest autocompleta nombres de cursos activos a partir de un prefijo
+func (s service) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return nil, ErrSearchQueryRequired
+	}
+	if len(prefix) > MaxSearchQueryLength {
+		return nil, ErrSearchQueryTooLong
+	}
+	if limit <= 0 {
+		limit = DefaultSuggestions
+	}
+	if limit > MaxSuggestions {
+		limit = MaxSuggestions
+	}
+
+	suggestions, err := s.searcher.Suggest(ctx, prefix, limit)
+	if err != nil {
+		s.log.Println(err)
+		return nil, fmt.Errorf("%w: %w", ErrFailedToSearch, err)
+	}
+	return suggestions, nil
+}
+
+// searchTerms separa la consulta en palabras normalizadas con foldText, descartando
+// los operadores que los backends interpretarían (+, -, *, comillas, etc.)
+func searchTerms(q string) []string {
+	return strings.FieldsFunc(foldText(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// foldText pasa a minúsculas y quita los acentos, como hace la collation de MySQL
+func foldText(text string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// highlight escapa text y marca las palabras que empiezan con alguno de los términos;
+// los dos backends lo usan para que el resaltado sea el mismo
+func highlight(text string, terms []string) string {
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			b.WriteString(html.EscapeString(string(runes[i])))
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+			j++
+		}
+		word := string(runes[i:j])
+		folded := foldText(word)
+		marked := false
+		for _, term := range terms {
+			if strings.HasPrefix(folded, term) {
+				marked = true
+				break
+			}
+		}
+		if marked {
+			b.WriteString(highlightPre + html.EscapeString(word) + highlightPost)
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		i = j
+	}
+	return b.String()
+}
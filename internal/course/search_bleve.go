@@ -0,0 +1,335 @@
+package course
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/single"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+type (
+	// BleveSearcher mantiene un índice Bleve embebido en memoria. Se llena con
+	// SearchIndexer, que lo reconstruye al arrancar y lo sigue con el feed de cambios.
+	BleveSearcher struct {
+		log   *log.Logger
+		index bleve.Index
+	}
+
+	// bleveDocument es lo que se indexa por curso; el ID del documento es el del curso.
+	// name se guarda sin indexar para las sugerencias y el resaltado; las búsquedas van
+	// contra text, el nombre ya normalizado con foldText.
+	bleveDocument struct {
+		Name    string `json:"name"`
+		Text    string `json:"text"`
+		NameRaw string `json:"name_raw"`
+		Deleted bool   `json:"deleted"`
+		Tenant  string `json:"tenant"`
+	}
+
+	// SearchIndexer sincroniza un BleveSearcher con la base de datos
+	SearchIndexer struct {
+		log      *log.Logger
+		service  Service
+		searcher *BleveSearcher
+		interval time.Duration
+	}
+)
+
+const (
+	bleveTextAnalyzer    = "course_text"
+	bleveKeywordAnalyzer = "course_keyword"
+)
+
+// bleveFuzziness evita que "go" matchee con "js": sólo se toleran errores en palabras largas
+func bleveFuzziness(term string) int {
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		return 2
+	case n >= 5:
+		return 1
+	}
+	return 0
+}
+
+func NewBleveSearcher(log *log.Logger) (*BleveSearcher, error) {
+	indexMapping := bleve.NewIndexMapping()
+
+	// Sin stop words en inglés: los nombres de los cursos suelen estar en español
+	if err := indexMapping.AddCustomAnalyzer(bleveTextAnalyzer, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     unicode.Name,
+		"token_filters": []string{lowercase.Name},
+	}); err != nil {
+		return nil, err
+	}
+	// name_raw replica el LIKE '%x%' de applyFilters con un regexp sobre el nombre completo
+	if err := indexMapping.AddCustomAnalyzer(bleveKeywordAnalyzer, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     single.Name,
+		"token_filters": []string{lowercase.Name},
+	}); err != nil {
+		return nil, err
+	}
+
+	nameField := bleve.NewTextFieldMapping()
+	nameField.Index = false
+	nameField.IncludeInAll = false
+
+	textField := bleve.NewTextFieldMapping()
+	textField.Analyzer = bleveTextAnalyzer
+	textField.Store = false
+	textField.IncludeTermVectors = true
+
+	nameRawField := bleve.NewTextFieldMapping()
+	nameRawField.Analyzer = bleveKeywordAnalyzer
+	nameRawField.Store = false
+
+	deletedField := bleve.NewBooleanFieldMapping()
+	deletedField.Store = false
+
+	tenantField := bleve.NewKeywordFieldMapping()
+	tenantField.Store = false
+	tenantField.IncludeInAll = false
+
+	courseMapping := bleve.NewDocumentMapping()
+	courseMapping.AddFieldMappingsAt("name", nameField)
+	courseMapping.AddFieldMappingsAt("text", textField)
+	courseMapping.AddFieldMappingsAt("name_raw", nameRawField)
+	courseMapping.AddFieldMappingsAt("deleted", deletedField)
+	courseMapping.AddFieldMappingsAt("tenant", tenantField)
+	indexMapping.DefaultMapping = courseMapping
+
+	index, err := bleve.NewMemOnly(indexMapping)
+	if err != nil {
+		return nil, err
+	}
+	return &BleveSearcher{
+		log:   log,
+		index: index,
+	}, nil
+}
+
+// Search no indexa tags ni categorías: filtrar después por ellos dejaría mal el total
+// y las páginas, así que esos filtros se rechazan (el backend mysql sí los aplica)
+func (s *BleveSearcher) Search(ctx context.Context, q string, filters Filters, offset, limit int) ([]SearchMatch, int64, error) {
+	if len(filters.Tags) > 0 || filters.Category != "" {
+		return nil, 0, ErrSearchFilterUnsupported
+	}
+	terms := searchTerms(q)
+	if len(terms) == 0 {
+		return []SearchMatch{}, 0, nil
+	}
+
+	// Cada término puede aparecer tal cual, con un error de tipeo o como prefijo
+	must := make([]query.Query, 0, len(terms)+2)
+	for _, term := range terms {
+		match := bleve.NewMatchQuery(term)
+		match.SetField("text")
+		match.SetFuzziness(bleveFuzziness(term))
+		prefix := bleve.NewPrefixQuery(term)
+		prefix.SetField("text")
+		prefix.SetBoost(0.5)
+		must = append(must, bleve.NewDisjunctionQuery(match, prefix))
+	}
+	must = append(must, s.filterQueries(ctx, filters)...)
+
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(must...), limit, offset, false)
+	req.Fields = []string{"name"}
+	req.IncludeLocations = true
+
+	result, err := s.index.SearchInContext(ctx, req)
+	if err != nil {
+		s.log.Println(err)
+		return nil, 0, err
+	}
+
+	matches := make([]SearchMatch, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		// Los términos del índice que matchearon incluyen las correcciones de tipeo
+		matched := append([]string{}, terms...)
+		for term := range hit.Locations["text"] {
+			matched = append(matched, term)
+		}
+		name, _ := hit.Fields["name"].(string)
+		matches = append(matches, SearchMatch{
+			ID:         hit.ID,
+			Score:      hit.Score,
+			Highlights: map[string][]string{"name": {highlight(name, matched)}},
+		})
+	}
+	return matches, int64(result.Total), nil
+}
+
+func (s *BleveSearcher) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	terms := searchTerms(prefix)
+	if len(terms) == 0 {
+		return []string{}, nil
+	}
+
+	// Las palabras completas deben aparecer y la última se completa como prefijo
+	must := make([]query.Query, 0, len(terms)+1)
+	for i, term := range terms {
+		if i == len(terms)-1 {
+			q := bleve.NewPrefixQuery(term)
+			q.SetField("text")
+			must = append(must, q)
+			continue
+		}
+		q := bleve.NewTermQuery(term)
+		q.SetField("text")
+		must = append(must, q)
+	}
+	must = append(must, s.filterQueries(ctx, Filters{})...)
+
+	// Se piden más documentos que limit porque varios cursos pueden compartir el nombre
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(must...), limit*3, 0, false)
+	req.Fields = []string{"name"}
+	result, err := s.index.SearchInContext(ctx, req)
+	if err != nil {
+		s.log.Println(err)
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, hit := range result.Hits {
+		name, _ := hit.Fields["name"].(string)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+		if len(names) == limit {
+			break
+		}
+	}
+	return names, nil
+}
+
+// filterQueries traduce Filters a consultas sobre el índice con la misma semántica que
+// applyFilters, salvo tags y categoría que Search rechaza; el índice es compartido, así
+// que también filtra por el tenant del contexto
+func (s *BleveSearcher) filterQueries(ctx context.Context, filters Filters) []query.Query {
+	var queries []query.Query
+
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		q := bleve.NewTermQuery(bleveTenant(tenantID))
+		q.SetField("tenant")
+		queries = append(queries, q)
+	}
+
+	switch filters.Deleted {
+	case DeletedExclude:
+		q := bleve.NewBoolFieldQuery(false)
+		q.SetField("deleted")
+		queries = append(queries, q)
+	case DeletedOnly:
+		q := bleve.NewBoolFieldQuery(true)
+		q.SetField("deleted")
+		queries = append(queries, q)
+	}
+
+	if filters.Name != "" {
+		q := bleve.NewRegexpQuery(".*" + regexp.QuoteMeta(foldText(filters.Name)) + ".*")
+		q.SetField("name_raw")
+		queries = append(queries, q)
+	}
+	return queries
+}
+
+// Index agrega o reemplaza el documento del curso
+func (s *BleveSearcher) Index(course domain.Course, tenantID string, deleted bool) error {
+	return s.index.Index(course.ID, bleveDocument{
+		Name:    course.Name,
+		Text:    foldText(course.Name),
+		NameRaw: foldText(course.Name),
+		Deleted: deleted,
+		Tenant:  bleveTenant(tenantID),
+	})
+}
+
+// bleveTenant evita un término vacío para el tenant por defecto, que no se indexaría
+func bleveTenant(tenantID string) string {
+	return "tenant:" + tenantID
+}
+
+// Remove quita el curso del índice (purge)
+func (s *BleveSearcher) Remove(id string) error {
+	return s.index.Delete(id)
+}
+
+func (s *BleveSearcher) Close() error {
+	return s.index.Close()
+}
+
+func NewSearchIndexer(logger *log.Logger, service Service, searcher *BleveSearcher, interval time.Duration) *SearchIndexer {
+	return &SearchIndexer{
+		log:      logger,
+		service:  service,
+		searcher: searcher,
+		interval: interval,
+	}
+}
+
+// Run reconstruye el índice y lo mantiene al día hasta que se cancele ctx.
+// El feed se abre antes de la carga inicial para no perder cambios intermedios.
+func (i *SearchIndexer) Run(ctx context.Context) {
+	feed, err := i.service.Changes(ctx, "", Filters{Deleted: DeletedInclude})
+	if err != nil {
+		i.log.Println("error opening search change feed: ", err)
+		return
+	}
+
+	var indexed int
+	err = i.service.Export(ctx, Filters{Deleted: DeletedInclude}, func(course TenantCourse) error {
+		indexed++
+		return i.searcher.Index(course.Course, course.TenantID, course.DeletedAt.Valid)
+	})
+	if err != nil {
+		i.log.Println("error building search index: ", err)
+		return
+	}
+	i.log.Printf("search index built with %d courses", indexed)
+
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		events, err := feed.Next(ctx)
+		if err != nil {
+			i.log.Println("error reading search change feed: ", err)
+			continue
+		}
+		for _, event := range events {
+			if err := i.apply(event); err != nil {
+				i.log.Println("error updating search index: ", err)
+			}
+		}
+	}
+}
+
+func (i *SearchIndexer) apply(event ChangeEvent) error {
+	if event.Type == changeEventType(audit.ActionPurge) {
+		return i.searcher.Remove(event.CourseID)
+	}
+	if event.Course == nil {
+		return nil
+	}
+	return i.searcher.Index(*event.Course, event.TenantID, event.Type == changeEventType(audit.ActionDelete))
+}
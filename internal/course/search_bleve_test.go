@@ -0,0 +1,56 @@
+package course_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+func TestBleveSearchFilters(t *testing.T) {
+	searcher, err := course.NewBleveSearcher(tenanttest.Logger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer searcher.Close()
+
+	ctxA, ctxB := tenanttest.Contexts()
+	docs := []struct {
+		id, name, tenantID string
+		deleted            bool
+	}{
+		{"c1", "Go basics", tenanttest.A, false},
+		{"c2", "Go avanzado", tenanttest.A, true},
+		{"c3", "Go para equipos", tenanttest.B, false},
+	}
+	for _, doc := range docs {
+		if err := searcher.Index(domain.Course{ID: doc.id, Name: doc.name}, doc.tenantID, doc.deleted); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches, total, err := searcher.Search(ctxA, "go", course.Filters{Deleted: course.DeletedExclude}, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(matches) != 1 || matches[0].ID != "c1" {
+		t.Errorf("Search = %+v (total %d), want only c1", matches, total)
+	}
+	if _, total, err := searcher.Search(ctxB, "go", course.Filters{Deleted: course.DeletedInclude}, 0, 10); err != nil || total != 1 {
+		t.Errorf("Search for %s = %d, %v; want 1", tenanttest.B, total, err)
+	}
+
+	// Sin tags ni categorías en el índice el total saldría mal: se rechazan
+	for name, filters := range map[string]course.Filters{
+		"tags":     {Tags: []string{"backend"}},
+		"category": {Category: "programacion"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := searcher.Search(ctxA, "go", filters, 0, 10); !errors.Is(err, course.ErrSearchFilterUnsupported) {
+				t.Errorf("Search = %v, want %v", err, course.ErrSearchFilterUnsupported)
+			}
+		})
+	}
+}
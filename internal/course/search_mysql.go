@@ -0,0 +1,119 @@
+package course
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/NicoJCastro/gocourse_domain/domain"
+	"gorm.io/gorm"
+)
+
+// fullTextIndex es el índice FULLTEXT sobre courses.name que usa mysqlSearcher
+const fullTextIndex = "idx_courses_name_fulltext"
+
+// minFullTextTerm es innodb_ft_min_token_size por defecto: InnoDB no indexa palabras más cortas
+const minFullTextTerm = 3
+
+type mysqlSearcher struct {
+	log *log.Logger
+	db  *gorm.DB
+}
+
+// NewMySQLSearcher busca con MATCH ... AGAINST sobre el índice FULLTEXT de courses.name
+func NewMySQLSearcher(log *log.Logger, db *gorm.DB) Searcher {
+	return &mysqlSearcher{
+		log: log,
+		db:  db,
+	}
+}
+
+// EnsureFullTextIndex crea el índice FULLTEXT; domain.Course no lo declara en sus tags
+func EnsureFullTextIndex(db *gorm.DB) error {
+	if db.Migrator().HasIndex(&domain.Course{}, fullTextIndex) {
+		return nil
+	}
+	return db.Exec("CREATE FULLTEXT INDEX " + fullTextIndex + " ON courses (name)").Error
+}
+
+func (s *mysqlSearcher) Search(ctx context.Context, q string, filters Filters, offset, limit int) ([]SearchMatch, int64, error) {
+	terms := searchTerms(q)
+	against := booleanQuery(terms)
+
+	tx := s.db.WithContext(ctx).Model(&domain.Course{})
+	tx = applyFilters(tx, filters)
+	score := "0"
+	var args []interface{}
+	if against != "" {
+		tx = tx.Where("MATCH(name) AGAINST (? IN BOOLEAN MODE)", against)
+		score = "MATCH(name) AGAINST (? IN BOOLEAN MODE)"
+		args = append(args, against)
+	} else {
+		// Sólo palabras más cortas que el mínimo de InnoDB: no hay ranking posible
+		tx = tx.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(strings.TrimSpace(q))+"%")
+	}
+
+	var total int64
+	if err := tx.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		s.log.Println(err)
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		ID    string
+		Name  string
+		Score float64
+	}
+	result := tx.Select("id, name, "+score+" AS score", args...).
+		Order("score desc, created_at desc").
+		Offset(offset).
+		Limit(limit).
+		Scan(&rows)
+	if result.Error != nil {
+		s.log.Println(result.Error)
+		return nil, 0, result.Error
+	}
+
+	matches := make([]SearchMatch, 0, len(rows))
+	for _, row := range rows {
+		matches = append(matches, SearchMatch{
+			ID:         row.ID,
+			Score:      row.Score,
+			Highlights: map[string][]string{"name": {highlight(row.Name, terms)}},
+		})
+	}
+	return matches, total, nil
+}
+
+func (s *mysqlSearcher) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	against := booleanQuery(searchTerms(prefix))
+	if against == "" {
+		return []string{}, nil
+	}
+
+	names := []string{}
+	result := s.db.WithContext(ctx).Model(&domain.Course{}).
+		Distinct("name").
+		Where("MATCH(name) AGAINST (? IN BOOLEAN MODE)", against).
+		Order("name").
+		Limit(limit).
+		Pluck("name", &names)
+	if result.Error != nil {
+		s.log.Println(result.Error)
+		return nil, result.Error
+	}
+	return names, nil
+}
+
+// booleanQuery exige cada término como prefijo ("+go* +avanz*"), lo que también
+// sirve para buscar mientras se escribe
+func booleanQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if len([]rune(term)) < minFullTextTerm {
+			continue
+		}
+		parts = append(parts, "+"+term+"*")
+	}
+	return strings.Join(parts, " ")
+}
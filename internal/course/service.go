@@ -1,183 +1,773 @@
-package course
-
-import (
-	"context"
-	"errors"
-	"fmt"
-	"log"
-	"time"
-
-	"github.com/NicoJCastro/gocourse_domain/domain"
-)
-
-type (
-	Filters struct {
-		Name string
-	}
-
-	Service interface {
-		Create(ctx context.Context, name, startDate, endDate string) (*domain.Course, error)
-		Get(ctx context.Context, id string) (*domain.Course, error)
-		GetAll(ctx context.Context, filters Filters, offset, limit int) ([]domain.Course, error)
-		Delete(ctx context.Context, id string) error
-		Update(ctx context.Context, id string, name *string, startDate *string, endDate *string) error
-		Count(ctx context.Context, filters Filters) (int64, error)
-	}
-
-	service struct {
-		log  *log.Logger
-		repo Repository
-	}
-)
-
-func NewService(log *log.Logger, repo Repository) Service {
-	return &service{
-		log:  log,
-		repo: repo,
-	}
-}
-
-func (s *service) Create(ctx context.Context, name, startDate, endDate string) (*domain.Course, error) {
-	s.log.Println("---- Creating course ----")
-
-	startDateParsed, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
-		s.log.Println("Error parsing start date:", err)
-		return nil, fmt.Errorf("%w: %v", ErrInvalidStartDate, err)
-	}
-
-	endDateParsed, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
-		s.log.Println("Error parsing end date:", err)
-		return nil, fmt.Errorf("%w: %v", ErrInvalidEndDate, err)
-	}
-
-	// 🔧 Validar que la fecha de inicio no sea después de la fecha de fin
-	if startDateParsed.After(endDateParsed) {
-		s.log.Println("Start date is after end date")
-		return nil, ErrStartDateAfterEndDate
-	}
-
-	course := &domain.Course{
-		Name:      name,
-		StartDate: startDateParsed,
-		EndDate:   endDateParsed,
-	}
-
-	if err := s.repo.Create(ctx, course); err != nil {
-		s.log.Printf("Error creating course: %v\n", err)
-		return nil, fmt.Errorf("%w: %v", ErrFailedToCreateCourse, err)
-	}
-
-	return course, nil
-}
-
-func (s service) GetAll(ctx context.Context, filters Filters, offset, limit int) ([]domain.Course, error) {
-	s.log.Println("---- Getting all courses ----")
-	courses, err := s.repo.GetAll(ctx, filters, offset, limit)
-	if err != nil {
-		s.log.Printf("Error getting courses: %v\n", err)
-		// No envolvemos ErrNotFound, lo propagamos directamente
-		var notFoundErr *ErrNotFound
-		if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
-			return nil, err
-		}
-		return nil, fmt.Errorf("%w: %v", ErrFailedToGetAllCourses, err)
-	}
-	return courses, nil
-}
-
-func (s service) Get(ctx context.Context, id string) (*domain.Course, error) {
-	course, err := s.repo.Get(ctx, id)
-	if err != nil {
-		s.log.Printf("Error getting course: %v\n", err)
-		// No envolvemos ErrNotFound, lo propagamos directamente
-		var notFoundErr *ErrNotFound
-		if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
-			return nil, err
-		}
-		return nil, fmt.Errorf("%w: %v", ErrFailedToGetCourse, err)
-	}
-	return course, nil
-}
-
-func (s service) Delete(ctx context.Context, id string) error {
-	s.log.Println("---- Deleting course ----")
-	err := s.repo.Delete(ctx, id)
-	if err != nil {
-		// No envolvemos ErrNotFound, lo propagamos directamente
-		var notFoundErr *ErrNotFound
-		if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
-			return err
-		}
-		return fmt.Errorf("%w: %v", ErrFailedToDeleteCourse, err)
-	}
-	return nil
-}
-
-func (s service) Update(ctx context.Context, id string, name *string, startDate *string, endDate *string) error {
-	s.log.Println("---- Updating course ----")
-
-	var startDateParsed, endDateParsed *time.Time
-
-	course, err := s.Get(ctx, id)
-	if err != nil {
-		return err
-	}
-
-	// Usar las fechas existentes como valores por defecto si no se proporcionan nuevas
-	currentStartDate := course.StartDate
-	currentEndDate := course.EndDate
-
-	if startDate != nil {
-		parsedDate, err := time.Parse("2006-01-02", *startDate)
-		if err != nil {
-			s.log.Printf("Error parsing start date: %v\n", err)
-			return fmt.Errorf("%w: %v", ErrInvalidStartDate, err)
-		}
-		startDateParsed = &parsedDate
-		currentStartDate = parsedDate
-	}
-
-	if endDate != nil {
-		parsedDate, err := time.Parse("2006-01-02", *endDate)
-		if err != nil {
-			s.log.Printf("Error parsing end date: %v\n", err)
-			return fmt.Errorf("%w: %v", ErrInvalidEndDate, err)
-		}
-		endDateParsed = &parsedDate
-		currentEndDate = parsedDate
-
-		// 🔧 Si se está actualizando endDate, validar que no sea antes del startDate
-		if currentEndDate.Before(currentStartDate) {
-			s.log.Println("End date is before start date")
-			return ErrEndDateBeforeStartDate
-		}
-	}
-
-	// 🔧 Validar que la fecha de inicio no sea después de la fecha de fin (usando valores actuales)
-	// Esta validación se ejecuta si se actualiza startDate o si ambas fechas están presentes
-	if currentStartDate.After(currentEndDate) {
-		s.log.Println("Start date is after end date")
-		return ErrStartDateAfterEndDate
-	}
-
-	err = s.repo.Update(ctx, id, name, startDateParsed, endDateParsed)
-	if err != nil {
-		// No envolvemos ErrNotFound, lo propagamos directamente
-		var notFoundErr *ErrNotFound
-		if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
-			return err
-		}
-		return fmt.Errorf("%w: %v", ErrFailedToUpdateCourse, err)
-	}
-	return nil
-}
-
-func (s service) Count(ctx context.Context, filters Filters) (int64, error) {
-	count, err := s.repo.Count(ctx, filters)
-	if err != nil {
-		return 0, fmt.Errorf("%w: %v", ErrFailedToCountCourses, err)
-	}
-	return count, nil
-}
+package course
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/NicoJCastro/gocourse_course/pkg/user"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/google/uuid"
+)
+
+type (
+	Filters struct {
+		Name    string
+		Deleted DeletedScope
+		// Tags filtra por nombre de tag; TagMatch decide si deben estar todos o alguno
+		Tags     []string
+		TagMatch TagMatch
+		// Category incluye los cursos de sus subcategorías
+		Category string
+	}
+
+	// DeletedScope controla si el listado incluye cursos en la papelera (soft delete)
+	DeletedScope string
+
+	Service interface {
+		// Las fechas aceptan RFC 3339 o "2006-01-02" (medianoche en timezone, UTC si está vacío).
+		// instructorIDs asigna instructores si no tienen otro curso en esas fechas.
+		Create(ctx context.Context, name, startDate, endDate, timezone string, class Classification, instructorIDs []string) (*Course, error)
+		Get(ctx context.Context, id string) (*Course, error)
+		GetAll(ctx context.Context, filters Filters, offset, limit int) ([]Course, error)
+		Delete(ctx context.Context, id string) error
+		Update(ctx context.Context, id string, name *string, startDate *string, endDate *string, timezone *string, class Classification) error
+		Replace(ctx context.Context, id, name, startDate, endDate, timezone string, upsert bool) (*Course, bool, error)
+		Patch(ctx context.Context, id string, patchType PatchType, patch []byte) (*Course, error)
+		Count(ctx context.Context, filters Filters) (int64, error)
+		CreateBatch(ctx context.Context, items []BatchCreateItem, mode BatchMode) ([]BatchResult, error)
+		UpdateBatch(ctx context.Context, items []BatchUpdateItem, mode BatchMode) ([]BatchResult, error)
+		DeleteBatch(ctx context.Context, ids []string, mode BatchMode) ([]BatchResult, error)
+		Import(ctx context.Context, rows []ImportRow, dryRun bool) (*ImportReport, error)
+		Export(ctx context.Context, filters Filters, fn func(course TenantCourse) error) error
+		Restore(ctx context.Context, id string) (*Course, error)
+		Purge(ctx context.Context, id string) error
+		PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+		History(ctx context.Context, id string, offset, limit int) ([]audit.Entry, error)
+		CountHistory(ctx context.Context, id string) (int64, error)
+		Changes(ctx context.Context, lastEventID string, filters Filters) (*ChangeFeed, error)
+		Search(ctx context.Context, q string, filters Filters, offset, limit int) ([]SearchHit, int64, error)
+		Suggest(ctx context.Context, prefix string, limit int) ([]string, error)
+		AddPrerequisite(ctx context.Context, id, prerequisiteID string) (*PrerequisiteNode, error)
+		RemovePrerequisite(ctx context.Context, id, prerequisiteID string) error
+		Prerequisites(ctx context.Context, id string) (*PrerequisiteNode, error)
+		AssignInstructor(ctx context.Context, id, userID string) ([]Instructor, error)
+		RemoveInstructor(ctx context.Context, id, userID string) error
+		Instructors(ctx context.Context, id string) ([]Instructor, error)
+		SetTranslation(ctx context.Context, id, locale, name, description string) (*Translation, error)
+		RemoveTranslation(ctx context.Context, id, locale string) error
+		Translations(ctx context.Context, id string) ([]Translation, error)
+	}
+
+	// courseChange describe una mutación para la auditoría y los eventos de dominio
+	courseChange struct {
+		action audit.Action
+		id     string
+		before *domain.Course
+		after  *domain.Course
+	}
+
+	// mutationFunc ejecuta una mutación con el repositorio de la transacción
+	mutationFunc func(txRepo Repository) (courseChange, error)
+
+	// PatchType identifica el formato del documento de PATCH
+	PatchType string
+
+	// patchDocument es la representación JSON del curso sobre la que se aplican los patches
+	patchDocument struct {
+		ID        string  `json:"id"`
+		Name      *string `json:"name"`
+		StartDate *string `json:"start_date"`
+		EndDate   *string `json:"end_date"`
+	}
+
+	service struct {
+		log      *log.Logger
+		repo     Repository
+		searcher Searcher
+		// prerequisitePolicy se aplica al borrar un curso que otros requieren
+		prerequisitePolicy PrerequisitePolicy
+		// users verifica instructores y creadores; nil desactiva la verificación
+		users user.Client
+		// locales son los idiomas en los que se aceptan traducciones
+		locales config.Locales
+	}
+)
+
+// AuditEntityCourse es el entity_type de los registros de auditoría de cursos
+const AuditEntityCourse = "course"
+
+const (
+	DeletedExclude DeletedScope = ""
+	DeletedInclude DeletedScope = "include"
+	DeletedOnly    DeletedScope = "only"
+)
+
+const (
+	MergePatch PatchType = "application/merge-patch+json"
+	JSONPatch  PatchType = "application/json-patch+json"
+)
+
+func NewService(log *log.Logger, repo Repository, searcher Searcher, prerequisitePolicy PrerequisitePolicy, users user.Client, locales config.Locales) Service {
+	return &service{
+		log:                log,
+		repo:               repo,
+		searcher:           searcher,
+		prerequisitePolicy: prerequisitePolicy,
+		users:              users,
+		locales:            locales,
+	}
+}
+
+func (s *service) Create(ctx context.Context, name, startDate, endDate, timezone string, class Classification, instructorIDs []string) (*Course, error) {
+	s.log.Println("---- Creating course ----")
+
+	timezone, loc, err := loadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+	course, err := s.buildCourse(name, startDate, endDate, loc)
+	if err != nil {
+		return nil, err
+	}
+	if instructorIDs, err = normalizeUserIDs(instructorIDs); err != nil {
+		return nil, err
+	}
+	if err := s.verifyCreator(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.verifyUsers(ctx, ErrUserNotFound, instructorIDs...); err != nil {
+		return nil, err
+	}
+
+	var described *Course
+	err = s.mutate(ctx, func(txRepo Repository) (courseChange, error) {
+		if err := txRepo.Create(ctx, course); err != nil {
+			s.log.Printf("Error creating course: %v\n", err)
+			return courseChange{}, fmt.Errorf("%w: %w", ErrFailedToCreateCourse, err)
+		}
+		if err := txRepo.SetTimezone(ctx, course.ID, timezone); err != nil {
+			return courseChange{}, fmt.Errorf("%w: %w", ErrFailedToCreateCourse, err)
+		}
+		txService := s.withRepo(txRepo)
+		if err := txService.classify(ctx, course.ID, class); err != nil {
+			return courseChange{}, err
+		}
+		if err := txService.assignInstructors(ctx, course.ID, instructorIDs, course.StartDate, course.EndDate); err != nil {
+			return courseChange{}, err
+		}
+		courses, err := txService.describe(ctx, *course)
+		if err != nil {
+			return courseChange{}, err
+		}
+		described = &courses[0]
+		return courseChange{action: audit.ActionCreate, after: course}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return described, nil
+}
+
+// buildCourse parsea y valida las fechas con las reglas de Create; las fechas quedan en loc
+func (s service) buildCourse(name, startDate, endDate string, loc *time.Location) (*domain.Course, error) {
+	startDateParsed, err := parseCourseDate(startDate, loc)
+	if err != nil {
+		s.log.Println("Error parsing start date:", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidStartDate, err)
+	}
+
+	endDateParsed, err := parseCourseDate(endDate, loc)
+	if err != nil {
+		s.log.Println("Error parsing end date:", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEndDate, err)
+	}
+
+	// 🔧 Validar que la fecha de inicio no sea después de la fecha de fin
+	if startDateParsed.After(endDateParsed) {
+		s.log.Println("Start date is after end date")
+		return nil, ErrStartDateAfterEndDate
+	}
+
+	return &domain.Course{
+		Name:      name,
+		StartDate: startDateParsed,
+		EndDate:   endDateParsed,
+	}, nil
+}
+
+func (s service) GetAll(ctx context.Context, filters Filters, offset, limit int) ([]Course, error) {
+	s.log.Println("---- Getting all courses ----")
+	courses, err := s.repo.GetAll(ctx, filters, offset, limit)
+	if err != nil {
+		s.log.Printf("Error getting courses: %v\n", err)
+		// No envolvemos ErrNotFound, lo propagamos directamente
+		var notFoundErr *ErrNotFound
+		if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetAllCourses, err)
+	}
+	return courses, nil
+}
+
+func (s service) Get(ctx context.Context, id string) (*Course, error) {
+	course, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	courses, err := s.describe(ctx, *course)
+	if err != nil {
+		return nil, err
+	}
+	return &courses[0], nil
+}
+
+func (s service) get(ctx context.Context, id string) (*domain.Course, error) {
+	course, err := s.repo.Get(ctx, id)
+	if err != nil {
+		s.log.Printf("Error getting course: %v\n", err)
+		// No envolvemos ErrNotFound, lo propagamos directamente
+		var notFoundErr *ErrNotFound
+		if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetCourse, err)
+	}
+	return course, nil
+}
+
+func (s service) Delete(ctx context.Context, id string) error {
+	s.log.Println("---- Deleting course ----")
+	return s.mutate(ctx, func(txRepo Repository) (courseChange, error) {
+		before, err := txRepo.Get(ctx, id)
+		if err == nil {
+			err = s.releasePrerequisite(ctx, txRepo, id)
+		}
+		if err == nil {
+			err = txRepo.Delete(ctx, id)
+		}
+		if err != nil {
+			// No envolvemos ErrNotFound, lo propagamos directamente
+			var notFoundErr *ErrNotFound
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) ||
+				errors.Is(err, ErrCourseIsPrerequisite) {
+				return courseChange{}, err
+			}
+			return courseChange{}, fmt.Errorf("%w: %w", ErrFailedToDeleteCourse, err)
+		}
+		return courseChange{action: audit.ActionDelete, before: before}, nil
+	})
+}
+
+// Restore recupera un curso de la papelera
+func (s service) Restore(ctx context.Context, id string) (*Course, error) {
+	s.log.Println("---- Restoring course ----")
+	var course *domain.Course
+	err := s.mutate(ctx, func(txRepo Repository) (courseChange, error) {
+		err := txRepo.Restore(ctx, id)
+		if err == nil {
+			course, err = txRepo.Get(ctx, id)
+		}
+		if err != nil {
+			var notFoundErr *ErrNotFound
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+				return courseChange{}, err
+			}
+			return courseChange{}, fmt.Errorf("%w: %w", ErrFailedToRestoreCourse, err)
+		}
+		return courseChange{action: audit.ActionRestore, after: course}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.describeOne(ctx, course)
+}
+
+// Purge elimina definitivamente un curso, esté o no en la papelera
+func (s service) Purge(ctx context.Context, id string) error {
+	s.log.Println("---- Purging course ----")
+	return s.mutate(ctx, func(txRepo Repository) (courseChange, error) {
+		// Si el curso no estaba en la papelera, before permite emitir CourseDeleted
+		before, err := txRepo.Get(ctx, id)
+		var notFoundErr *ErrNotFound
+		if err != nil && !errors.As(err, &notFoundErr) && !errors.Is(err, ErrNotFoundBase) {
+			return courseChange{}, fmt.Errorf("%w: %w", ErrFailedToPurgeCourse, err)
+		}
+		// Un curso en la papelera ya pasó por la política al borrarse
+		if before != nil {
+			if err := s.releasePrerequisite(ctx, txRepo, id); err != nil {
+				return courseChange{}, err
+			}
+		}
+
+		if err := txRepo.Purge(ctx, id); err != nil {
+			var notFoundErr *ErrNotFound
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+				return courseChange{}, err
+			}
+			return courseChange{}, fmt.Errorf("%w: %w", ErrFailedToPurgeCourse, err)
+		}
+		return courseChange{action: audit.ActionPurge, id: id, before: before}, nil
+	})
+}
+
+// PurgeDeletedBefore elimina definitivamente los cursos borrados antes de la fecha dada
+func (s service) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var purged map[string]string
+	err := s.repo.Transaction(ctx, func(txRepo Repository) error {
+		var err error
+		if purged, err = txRepo.PurgeDeletedBefore(ctx, before); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToPurgeCourse, err)
+		}
+
+		entries := make([]*audit.Entry, 0, len(purged))
+		// El job corre sin tenant: cada registro va al historial de la organización del curso
+		for id, tenantID := range purged {
+			entry, err := audit.NewEntry(tenant.WithTenant(ctx, tenantID), AuditEntityCourse, id, audit.ActionPurge, nil, nil)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrFailedToRecordAudit, err)
+			}
+			entries = append(entries, entry)
+		}
+		if err := txRepo.RecordAudit(ctx, entries...); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToRecordAudit, err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Printf("Error purging deleted courses: %v\n", err)
+		return 0, err
+	}
+	return int64(len(purged)), nil
+}
+
+func (s service) Update(ctx context.Context, id string, name *string, startDate *string, endDate *string, timezone *string, class Classification) error {
+	s.log.Println("---- Updating course ----")
+	return s.mutate(ctx, func(txRepo Repository) (courseChange, error) {
+		txService := s.withRepo(txRepo)
+		before, after, err := txService.update(ctx, id, name, startDate, endDate, timezone)
+		if err != nil {
+			return courseChange{}, err
+		}
+		if err := txService.classify(ctx, id, class); err != nil {
+			return courseChange{}, err
+		}
+		return courseChange{action: audit.ActionUpdate, before: before, after: after}, nil
+	})
+}
+
+// update aplica los cambios parciales y devuelve el curso antes y después. Si cambia
+// la zona horaria, las fechas no enviadas conservan su fecha y hora en la zona nueva.
+func (s service) update(ctx context.Context, id string, name *string, startDate *string, endDate *string, timezone *string) (*domain.Course, *domain.Course, error) {
+	var startDateParsed, endDateParsed *time.Time
+
+	course, err := s.get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	currentTimezone, loc, err := s.timezone(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	localize(course, currentTimezone)
+
+	// Usar las fechas existentes como valores por defecto si no se proporcionan nuevas
+	currentStartDate := course.StartDate
+	currentEndDate := course.EndDate
+
+	if timezone != nil {
+		newTimezone, newLoc, err := loadTimezone(*timezone)
+		if err != nil {
+			return nil, nil, err
+		}
+		if newTimezone != currentTimezone {
+			if err := s.repo.SetTimezone(ctx, id, newTimezone); err != nil {
+				return nil, nil, fmt.Errorf("%w: %w", ErrFailedToUpdateCourse, err)
+			}
+			currentStartDate = rezone(currentStartDate, newLoc)
+			currentEndDate = rezone(currentEndDate, newLoc)
+			startDateParsed, endDateParsed = &currentStartDate, &currentEndDate
+		}
+		loc = newLoc
+	}
+
+	if startDate != nil {
+		parsedDate, err := parseCourseDate(*startDate, loc)
+		if err != nil {
+			s.log.Printf("Error parsing start date: %v\n", err)
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidStartDate, err)
+		}
+		startDateParsed = &parsedDate
+		currentStartDate = parsedDate
+	}
+
+	if endDate != nil {
+		parsedDate, err := parseCourseDate(*endDate, loc)
+		if err != nil {
+			s.log.Printf("Error parsing end date: %v\n", err)
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidEndDate, err)
+		}
+		endDateParsed = &parsedDate
+		currentEndDate = parsedDate
+	}
+
+	if err := s.validateDateRange(currentStartDate, currentEndDate, endDate != nil); err != nil {
+		return nil, nil, err
+	}
+	if startDateParsed != nil || endDateParsed != nil {
+		if err := s.validateSessions(ctx, id, currentStartDate, currentEndDate); err != nil {
+			return nil, nil, err
+		}
+		if err := s.checkSchedule(ctx, id, nil, currentStartDate, currentEndDate); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Un cambio sólo de categoría o tags no toca la fila del curso
+	if name == nil && startDateParsed == nil && endDateParsed == nil {
+		return course, course, nil
+	}
+
+	err = s.repo.Update(ctx, id, name, startDateParsed, endDateParsed)
+	if err != nil {
+		// No envolvemos ErrNotFound, lo propagamos directamente
+		var notFoundErr *ErrNotFound
+		if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("%w: %w", ErrFailedToUpdateCourse, err)
+	}
+
+	after, err := s.get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return course, after, nil
+}
+
+// validateSessions rechaza un cambio de fechas que deje sesiones fuera del curso
+func (s service) validateSessions(ctx context.Context, id string, startDate, endDate time.Time) error {
+	sessions, err := s.repo.Sessions(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToUpdateCourse, err)
+	}
+	outside := 0
+	for _, session := range sessions {
+		if !session.Within(startDate, endDate) {
+			outside++
+		}
+	}
+	if outside > 0 {
+		return fmt.Errorf("%w: %d of %d sessions", ErrSessionsOutsideCourse, outside, len(sessions))
+	}
+	return nil
+}
+
+// validateDateRange aplica las reglas de fechas de Update sobre los valores resultantes
+func (s service) validateDateRange(startDate, endDate time.Time, endDateChanged bool) error {
+	// 🔧 Si se está actualizando endDate, validar que no sea antes del startDate
+	if endDateChanged && endDate.Before(startDate) {
+		s.log.Println("End date is before start date")
+		return ErrEndDateBeforeStartDate
+	}
+
+	// 🔧 Validar que la fecha de inicio no sea después de la fecha de fin (usando valores actuales)
+	// Esta validación se ejecuta si se actualiza startDate o si ambas fechas están presentes
+	if startDate.After(endDate) {
+		s.log.Println("Start date is after end date")
+		return ErrStartDateAfterEndDate
+	}
+	return nil
+}
+
+// Patch aplica un JSON Merge Patch (RFC 7386) o un JSON Patch (RFC 6902) sobre el
+// curso almacenado y luego ejecuta las mismas validaciones de fechas que Update.
+// Las fechas del documento se exponen con el formato "2006-01-02".
+func (s service) Patch(ctx context.Context, id string, patchType PatchType, patch []byte) (*Course, error) {
+	s.log.Println("---- Patching course ----")
+	var course *domain.Course
+	err := s.mutate(ctx, func(txRepo Repository) (courseChange, error) {
+		var before *domain.Course
+		var err error
+		before, course, err = s.withRepo(txRepo).patch(ctx, id, patchType, patch)
+		if err != nil {
+			return courseChange{}, err
+		}
+		return courseChange{action: audit.ActionUpdate, before: before, after: course}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.describeOne(ctx, course)
+}
+
+// patch aplica el documento y devuelve el curso antes y después
+func (s service) patch(ctx context.Context, id string, patchType PatchType, patch []byte) (*domain.Course, *domain.Course, error) {
+	course, err := s.get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	timezone, loc, err := s.timezone(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	localize(course, timezone)
+	before := *course
+
+	original := patchDocument{
+		ID:        course.ID,
+		Name:      &course.Name,
+		StartDate: stringPtr(course.StartDate.Format(dateLayout)),
+		EndDate:   stringPtr(course.EndDate.Format(dateLayout)),
+	}
+	doc, err := json.Marshal(original)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrFailedToUpdateCourse, err)
+	}
+
+	var patched []byte
+	switch patchType {
+	case MergePatch:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	case JSONPatch:
+		var ops jsonpatch.Patch
+		ops, err = jsonpatch.DecodePatch(patch)
+		if err == nil {
+			patched, err = ops.Apply(doc)
+		}
+	default:
+		return nil, nil, ErrUnsupportedPatchType
+	}
+	if err != nil {
+		s.log.Printf("Error applying patch: %v\n", err)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return nil, nil, fmt.Errorf("%w: %w", ErrPatchTestFailed, err)
+		}
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+
+	var result patchDocument
+	if err := json.Unmarshal(patched, &result); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+
+	if result.ID != course.ID {
+		return nil, nil, ErrIDImmutable
+	}
+	// 🔧 Un campo eliminado (null en merge patch o "remove" en JSON Patch) no es válido
+	if result.Name == nil || *result.Name == "" {
+		return nil, nil, ErrNameRequired
+	}
+	if result.StartDate == nil || result.EndDate == nil {
+		return nil, nil, ErrStartDateAndEndDateRequired
+	}
+
+	// Una fecha que el patch no tocó conserva su hora, que el documento no expone
+	startDateParsed, endDateParsed := course.StartDate, course.EndDate
+	if *result.StartDate != *original.StartDate {
+		if startDateParsed, err = parseCourseDate(*result.StartDate, loc); err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidStartDate, err)
+		}
+	}
+	if *result.EndDate != *original.EndDate {
+		if endDateParsed, err = parseCourseDate(*result.EndDate, loc); err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidEndDate, err)
+		}
+	}
+
+	if err := s.validateDateRange(startDateParsed, endDateParsed, *result.EndDate != *original.EndDate); err != nil {
+		return nil, nil, err
+	}
+	if err := s.validateSessions(ctx, id, startDateParsed, endDateParsed); err != nil {
+		return nil, nil, err
+	}
+	if !startDateParsed.Equal(course.StartDate) || !endDateParsed.Equal(course.EndDate) {
+		if err := s.checkSchedule(ctx, id, nil, startDateParsed, endDateParsed); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	course.Name = *result.Name
+	course.StartDate = startDateParsed
+	course.EndDate = endDateParsed
+
+	if err := s.repo.Replace(ctx, course); err != nil {
+		var notFoundErr *ErrNotFound
+		if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("%w: %w", ErrFailedToUpdateCourse, err)
+	}
+	return &before, course, nil
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
+
+// Replace reemplaza el recurso completo (semántica PUT). A diferencia de Update,
+// todos los campos son obligatorios. Si upsert es true y el curso no existe,
+// se crea con el ID enviado por el cliente. El bool indica si fue creado.
+// timezone vacío conserva la zona horaria del curso (UTC si se crea).
+func (s service) Replace(ctx context.Context, id, name, startDate, endDate, timezone string, upsert bool) (*Course, bool, error) {
+	s.log.Println("---- Replacing course ----")
+	if upsert {
+		if err := s.verifyCreator(ctx); err != nil {
+			return nil, false, err
+		}
+	}
+
+	var course *domain.Course
+	created := false
+	err := s.mutate(ctx, func(txRepo Repository) (courseChange, error) {
+		// La transacción puede reintentarse completa ante un deadlock
+		created = false
+		txService := s.withRepo(txRepo)
+		currentTimezone, _, err := txService.timezone(ctx, id)
+		if err != nil {
+			return courseChange{}, err
+		}
+		if timezone == "" {
+			timezone = currentTimezone
+		}
+		_, loc, err := loadTimezone(timezone)
+		if err != nil {
+			return courseChange{}, err
+		}
+		if course, err = s.buildCourse(name, startDate, endDate, loc); err != nil {
+			return courseChange{}, err
+		}
+		course.ID = id
+
+		before, err := txRepo.Get(ctx, id)
+		if err != nil {
+			var notFoundErr *ErrNotFound
+			if !errors.As(err, &notFoundErr) && !errors.Is(err, ErrNotFoundBase) {
+				return courseChange{}, fmt.Errorf("%w: %w", ErrFailedToReplaceCourse, err)
+			}
+			if !upsert {
+				return courseChange{}, err
+			}
+
+			// 🔧 El ID lo define el cliente, así que validamos que sea un UUID
+			if _, err := uuid.Parse(id); err != nil {
+				return courseChange{}, ErrInvalidID
+			}
+			if err := txRepo.Create(ctx, course); err != nil {
+				s.log.Printf("Error creating course: %v\n", err)
+				return courseChange{}, fmt.Errorf("%w: %w", ErrFailedToCreateCourse, err)
+			}
+			if err := txRepo.SetTimezone(ctx, id, timezone); err != nil {
+				return courseChange{}, fmt.Errorf("%w: %w", ErrFailedToCreateCourse, err)
+			}
+			created = true
+			return courseChange{action: audit.ActionCreate, after: course}, nil
+		}
+
+		if err := txService.validateSessions(ctx, id, course.StartDate, course.EndDate); err != nil {
+			return courseChange{}, err
+		}
+		if !course.StartDate.Equal(before.StartDate) || !course.EndDate.Equal(before.EndDate) {
+			if err := txService.checkSchedule(ctx, id, nil, course.StartDate, course.EndDate); err != nil {
+				return courseChange{}, err
+			}
+		}
+		if err := txRepo.Replace(ctx, course); err != nil {
+			var notFoundErr *ErrNotFound
+			if errors.As(err, &notFoundErr) || errors.Is(err, ErrNotFoundBase) {
+				return courseChange{}, err
+			}
+			return courseChange{}, fmt.Errorf("%w: %w", ErrFailedToReplaceCourse, err)
+		}
+		if err := txRepo.SetTimezone(ctx, id, timezone); err != nil {
+			return courseChange{}, fmt.Errorf("%w: %w", ErrFailedToReplaceCourse, err)
+		}
+		return courseChange{action: audit.ActionUpdate, before: before, after: course}, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	described, err := s.describeOne(ctx, course)
+	if err != nil {
+		return nil, false, err
+	}
+	return described, created, nil
+}
+
+// History devuelve los registros de auditoría del curso, del más reciente al más antiguo
+func (s service) History(ctx context.Context, id string, offset, limit int) ([]audit.Entry, error) {
+	entries, err := s.repo.History(ctx, id, offset, limit)
+	if err != nil {
+		s.log.Printf("Error getting course history: %v\n", err)
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetHistory, err)
+	}
+	return entries, nil
+}
+
+func (s service) CountHistory(ctx context.Context, id string) (int64, error) {
+	count, err := s.repo.CountHistory(ctx, id)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToGetHistory, err)
+	}
+	return count, nil
+}
+
+// mutate ejecuta la mutación en una transacción y en esa misma transacción escribe
+// el registro de auditoría y los eventos del outbox: si falla uno, se revierte todo
+func (s service) mutate(ctx context.Context, fn mutationFunc) error {
+	return s.repo.Transaction(ctx, func(txRepo Repository) error {
+		change, err := fn(txRepo)
+		if err != nil {
+			return err
+		}
+
+		id := change.id
+		switch {
+		case id != "":
+		case change.after != nil:
+			id = change.after.ID
+		case change.before != nil:
+			id = change.before.ID
+		}
+
+		entry, err := audit.NewEntry(ctx, AuditEntityCourse, id, change.action, change.before, change.after)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToRecordAudit, err)
+		}
+		if err := txRepo.RecordAudit(ctx, entry); err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToRecordAudit, err)
+		}
+
+		events, err := courseEvents(ctx, change)
+		if err == nil {
+			err = txRepo.RecordEvents(ctx, events...)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToRecordEvents, err)
+		}
+		return nil
+	})
+}
+
+// withRepo devuelve una copia del service que usa otro repositorio (ej: uno transaccional)
+func (s service) withRepo(repo Repository) service {
+	s.repo = repo
+	return s
+}
+
+func (s service) Count(ctx context.Context, filters Filters) (int64, error) {
+	count, err := s.repo.Count(ctx, filters)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToCountCourses, err)
+	}
+	return count, nil
+}
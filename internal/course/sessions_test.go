@@ -0,0 +1,54 @@
+package course_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/session"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+)
+
+func TestSessionsOutsideCourse(t *testing.T) {
+	svc, _, db := newTenantService(t)
+	ctx, _ := tenanttest.Contexts()
+	created, err := svc.Create(ctx, "Go basics", "2024-06-01", "2024-06-30", "", course.Classification{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := session.NewService(tenanttest.Logger(), session.NewRepo(db, tenanttest.Logger()))
+	if _, err := sessions.Create(ctx, created.ID, session.Input{Start: "2024-06-03T10:00", Duration: 90, RRule: "FREQ=WEEKLY;COUNT=3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Las sesiones son el 3, 10 y 17 de junio
+	tests := []struct {
+		name      string
+		startDate string
+		endDate   string
+		err       error
+	}{
+		{"end before the last session", "2024-06-01", "2024-06-15", course.ErrSessionsOutsideCourse},
+		{"start after the first session", "2024-06-05", "2024-06-30", course.ErrSessionsOutsideCourse},
+		{"keeps them inside", "2024-06-03", "2024-06-17", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.Update(ctx, created.ID, nil, &tt.startDate, &tt.endDate, nil, course.Classification{})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Update = %v, want %v", err, tt.err)
+			}
+			if _, _, err := svc.Replace(ctx, created.ID, "Go basics", tt.startDate, tt.endDate, "", false); !errors.Is(err, tt.err) {
+				t.Errorf("Replace = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	got, err := svc.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.StartDate.Format("2006-01-02") != "2024-06-03" || got.EndDate.Format("2006-01-02") != "2024-06-17" {
+		t.Errorf("dates = %s..%s, want only the valid change applied", got.StartDate, got.EndDate)
+	}
+}
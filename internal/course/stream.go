@@ -0,0 +1,194 @@
+package course
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+// El stream de cambios usa la tabla de auditoría como log de eventos: se escribe en la
+// misma transacción que la mutación, así que sólo ve cambios confirmados y funciona con
+// varias instancias del servicio. El ID del evento SSE es el ID del registro de auditoría;
+// el feed avanza por su Seq, que sigue el orden de commit, así que una transacción lenta
+// no queda detrás del cursor.
+
+type (
+	// ChangeEvent es un cambio de curso tal como se envía por SSE
+	ChangeEvent struct {
+		ID         string         `json:"id"`
+		TenantID   string         `json:"-"`
+		Type       string         `json:"type"`
+		CourseID   string         `json:"course_id"`
+		Course     *domain.Course `json:"course"`
+		Actor      string         `json:"actor"`
+		OccurredAt time.Time      `json:"occurred_at"`
+	}
+
+	// ChangeFeed recorre el log de cambios desde un cursor; no es seguro para uso concurrente
+	ChangeFeed struct {
+		log     *log.Logger
+		repo    Repository
+		filters Filters
+		// cursor es el Seq del último registro leído
+		cursor int64
+	}
+)
+
+const (
+	// ChangeFeedWindow es lo más atrás que se puede reanudar con Last-Event-ID
+	ChangeFeedWindow = 24 * time.Hour
+	changeFeedLimit  = 500
+)
+
+// Changes abre un feed de cambios. Con lastEventID vacío empieza en el momento actual;
+// si el ID no existe o quedó fuera de ChangeFeedWindow se reanuda desde el inicio de la ventana.
+// Tags y categoría no están en el log de cambios, así que esos filtros se rechazan.
+func (s service) Changes(ctx context.Context, lastEventID string, filters Filters) (*ChangeFeed, error) {
+	if len(filters.Tags) > 0 || filters.Category != "" {
+		return nil, ErrChangeFilterUnsupported
+	}
+	feed := &ChangeFeed{
+		log:     s.log,
+		repo:    s.repo,
+		filters: filters,
+	}
+
+	var entry *audit.Entry
+	if lastEventID != "" {
+		var err error
+		if entry, err = s.repo.HistoryEntry(ctx, lastEventID); err != nil {
+			s.log.Println(err)
+			return nil, fmt.Errorf("%w: %w", ErrFailedToGetHistory, err)
+		}
+	}
+
+	var err error
+	oldest := time.Now().Add(-ChangeFeedWindow)
+	switch {
+	case lastEventID == "":
+		feed.cursor, err = s.repo.LastHistorySeq(ctx)
+	case entry == nil || entry.CreatedAt.Before(oldest):
+		feed.cursor, err = s.repo.HistorySeqAt(ctx, oldest)
+	default:
+		feed.cursor = entry.Seq
+	}
+	if err != nil {
+		s.log.Println(err)
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetHistory, err)
+	}
+	return feed, nil
+}
+
+// Next devuelve los cambios nuevos desde la última llamada que cumplen los filtros
+func (f *ChangeFeed) Next(ctx context.Context) ([]ChangeEvent, error) {
+	entries, err := f.repo.HistoryAfter(ctx, f.cursor, changeFeedLimit)
+	if err != nil {
+		f.log.Println(err)
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetHistory, err)
+	}
+
+	// El nombre también se busca en las traducciones, como en applyFilters
+	translations := map[string][]Translation{}
+	if f.filters.Name != "" && len(entries) > 0 {
+		ids := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.EntityID)
+		}
+		if translations, err = f.repo.Translations(ctx, ids); err != nil {
+			f.log.Println(err)
+			return nil, fmt.Errorf("%w: %w", ErrFailedToGetHistory, err)
+		}
+	}
+
+	var events []ChangeEvent
+	for _, entry := range entries {
+		event, err := newChangeEvent(entry)
+		if err != nil {
+			return nil, err
+		}
+		f.cursor = entry.Seq
+		if f.filters.Match(event.Course, entry.Action, translations[entry.EntityID]) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// Match aplica en memoria la misma semántica que applyFilters: Name es un "contiene" sin
+// distinguir mayúsculas sobre el nombre o sus traducciones actuales (las de un curso purgado
+// ya no existen) y Deleted decide si se incluyen las bajas (delete y purge). Tags y
+// categoría no se evalúan: Changes los rechaza.
+func (f Filters) Match(course *domain.Course, action audit.Action, translations []Translation) bool {
+	deleted := action == audit.ActionDelete || action == audit.ActionPurge
+	switch f.Deleted {
+	case DeletedExclude:
+		if deleted {
+			return false
+		}
+	case DeletedOnly:
+		if !deleted {
+			return false
+		}
+	}
+
+	if f.Name == "" {
+		return true
+	}
+	name := strings.ToLower(f.Name)
+	if course != nil && strings.Contains(strings.ToLower(course.Name), name) {
+		return true
+	}
+	for _, translation := range translations {
+		if strings.Contains(strings.ToLower(translation.Name), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func newChangeEvent(entry audit.Entry) (ChangeEvent, error) {
+	event := ChangeEvent{
+		ID:         entry.ID,
+		TenantID:   entry.TenantID,
+		Type:       changeEventType(entry.Action),
+		CourseID:   entry.EntityID,
+		Actor:      entry.Actor,
+		OccurredAt: entry.CreatedAt,
+	}
+
+	// En las bajas se envía el último estado conocido del curso
+	snapshot := entry.After
+	if len(snapshot) == 0 || string(snapshot) == "null" {
+		snapshot = entry.Before
+	}
+	if len(snapshot) > 0 && string(snapshot) != "null" {
+		var course domain.Course
+		if err := json.Unmarshal(snapshot, &course); err != nil {
+			return ChangeEvent{}, fmt.Errorf("%w: %w", ErrFailedToGetHistory, err)
+		}
+		event.Course = &course
+	}
+	return event, nil
+}
+
+func changeEventType(action audit.Action) string {
+	switch action {
+	case audit.ActionCreate:
+		return "course.created"
+	case audit.ActionUpdate:
+		return "course.updated"
+	case audit.ActionDelete:
+		return "course.deleted"
+	case audit.ActionRestore:
+		return "course.restored"
+	case audit.ActionPurge:
+		return "course.purged"
+	}
+	return "course." + string(action)
+}
@@ -0,0 +1,114 @@
+package course_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+)
+
+func eventTypes(t *testing.T, ctx context.Context, feed *course.ChangeFeed) []string {
+	t.Helper()
+	events, err := feed.Next(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := []string{}
+	for _, event := range events {
+		types = append(types, event.Type+" "+event.Course.Name)
+	}
+	return types
+}
+
+func TestChanges(t *testing.T) {
+	svc, _, db := newTenantService(t)
+	ctxA, ctxB := tenanttest.Contexts()
+	create := func(ctx context.Context, name string) *course.Course {
+		t.Helper()
+		created, err := svc.Create(ctx, name, "2024-06-01", "2024-06-30", "", course.Classification{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return created
+	}
+	create(ctxA, "Before the feed")
+
+	all := course.Filters{Deleted: course.DeletedInclude}
+	feed, err := svc.Changes(ctxA, "", all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := create(ctxA, "Go basics")
+	create(ctxB, "Other tenant")
+	if err := svc.Delete(ctxA, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := eventTypes(t, ctxA, feed); len(got) != 2 || got[0] != "course.created Go basics" || got[1] != "course.deleted Go basics" {
+		t.Errorf("events = %v, want the create and delete of Go basics", got)
+	}
+
+	t.Run("a late commit with an older created_at", func(t *testing.T) {
+		// Una transacción que arrancó hace un minuto y confirma ahora: su created_at
+		// queda detrás de lo ya entregado, pero su Seq no
+		after, _ := json.Marshal(map[string]string{"id": first.ID, "name": "Slow import"})
+		entry, err := audit.NewEntry(ctxA, course.AuditEntityCourse, first.ID, audit.ActionUpdate, nil, json.RawMessage(after))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.CreatedAt = time.Now().UTC().Add(-time.Minute)
+		if err := audit.NewRepo(db, tenanttest.Logger()).Create(ctxA, entry); err != nil {
+			t.Fatal(err)
+		}
+		if got := eventTypes(t, ctxA, feed); len(got) != 1 || got[0] != "course.updated Slow import" {
+			t.Errorf("events = %v, want the late update", got)
+		}
+	})
+
+	t.Run("resume after the last event ID", func(t *testing.T) {
+		history, err := svc.History(ctxA, first.ID, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var created audit.Entry
+		for _, entry := range history {
+			if entry.Action == audit.ActionCreate {
+				created = entry
+			}
+		}
+		resumed, err := svc.Changes(ctxA, created.ID, all)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := eventTypes(t, ctxA, resumed); len(got) != 2 || got[0] != "course.deleted Go basics" {
+			t.Errorf("events = %v, want the delete and the late update", got)
+		}
+	})
+
+	t.Run("name matches translations", func(t *testing.T) {
+		feed, err := svc.Changes(ctxA, "", course.Filters{Name: "básico", Deleted: course.DeletedExclude})
+		if err != nil {
+			t.Fatal(err)
+		}
+		translated := create(ctxA, "Go for teams")
+		create(ctxA, "Rust")
+		if _, err := svc.SetTranslation(ctxA, translated.ID, "es", "Go básico para equipos", ""); err != nil {
+			t.Fatal(err)
+		}
+		if got := eventTypes(t, ctxA, feed); len(got) != 1 || got[0] != "course.created Go for teams" {
+			t.Errorf("events = %v, want the course with a matching translation", got)
+		}
+	})
+
+	t.Run("tag and category filters", func(t *testing.T) {
+		for _, filters := range []course.Filters{{Tags: []string{"backend"}}, {Category: "programacion"}} {
+			if _, err := svc.Changes(ctxA, "", filters); !errors.Is(err, course.ErrChangeFilterUnsupported) {
+				t.Errorf("Changes(%+v) = %v, want %v", filters, err, course.ErrChangeFilterUnsupported)
+			}
+		}
+	})
+}
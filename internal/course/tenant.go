@@ -0,0 +1,26 @@
+package course
+
+import (
+	"context"
+
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+// TenantCourse es la fila de courses con la organización dueña del curso. El resto
+// del servicio usa domain.Course: el filtro por tenant lo agrega el callback de
+// tenant.Register, así que sólo el alta necesita este modelo.
+type TenantCourse struct {
+	domain.Course
+	TenantID string `json:"-" gorm:"type:varchar(64);not null;default:'';index"`
+}
+
+func (TenantCourse) TableName() string {
+	return "courses"
+}
+
+// newTenantCourse asigna el curso al tenant del contexto (el por defecto si no hay)
+func newTenantCourse(ctx context.Context, course domain.Course) *TenantCourse {
+	tenantID, _ := tenant.FromContext(ctx)
+	return &TenantCourse{Course: course, TenantID: tenantID}
+}
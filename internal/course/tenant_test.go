@@ -0,0 +1,223 @@
+package course_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/catalog"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/outbox"
+	"github.com/NicoJCastro/gocourse_course/internal/session"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"gorm.io/gorm"
+)
+
+func newTenantService(t *testing.T) (course.Service, course.Repository, *gorm.DB) {
+	t.Helper()
+	db := tenanttest.NewDB(t,
+		&course.TenantCourse{}, &audit.Entry{}, &audit.Sequence{}, &outbox.Message{},
+		&catalog.Tag{}, &catalog.Category{}, &catalog.CourseTag{}, &catalog.CourseCategory{},
+		&course.Prerequisite{}, &course.PrerequisiteLock{}, &course.CourseTimezone{}, &course.Instructor{}, &course.Translation{},
+		&session.Session{},
+	)
+	repo := course.NewRepo(db, tenanttest.Logger())
+	locales := config.Locales{Default: "en", Supported: []string{"en", "es"}}
+	return course.NewService(tenanttest.Logger(), repo, nil, course.PrerequisiteRestrict, nil, locales), repo, db
+}
+
+func TestTenantIsolation(t *testing.T) {
+	svc, repo, db := newTenantService(t)
+	ctxA, ctxB := tenanttest.Contexts()
+
+	// tenant A: un curso con zona horaria, traducción, instructor y un prerequisito
+	base, err := svc.Create(ctxA, "Go basics", "2024-06-01", "2024-06-30", "America/Argentina/Buenos_Aires", course.Classification{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	advanced, err := svc.Create(ctxA, "Go advanced", "2024-07-01", "2024-07-31", "", course.Classification{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AddPrerequisite(ctxA, advanced.ID, base.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetTranslation(ctxA, base.ID, "es", "Go básico", ""); err != nil {
+		t.Fatal(err)
+	}
+	userID := "3f2c1d4e-5b6a-4c7d-8e9f-0a1b2c3d4e5f"
+	if _, err := svc.AssignInstructor(ctxA, base.ID, userID); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("rows are stamped with the tenant", func(t *testing.T) {
+		tenanttest.AssertStamped(t, db, "courses", "audit_entries", "outbox_messages", "course_timezones",
+			"course_translations", "course_instructors", "course_prerequisites")
+	})
+
+	t.Run("courses", func(t *testing.T) {
+		_, err := svc.Get(ctxB, base.ID)
+		tenanttest.AssertNotFound(t, "Get", err, course.ErrNotFoundBase)
+
+		courses, err := svc.GetAll(ctxB, course.Filters{}, 0, 10)
+		if err != nil || len(courses) != 0 {
+			t.Errorf("GetAll = %d courses, %v; want none", len(courses), err)
+		}
+		count, err := svc.Count(ctxB, course.Filters{Deleted: course.DeletedInclude})
+		if err != nil || count != 0 {
+			t.Errorf("Count = %d, %v; want 0", count, err)
+		}
+
+		name := "Hijacked"
+		tenanttest.AssertNotFound(t, "Update", svc.Update(ctxB, base.ID, &name, nil, nil, nil, course.Classification{}), course.ErrNotFoundBase)
+		_, err = svc.Patch(ctxB, base.ID, course.MergePatch, []byte(`{"name":"Hijacked"}`))
+		tenanttest.AssertNotFound(t, "Patch", err, course.ErrNotFoundBase)
+		_, _, err = svc.Replace(ctxB, base.ID, "Hijacked", "2024-06-01", "2024-06-30", "", false)
+		tenanttest.AssertNotFound(t, "Replace", err, course.ErrNotFoundBase)
+		tenanttest.AssertNotFound(t, "Delete", svc.Delete(ctxB, base.ID), course.ErrNotFoundBase)
+
+		history, err := svc.History(ctxB, base.ID, 0, 10)
+		if err != nil || len(history) != 0 {
+			t.Errorf("History = %d entries, %v; want none", len(history), err)
+		}
+		if count, err := svc.CountHistory(ctxB, base.ID); err != nil || count != 0 {
+			t.Errorf("CountHistory = %d, %v; want 0", count, err)
+		}
+
+		got, err := svc.Get(ctxA, base.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Name != "Go basics" {
+			t.Errorf("name = %q after writes from another tenant", got.Name)
+		}
+	})
+
+	t.Run("trash", func(t *testing.T) {
+		if err := svc.Delete(ctxA, advanced.ID); err != nil {
+			t.Fatal(err)
+		}
+		_, err := svc.Restore(ctxB, advanced.ID)
+		tenanttest.AssertNotFound(t, "Restore", err, course.ErrNotFoundBase)
+		tenanttest.AssertNotFound(t, "Purge", svc.Purge(ctxB, advanced.ID), course.ErrNotFoundBase)
+
+		deleted, err := svc.GetAll(ctxA, course.Filters{Deleted: course.DeletedOnly}, 0, 10)
+		if err != nil || len(deleted) != 1 {
+			t.Fatalf("trash of tenant A = %d courses, %v; want 1", len(deleted), err)
+		}
+		if _, err := svc.Restore(ctxA, advanced.ID); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("translations", func(t *testing.T) {
+		_, err := svc.Translations(ctxB, base.ID)
+		tenanttest.AssertNotFound(t, "Translations", err, course.ErrNotFoundBase)
+		_, err = svc.SetTranslation(ctxB, base.ID, "es", "Otro", "")
+		tenanttest.AssertNotFound(t, "SetTranslation", err, course.ErrNotFoundBase)
+		tenanttest.AssertNotFound(t, "RemoveTranslation", svc.RemoveTranslation(ctxB, base.ID, "es"), course.ErrNotFoundBase)
+
+		// también sin pasar por el curso: la tabla se filtra sola
+		translations, err := repo.Translations(ctxB, []string{base.ID})
+		if err != nil || len(translations[base.ID]) != 0 {
+			t.Errorf("repo.Translations = %v, %v; want none", translations, err)
+		}
+	})
+
+	t.Run("instructors", func(t *testing.T) {
+		_, err := svc.Instructors(ctxB, base.ID)
+		tenanttest.AssertNotFound(t, "Instructors", err, course.ErrNotFoundBase)
+		tenanttest.AssertNotFound(t, "RemoveInstructor", svc.RemoveInstructor(ctxB, base.ID, userID), course.ErrNotFoundBase)
+
+		instructors, err := repo.Instructors(ctxB, base.ID)
+		if err != nil || len(instructors) != 0 {
+			t.Errorf("repo.Instructors = %v, %v; want none", instructors, err)
+		}
+		if err := repo.RemoveInstructor(ctxB, base.ID, userID); !errors.Is(err, course.ErrInstructorNotFound) {
+			t.Errorf("repo.RemoveInstructor = %v, want %v", err, course.ErrInstructorNotFound)
+		}
+	})
+
+	t.Run("prerequisites", func(t *testing.T) {
+		_, err := svc.Prerequisites(ctxB, advanced.ID)
+		tenanttest.AssertNotFound(t, "Prerequisites", err, course.ErrNotFoundBase)
+		_, err = svc.AddPrerequisite(ctxB, advanced.ID, base.ID)
+		tenanttest.AssertNotFound(t, "AddPrerequisite", err, course.ErrNotFoundBase)
+
+		prerequisites, err := repo.Prerequisites(ctxB, []string{advanced.ID})
+		if err != nil || len(prerequisites[advanced.ID]) != 0 {
+			t.Errorf("repo.Prerequisites = %v, %v; want none", prerequisites, err)
+		}
+		if err := repo.RemovePrerequisite(ctxB, advanced.ID, base.ID); !errors.Is(err, course.ErrPrerequisiteNotFound) {
+			t.Errorf("repo.RemovePrerequisite = %v, want %v", err, course.ErrPrerequisiteNotFound)
+		}
+	})
+
+	t.Run("timezones", func(t *testing.T) {
+		timezones, err := repo.Timezones(ctxB, []string{base.ID})
+		if err != nil || len(timezones) != 0 {
+			t.Errorf("repo.Timezones = %v, %v; want none", timezones, err)
+		}
+	})
+
+	// Un vínculo inconsistente (curso de B en una categoría o con un tag de A) no tiene que
+	// hacer que los filtros de B resuelvan la categoría o el tag de A
+	t.Run("tag and category filters", func(t *testing.T) {
+		catalogSvc := catalog.NewService(tenanttest.Logger(), catalog.NewRepo(db, tenanttest.Logger()))
+		category, err := catalogSvc.CreateCategory(ctxA, "Programming", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		child, err := catalogSvc.CreateCategory(ctxA, "Backend", &category.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tag, err := catalogSvc.CreateTag(ctxA, "golang")
+		if err != nil {
+			t.Fatal(err)
+		}
+		other, err := svc.Create(ctxB, "Rust basics", "2024-06-01", "2024-06-30", "", course.Classification{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		links := []interface{}{
+			&catalog.CourseCategory{CourseID: other.ID, CategoryID: child.ID, TenantID: tenanttest.B},
+			&catalog.CourseTag{CourseID: other.ID, TagID: tag.ID, TenantID: tenanttest.B},
+		}
+		for _, link := range links {
+			if err := db.Create(link).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for name, filters := range map[string]course.Filters{
+			"category": {Category: category.ID},
+			"tag":      {Tags: []string{"golang"}, TagMatch: course.TagMatchAny},
+		} {
+			courses, err := svc.GetAll(ctxB, filters, 0, 10)
+			if err != nil || len(courses) != 0 {
+				t.Errorf("GetAll by %s of another tenant = %d courses, %v; want none", name, len(courses), err)
+			}
+		}
+	})
+
+	// Al final, tenant A sigue viendo todo lo suyo
+	t.Run("owner still sees its data", func(t *testing.T) {
+		translations, err := svc.Translations(ctxA, base.ID)
+		if err != nil || len(translations) != 1 {
+			t.Errorf("Translations = %v, %v; want 1", translations, err)
+		}
+		instructors, err := svc.Instructors(ctxA, base.ID)
+		if err != nil || len(instructors) != 1 {
+			t.Errorf("Instructors = %v, %v; want 1", instructors, err)
+		}
+		node, err := svc.Prerequisites(ctxA, advanced.ID)
+		if err != nil || len(node.Prerequisites) != 1 {
+			t.Errorf("Prerequisites = %+v, %v; want 1", node, err)
+		}
+		if count, err := svc.CountHistory(ctxA, base.ID); err != nil || count == 0 {
+			t.Errorf("CountHistory = %d, %v; want entries", count, err)
+		}
+	})
+}
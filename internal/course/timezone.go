@@ -0,0 +1,91 @@
+package course
+
+import (
+	"context"
+	"fmt"
+	"time"
+	_ "time/tzdata" // las zonas horarias no dependen de la imagen del contenedor
+
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+// CourseTimezone guarda la zona horaria IANA del curso; un curso sin fila está en UTC
+type CourseTimezone struct {
+	CourseID string `gorm:"type:char(36);not null;primaryKey"`
+	Timezone string `gorm:"type:varchar(64);not null"`
+	TenantID string `gorm:"type:varchar(64);not null;default:'';index"`
+}
+
+const (
+	DefaultTimezone = "UTC"
+	dateLayout      = "2006-01-02"
+)
+
+func (CourseTimezone) TableName() string {
+	return "course_timezones"
+}
+
+// loadTimezone valida un nombre IANA; vacío es DefaultTimezone
+func loadTimezone(name string) (string, *time.Location, error) {
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, name)
+	}
+	return name, loc, nil
+}
+
+// parseCourseDate acepta RFC 3339 o una fecha "2006-01-02", que se toma como
+// la medianoche de loc. El resultado queda expresado en loc.
+func parseCourseDate(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), nil
+	}
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return date, err
+	}
+	year, month, day := date.Date()
+	return skipGap(time.Date(year, month, day, 0, 0, 0, 0, loc), year, month, day), nil
+}
+
+// rezone conserva la fecha y hora de t (en su zona actual) pero en loc: al
+// cambiar la zona horaria del curso, "10 de marzo" sigue siendo "10 de marzo"
+func rezone(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	return skipGap(time.Date(year, month, day, hour, min, sec, t.Nanosecond(), loc), year, month, day)
+}
+
+// skipGap corrige una hora que no existe por el cambio de horario a las 00:00 (ej:
+// America/Santiago): Go puede resolverla con el offset de antes del salto y caer el día
+// anterior. El curso tiene que empezar el día pedido, en el primer instante que existe.
+func skipGap(t time.Time, year int, month time.Month, day int) time.Time {
+	if y, m, d := t.Date(); y == year && m == month && d == day {
+		return t
+	}
+	_, before := t.Zone()
+	_, after := t.Add(24 * time.Hour).Zone()
+	return t.Add(time.Duration(after-before) * time.Second)
+}
+
+// localize expresa las fechas del curso en su zona horaria
+func localize(course *domain.Course, timezone string) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	course.StartDate = course.StartDate.In(loc)
+	course.EndDate = course.EndDate.In(loc)
+}
+
+// timezone devuelve la zona horaria del curso id
+func (s service) timezone(ctx context.Context, id string) (string, *time.Location, error) {
+	timezones, err := s.repo.Timezones(ctx, []string{id})
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrFailedToGetCourse, err)
+	}
+	return loadTimezone(timezones[id])
+}
@@ -0,0 +1,192 @@
+package course
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestParseCourseDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		timezone string
+		// utc es el instante esperado; local la fecha y hora de pared en la zona del curso
+		utc   string
+		local string
+	}{
+		{
+			// El salto de 02:00 a 03:00 no toca la medianoche: el 10/3 arranca en EST
+			name: "spring forward date only", value: "2024-03-10", timezone: "America/New_York",
+			utc: "2024-03-10T05:00:00Z", local: "2024-03-10T00:00:00-05:00",
+		},
+		{
+			// 07:30Z cae después del salto: la hora de pared es 03:30 EDT, no 02:30
+			name: "spring forward instant after the gap", value: "2024-03-10T07:30:00Z", timezone: "America/New_York",
+			utc: "2024-03-10T07:30:00Z", local: "2024-03-10T03:30:00-04:00",
+		},
+		{
+			// En Santiago el cambio es a las 00:00: la medianoche del 8/9 no existe y se corre a la 01:00
+			name: "spring forward gap at midnight", value: "2024-09-08", timezone: "America/Santiago",
+			utc: "2024-09-08T04:00:00Z", local: "2024-09-08T01:00:00-03:00",
+		},
+		{
+			name: "fall back date only", value: "2024-11-03", timezone: "America/New_York",
+			utc: "2024-11-03T04:00:00Z", local: "2024-11-03T00:00:00-04:00",
+		},
+		{
+			// 01:30 ocurre dos veces el 3/11: el offset explícito elige la primera (EDT)...
+			name: "fall back overlap first occurrence", value: "2024-11-03T01:30:00-04:00", timezone: "America/New_York",
+			utc: "2024-11-03T05:30:00Z", local: "2024-11-03T01:30:00-04:00",
+		},
+		{
+			// ...o la segunda (EST), una hora más tarde
+			name: "fall back overlap second occurrence", value: "2024-11-03T01:30:00-05:00", timezone: "America/New_York",
+			utc: "2024-11-03T06:30:00Z", local: "2024-11-03T01:30:00-05:00",
+		},
+		{
+			// El offset del valor manda sobre la zona del curso; el resultado queda en la zona del curso
+			name: "RFC 3339 with explicit offset", value: "2024-06-01T10:00:00+09:00", timezone: "America/Argentina/Buenos_Aires",
+			utc: "2024-06-01T01:00:00Z", local: "2024-05-31T22:00:00-03:00",
+		},
+		{
+			name: "date only in a non UTC zone", value: "2024-06-01", timezone: "Asia/Tokyo",
+			utc: "2024-05-31T15:00:00Z", local: "2024-06-01T00:00:00+09:00",
+		},
+		{
+			name: "date only in UTC", value: "2024-06-01", timezone: "UTC",
+			utc: "2024-06-01T00:00:00Z", local: "2024-06-01T00:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCourseDate(tt.value, mustLoad(t, tt.timezone))
+			if err != nil {
+				t.Fatalf("parseCourseDate(%q): %v", tt.value, err)
+			}
+			if utc := got.UTC().Format(time.RFC3339); utc != tt.utc {
+				t.Errorf("instant = %s, want %s", utc, tt.utc)
+			}
+			if local := got.Format(time.RFC3339); local != tt.local {
+				t.Errorf("local = %s, want %s", local, tt.local)
+			}
+		})
+	}
+}
+
+func TestParseCourseDateInvalid(t *testing.T) {
+	for _, value := range []string{"", "2024-13-01", "01/06/2024", "2024-06-01T10:00:00"} {
+		if _, err := parseCourseDate(value, time.UTC); err == nil {
+			t.Errorf("parseCourseDate(%q) should fail", value)
+		}
+	}
+}
+
+func TestRezone(t *testing.T) {
+	tests := []struct {
+		name string
+		// value está en la zona from; al pasar a to se conserva la fecha y hora de pared
+		value string
+		from  string
+		to    string
+		want  string
+	}{
+		{
+			name: "UTC to New York before DST", value: "2024-03-09T00:00:00Z", from: "UTC", to: "America/New_York",
+			want: "2024-03-09T00:00:00-05:00",
+		},
+		{
+			// El curso termina después del cambio de hora: el offset nuevo es el de esa fecha
+			name: "UTC to New York after DST", value: "2024-03-11T00:00:00Z", from: "UTC", to: "America/New_York",
+			want: "2024-03-11T00:00:00-04:00",
+		},
+		{
+			// Nueva York ya está en EDT y Madrid todavía no cambió (31/3)
+			name: "across different DST calendars", value: "2024-03-20T09:00:00-04:00", from: "America/New_York", to: "Europe/Madrid",
+			want: "2024-03-20T09:00:00+01:00",
+		},
+		{
+			name: "fall back date to UTC", value: "2024-11-03T00:00:00-04:00", from: "America/New_York", to: "UTC",
+			want: "2024-11-03T00:00:00Z",
+		},
+		{
+			// La medianoche del 8/9 no existe en Santiago: el curso sigue empezando ese día
+			name: "midnight inside the gap", value: "2024-09-08T00:00:00Z", from: "UTC", to: "America/Santiago",
+			want: "2024-09-08T01:00:00-03:00",
+		},
+		{
+			// 02:30 no existe en Madrid el 31/3: se corre a las 03:30 del mismo día
+			name: "wall time inside the gap", value: "2024-03-31T02:30:00Z", from: "UTC", to: "Europe/Madrid",
+			want: "2024-03-31T03:30:00+02:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := time.Parse(time.RFC3339, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := rezone(value.In(mustLoad(t, tt.from)), mustLoad(t, tt.to))
+			if got.Format(time.RFC3339) != tt.want {
+				t.Errorf("rezone = %s, want %s", got.Format(time.RFC3339), tt.want)
+			}
+			if got.Location().String() != tt.to {
+				t.Errorf("location = %s, want %s", got.Location(), tt.to)
+			}
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		// start y end son los instantes guardados en UTC; wantStart y wantEnd, en la zona del curso
+		start, end         string
+		wantStart, wantEnd string
+	}{
+		{
+			// 05:30Z es la primera 01:30 del 3/11 (EDT) y 06:30Z la segunda (EST)
+			name: "fall back overlap", timezone: "America/New_York",
+			start: "2024-11-03T05:30:00Z", end: "2024-11-03T06:30:00Z",
+			wantStart: "2024-11-03T01:30:00-04:00", wantEnd: "2024-11-03T01:30:00-05:00",
+		},
+		{
+			name: "spring forward", timezone: "America/New_York",
+			start: "2024-03-10T06:59:00Z", end: "2024-03-10T07:00:00Z",
+			wantStart: "2024-03-10T01:59:00-05:00", wantEnd: "2024-03-10T03:00:00-04:00",
+		},
+		{
+			name: "invalid zone falls back to UTC", timezone: "Mars/Olympus_Mons",
+			start: "2024-11-03T05:30:00Z", end: "2024-11-03T06:30:00Z",
+			wantStart: "2024-11-03T05:30:00Z", wantEnd: "2024-11-03T06:30:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, _ := time.Parse(time.RFC3339, tt.start)
+			end, _ := time.Parse(time.RFC3339, tt.end)
+			course := domain.Course{StartDate: start, EndDate: end}
+			localize(&course, tt.timezone)
+			if got := course.StartDate.Format(time.RFC3339); got != tt.wantStart {
+				t.Errorf("start = %s, want %s", got, tt.wantStart)
+			}
+			if got := course.EndDate.Format(time.RFC3339); got != tt.wantEnd {
+				t.Errorf("end = %s, want %s", got, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NicoJCastro/gocourse_course/internal/locale"
+)
+
+// Translation es el nombre y la descripción del curso en un idioma. domain.Course.Name
+// sigue siendo el nombre canónico: se usa cuando no hay traducción para la request.
+type Translation struct {
+	CourseID    string     `json:"course_id" gorm:"type:char(36);not null;primaryKey"`
+	Locale      string     `json:"locale" gorm:"type:varchar(8);not null;primaryKey"`
+	Name        string     `json:"name" gorm:"type:varchar(50);not null;index"`
+	Description string     `json:"description" gorm:"type:text"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	TenantID    string     `json:"-" gorm:"type:varchar(64);not null;default:'';index"`
+}
+
+const (
+	// MaxTranslationName es el largo de la columna, igual que el nombre del curso
+	MaxTranslationName = 50
+	MaxDescription     = 5000
+)
+
+func (Translation) TableName() string {
+	return "course_translations"
+}
+
+// SetTranslation crea o reemplaza la traducción del curso en locale
+func (s service) SetTranslation(ctx context.Context, id, code, name, description string) (*Translation, error) {
+	s.log.Println("---- Setting translation ----")
+	code, err := s.supportedLocale(code)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+	if utf8.RuneCountInString(name) > MaxTranslationName {
+		return nil, ErrNameTooLong
+	}
+	if utf8.RuneCountInString(description) > MaxDescription {
+		return nil, ErrDescriptionTooLong
+	}
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	translation := Translation{CourseID: id, Locale: code, Name: name, Description: description}
+	if err := s.repo.SetTranslation(ctx, &translation); err != nil {
+		s.log.Printf("Error setting translation: %v\n", err)
+		return nil, fmt.Errorf("%w: %w", ErrFailedToUpdateTranslations, err)
+	}
+	return &translation, nil
+}
+
+func (s service) RemoveTranslation(ctx context.Context, id, code string) error {
+	s.log.Println("---- Removing translation ----")
+	code, err := s.supportedLocale(code)
+	if err != nil {
+		return err
+	}
+	if _, err := s.get(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.RemoveTranslation(ctx, id, code); err != nil {
+		if errors.Is(err, ErrTranslationNotFound) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrFailedToUpdateTranslations, err)
+	}
+	return nil
+}
+
+// Translations devuelve todas las traducciones del curso ordenadas por idioma
+func (s service) Translations(ctx context.Context, id string) ([]Translation, error) {
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+	translations, err := s.repo.Translations(ctx, []string{id})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetTranslations, err)
+	}
+	result := translations[id]
+	if result == nil {
+		result = []Translation{}
+	}
+	return result, nil
+}
+
+// supportedLocale normaliza el código y lo rechaza si no es uno de los configurados
+func (s service) supportedLocale(code string) (string, error) {
+	normalized, err := locale.Normalize(code)
+	if err != nil || !slices.Contains(s.locales.Supported, normalized) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedLocale, code)
+	}
+	return normalized, nil
+}
+
+// translate elige la traducción del curso según la preferencia de la request: el
+// primer idioma aceptado que tenga traducción y si no el por defecto. Sin ninguna,
+// queda el nombre canónico.
+func translate(course *Course, translations []Translation, preference locale.Preference) {
+	for _, code := range preference.Candidates() {
+		for _, translation := range translations {
+			if translation.Locale == code {
+				course.Name = translation.Name
+				course.Description = translation.Description
+				course.Locale = code
+				return
+			}
+		}
+	}
+	course.Locale = preference.Default
+}
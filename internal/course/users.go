@@ -0,0 +1,39 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/pkg/user"
+)
+
+// verifyUsers confirma con el servicio de usuarios que los IDs existen y devuelve
+// notFound si alguno no existe. Si el servicio no responde (o el circuito está
+// abierto) el cambio se acepta: una caída ajena no bloquea la gestión de cursos.
+func (s service) verifyUsers(ctx context.Context, notFound error, ids ...string) error {
+	if s.users == nil {
+		return nil
+	}
+	ctx = user.WithRequestID(ctx, audit.RequestIDFrom(ctx))
+	for _, id := range ids {
+		_, err := s.users.Get(ctx, id)
+		if errors.Is(err, user.ErrNotFound) {
+			return fmt.Errorf("%w: %s", notFound, id)
+		}
+		if err != nil {
+			s.log.Printf("Could not verify user %s, accepting it: %v\n", id, err)
+		}
+	}
+	return nil
+}
+
+// verifyCreator verifica el usuario que hace la request (X-User-ID), si la identifica
+func (s service) verifyCreator(ctx context.Context) error {
+	actor := audit.ActorFrom(ctx)
+	if actor == audit.AnonymousActor || actor == audit.SystemActor {
+		return nil
+	}
+	return s.verifyUsers(ctx, ErrCreatorNotFound, actor)
+}
@@ -0,0 +1,73 @@
+package course_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/NicoJCastro/gocourse_course/pkg/user"
+	"github.com/NicoJCastro/gocourse_course/pkg/user/usertest"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+func TestVerifyUsers(t *testing.T) {
+	const (
+		known   = "3f2c1d4e-5b6a-4c7d-8e9f-0a1b2c3d4e5f"
+		missing = "9a8b7c6d-5e4f-4a3b-2c1d-0e9f8a7b6c5d"
+		// unverified tampoco existe: missing ya queda asignado en esas fechas con el servicio caído
+		unverified = "0b6f3c1e-1d2a-4e5f-9a8b-7c6d5e4f3a2b"
+	)
+	server := usertest.NewServer(domain.User{ID: known})
+	defer server.Close()
+
+	_, repo, _ := newTenantService(t)
+	cfg := user.DefaultConfig(server.URL)
+	cfg.CacheTTL = 0
+	cfg.Timeout = 50 * time.Millisecond
+	locales := config.Locales{Default: "en", Supported: []string{"en"}}
+	svc := course.NewService(tenanttest.Logger(), repo, nil, course.PrerequisiteRestrict, user.New(tenanttest.Logger(), cfg), locales)
+
+	ctx, _ := tenanttest.Contexts()
+	ctx = audit.WithRequestID(ctx, "req-123")
+	create := func(name string, instructorIDs ...string) error {
+		_, err := svc.Create(ctx, name, "2024-06-01", "2024-06-30", "", course.Classification{}, instructorIDs)
+		return err
+	}
+
+	if err := create("Go basics", known); err != nil {
+		t.Errorf("Create with a known instructor = %v", err)
+	}
+	if got := server.LastRequestID(); got != "req-123" {
+		t.Errorf("X-Request-ID = %q, want the request ID of the context", got)
+	}
+	if err := create("Go advanced", missing); !errors.Is(err, course.ErrUserNotFound) {
+		t.Errorf("Create with a missing instructor = %v, want %v", err, course.ErrUserNotFound)
+	}
+
+	t.Run("service down", func(t *testing.T) {
+		server.SetDown(true)
+		defer server.SetDown(false)
+		if err := create("Go for teams", missing); err != nil {
+			t.Errorf("Create while the user service is down = %v, want it accepted", err)
+		}
+	})
+
+	t.Run("service timeout", func(t *testing.T) {
+		server.SetDelay(time.Second)
+		defer server.SetDelay(0)
+		if err := create("Go concurrency", unverified); err != nil {
+			t.Errorf("Create while the user service is slow = %v, want it accepted", err)
+		}
+	})
+
+	t.Run("creator", func(t *testing.T) {
+		ctx := audit.WithActor(ctx, missing)
+		if _, err := svc.Create(ctx, "Go testing", "2024-06-01", "2024-06-30", "", course.Classification{}, nil); !errors.Is(err, course.ErrCreatorNotFound) {
+			t.Errorf("Create by a missing user = %v, want %v", err, course.ErrCreatorNotFound)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+// Package locale resuelve los idiomas que acepta cada request (Accept-Language)
+// entre los que soporta el servicio y los deja en el contexto
+package locale
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+type contextKey string
+
+const preferenceKey contextKey = "locale_preference"
+
+var ErrInvalidLocale = errors.New("invalid locale, must be an ISO 639 language code like es, en or pt")
+
+// Preference son los idiomas soportados que acepta el cliente, en orden de
+// preferencia, y el idioma por defecto para cuando ninguno está disponible.
+// Requested son todos los de Accept-Language, soportados o no: los mensajes de
+// error se eligen entre los idiomas de su propio catálogo.
+type Preference struct {
+	Accepted  []string
+	Requested []string
+	Default   string
+}
+
+// Normalize reduce un locale a su código de idioma ("pt-BR" es "pt")
+func Normalize(code string) (string, error) {
+	tag, err := language.Parse(strings.TrimSpace(code))
+	if err != nil {
+		return "", ErrInvalidLocale
+	}
+	base, _ := tag.Base()
+	return base.String(), nil
+}
+
+// Negotiate ordena los idiomas de Accept-Language (por q) que están en supported.
+// Un header inválido se trata como ausente.
+func Negotiate(acceptLanguage string, supported []string, defaultLocale string) Preference {
+	preference := Preference{Accepted: []string{}, Requested: []string{}, Default: defaultLocale}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return preference
+	}
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		code := base.String()
+		if !slices.Contains(preference.Requested, code) {
+			preference.Requested = append(preference.Requested, code)
+		}
+		if slices.Contains(supported, code) && !slices.Contains(preference.Accepted, code) {
+			preference.Accepted = append(preference.Accepted, code)
+		}
+	}
+	return preference
+}
+
+// Candidates es el orden en que se buscan las traducciones: los aceptados y al final el por defecto
+func (p Preference) Candidates() []string {
+	if p.Default == "" || slices.Contains(p.Accepted, p.Default) {
+		return p.Accepted
+	}
+	return append(slices.Clone(p.Accepted), p.Default)
+}
+
+func WithPreference(ctx context.Context, preference Preference) context.Context {
+	return context.WithValue(ctx, preferenceKey, preference)
+}
+
+// FromContext devuelve la preferencia de la request; fuera de una request (CLI,
+// procesos en segundo plano) no hay ninguna
+func FromContext(ctx context.Context) (Preference, bool) {
+	preference, ok := ctx.Value(preferenceKey).(Preference)
+	return preference, ok
+}
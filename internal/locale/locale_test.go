@@ -0,0 +1,63 @@
+package locale_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/locale"
+)
+
+func TestNegotiate(t *testing.T) {
+	supported := []string{"es", "en", "pt"}
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		accepted       []string
+		requested      []string
+		candidates     []string
+	}{
+		{"absent", "", []string{}, []string{}, []string{"es"}},
+		{"invalid header", "en;q=abc", []string{}, []string{}, []string{"es"}},
+		{"ordered by q", "en;q=0.5,pt-BR", []string{"pt", "en"}, []string{"pt", "en"}, []string{"pt", "en", "es"}},
+		{"regions collapse to the language", "pt-BR,pt-PT;q=0.9,en", []string{"pt", "en"}, []string{"pt", "en"}, []string{"pt", "en", "es"}},
+		{"unsupported fall back to the default", "fr,de;q=0.8", []string{}, []string{"fr", "de"}, []string{"es"}},
+		{"default not repeated", "es-AR,fr", []string{"es"}, []string{"es", "fr"}, []string{"es"}},
+		{"q=0 is not accepted", "en;q=0,pt", []string{"pt"}, []string{"pt"}, []string{"pt", "es"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := locale.Negotiate(tt.acceptLanguage, supported, "es")
+			if !slices.Equal(p.Accepted, tt.accepted) || !slices.Equal(p.Requested, tt.requested) {
+				t.Errorf("Negotiate(%q) = accepted %v, requested %v; want %v, %v", tt.acceptLanguage, p.Accepted, p.Requested, tt.accepted, tt.requested)
+			}
+			if got := p.Candidates(); !slices.Equal(got, tt.candidates) {
+				t.Errorf("Candidates = %v, want %v", got, tt.candidates)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for code, want := range map[string]string{"es": "es", " pt-BR ": "pt", "EN": "en", "zh-Hant-TW": "zh"} {
+		if got, err := locale.Normalize(code); err != nil || got != want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", code, got, err, want)
+		}
+	}
+	for _, code := range []string{"", "not a locale", "e"} {
+		if _, err := locale.Normalize(code); !errors.Is(err, locale.ErrInvalidLocale) {
+			t.Errorf("Normalize(%q) = %v, want %v", code, err, locale.ErrInvalidLocale)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if _, ok := locale.FromContext(context.Background()); ok {
+		t.Error("FromContext without a preference = ok")
+	}
+	ctx := locale.WithPreference(context.Background(), locale.Negotiate("en", []string{"en"}, "es"))
+	if p, ok := locale.FromContext(ctx); !ok || !slices.Equal(p.Candidates(), []string{"en", "es"}) {
+		t.Errorf("FromContext = %+v, %v", p, ok)
+	}
+}
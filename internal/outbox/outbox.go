@@ -0,0 +1,56 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Message es un evento de dominio pendiente de publicar. Se escribe en la misma
+// transacción que el cambio que lo origina y el Relay lo publica después.
+type Message struct {
+	ID            string          `json:"id" gorm:"type:char(36);not null;primary_key"`
+	EventType     string          `json:"event_type" gorm:"type:varchar(50);not null"`
+	AggregateType string          `json:"aggregate_type" gorm:"type:varchar(30);not null"`
+	AggregateID   string          `json:"aggregate_id" gorm:"type:char(36);not null"`
+	Payload       json.RawMessage `json:"payload" gorm:"type:json;not null"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"index:idx_outbox_pending,priority:2"`
+	PublishedAt   *time.Time      `json:"published_at,omitempty" gorm:"index:idx_outbox_pending,priority:1"`
+	Attempts      int             `json:"attempts" gorm:"not null;default:0"`
+	LastError     string          `json:"last_error,omitempty" gorm:"type:varchar(255)"`
+	// TenantID es la organización del agregado: los webhooks sólo se envían a sus suscripciones
+	TenantID string `json:"tenant_id" gorm:"type:varchar(64);not null;default:'';index"`
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+func (m *Message) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	return
+}
+
+// NewMessage serializa el payload del evento y lo asigna al tenant del contexto
+func NewMessage(ctx context.Context, eventType, aggregateType, aggregateID string, payload interface{}) (*Message, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	tenantID, _ := tenant.FromContext(ctx)
+	return &Message{
+		ID:            uuid.New().String(),
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       raw,
+		CreatedAt:     time.Now().UTC(),
+		TenantID:      tenantID,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+)
+
+type (
+	// Publisher entrega un mensaje al broker. El Relay reintenta los mensajes cuyo
+	// Publish falla, así que la entrega es "al menos una vez": los consumidores
+	// deben deduplicar por Message.ID.
+	Publisher interface {
+		Publish(ctx context.Context, msg Message) error
+		Close() error
+	}
+
+	// NATSPublisher publica cada mensaje en el subject "<prefix>.<event_type>"
+	NATSPublisher struct {
+		conn   *nats.Conn
+		prefix string
+	}
+
+	// MemoryPublisher guarda los mensajes en memoria; pensado para tests
+	MemoryPublisher struct {
+		mu       sync.Mutex
+		messages []Message
+		// Err, si no es nil, se devuelve en cada Publish para simular fallas
+		Err error
+	}
+)
+
+func NewNATSPublisher(url, prefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("gocourse-course-outbox"))
+	if err != nil {
+		return nil, err
+	}
+	return &NATSPublisher{conn: conn, prefix: prefix}, nil
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, msg Message) error {
+	natsMsg := nats.NewMsg(p.prefix + "." + msg.EventType)
+	natsMsg.Data = msg.Payload
+	// Nats-Msg-Id permite que JetStream descarte duplicados por reintentos
+	natsMsg.Header.Set(nats.MsgIdHdr, msg.ID)
+	natsMsg.Header.Set("Event-Type", msg.EventType)
+	natsMsg.Header.Set("Aggregate-ID", msg.AggregateID)
+	natsMsg.Header.Set("Tenant-ID", msg.TenantID)
+	if err := p.conn.PublishMsg(natsMsg); err != nil {
+		return err
+	}
+	// Flush confirma que el servidor recibió el mensaje antes de marcarlo publicado
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(_ context.Context, msg Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Err != nil {
+		return p.Err
+	}
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+// Messages devuelve una copia de los mensajes publicados
+func (p *MemoryPublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.messages...)
+}
+
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
+
+// MultiPublisher entrega cada mensaje a todos los publishers. Si alguno falla el
+// mensaje se reintenta completo, así que cada publisher debe tolerar duplicados.
+type MultiPublisher []Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, msg Message) error {
+	for _, p := range m {
+		if err := p.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m MultiPublisher) Close() error {
+	var errs []error
+	for _, p := range m {
+		if err := p.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,87 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Relay lee periódicamente el outbox y publica los mensajes pendientes en orden
+// de creación. Un mensaje se marca publicado sólo después de que Publish responde
+// sin error, por lo que ante una caída puede publicarse más de una vez.
+//
+// El orden sólo se garantiza con un único relay corriendo: con varias instancias
+// cada una toma un lote distinto (SKIP LOCKED) y los publican en paralelo.
+type Relay struct {
+	log       *log.Logger
+	repo      Repository
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+}
+
+func NewRelay(logger *log.Logger, repo Repository, publisher Publisher, interval time.Duration, batchSize int) *Relay {
+	return &Relay{
+		log:       logger,
+		repo:      repo,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run bloquea hasta que se cancele ctx; conviene lanzarlo en una goroutine
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		// Mientras haya lotes completos seguimos sin esperar al ticker
+		for {
+			published, err := r.RelayBatch(ctx)
+			if err != nil {
+				r.log.Println("error relaying outbox: ", err)
+				break
+			}
+			if published < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch publica un lote de mensajes pendientes y devuelve cuántos procesó.
+// Se detiene en el primer fallo para que el lote no se publique salteado.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	processed := 0
+	err := r.repo.Transaction(ctx, func(txRepo Repository) error {
+		messages, err := txRepo.FetchPending(ctx, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		published := make([]string, 0, len(messages))
+		for _, msg := range messages {
+			if err := r.publisher.Publish(ctx, msg); err != nil {
+				r.log.Printf("error publishing outbox message %s: %v\n", msg.ID, err)
+				if markErr := txRepo.MarkFailed(ctx, msg.ID, err); markErr != nil {
+					return markErr
+				}
+				break
+			}
+			published = append(published, msg.ID)
+		}
+		processed = len(published)
+		return txRepo.MarkPublished(ctx, published)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return processed, nil
+}
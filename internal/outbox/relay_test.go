@@ -0,0 +1,154 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/catalog"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/outbox"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"gorm.io/gorm"
+)
+
+// pending crea n mensajes con created_at creciente, en el orden en que deben publicarse
+func pending(t *testing.T, repo outbox.Repository, n int) []*outbox.Message {
+	t.Helper()
+	start := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	messages := make([]*outbox.Message, n)
+	for i := range messages {
+		messages[i] = &outbox.Message{
+			EventType:     course.EventCourseCreated,
+			AggregateType: "course",
+			AggregateID:   "0b6f3c1e-1d2a-4e5f-9a8b-7c6d5e4f3a2b",
+			Payload:       json.RawMessage(`{}`),
+			CreatedAt:     start.Add(time.Duration(i) * time.Second),
+		}
+	}
+	if err := repo.Create(context.Background(), messages...); err != nil {
+		t.Fatal(err)
+	}
+	return messages
+}
+
+func TestRelayBatch(t *testing.T) {
+	db := tenanttest.NewDB(t, &outbox.Message{})
+	repo := outbox.NewRepo(db, tenanttest.Logger())
+	messages := pending(t, repo, 3)
+	publisher := outbox.NewMemoryPublisher()
+	relay := outbox.NewRelay(tenanttest.Logger(), repo, publisher, time.Second, 10)
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		publisher.Err = errors.New("nats: connection closed")
+		published, err := relay.RelayBatch(context.Background())
+		if err != nil || published != 0 {
+			t.Fatalf("RelayBatch = %d, %v; want 0 published", published, err)
+		}
+
+		var stored []outbox.Message
+		if err := db.Order("created_at").Find(&stored).Error; err != nil {
+			t.Fatal(err)
+		}
+		for i, msg := range stored {
+			wantAttempts := 0
+			if i == 0 {
+				wantAttempts = 1
+			}
+			if msg.Attempts != wantAttempts || msg.PublishedAt != nil {
+				t.Errorf("message %d: attempts = %d, published = %v; want %d attempts, pending", i, msg.Attempts, msg.PublishedAt, wantAttempts)
+			}
+		}
+		if stored[0].LastError != "nats: connection closed" {
+			t.Errorf("last error = %q", stored[0].LastError)
+		}
+	})
+
+	t.Run("publishes in creation order once the publisher recovers", func(t *testing.T) {
+		publisher.Err = nil
+		published, err := relay.RelayBatch(context.Background())
+		if err != nil || published != 3 {
+			t.Fatalf("RelayBatch = %d, %v; want 3 published", published, err)
+		}
+		for i, msg := range publisher.Messages() {
+			if msg.ID != messages[i].ID {
+				t.Errorf("published[%d] = %s, want %s", i, msg.ID, messages[i].ID)
+			}
+		}
+		if published, err := relay.RelayBatch(context.Background()); err != nil || published != 0 {
+			t.Errorf("second RelayBatch = %d, %v; want nothing pending", published, err)
+		}
+	})
+}
+
+func TestMarkFailed(t *testing.T) {
+	db := tenanttest.NewDB(t, &outbox.Message{})
+	repo := outbox.NewRepo(db, tenanttest.Logger())
+	msg := pending(t, repo, 1)[0]
+
+	// 254 bytes de ASCII y después caracteres de 2 bytes: cortar por bytes partiría una runa
+	cause := errors.New(strings.Repeat("a", 254) + strings.Repeat("ñ", 10))
+	for range 2 {
+		if err := repo.MarkFailed(context.Background(), msg.ID, cause); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stored outbox.Message
+	if err := db.First(&stored, "id = ?", msg.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", stored.Attempts)
+	}
+	if want := strings.Repeat("a", 254) + "ñ"; stored.LastError != want || !utf8.ValidString(stored.LastError) {
+		t.Errorf("last error = %q (%d runes), want the first 255 runes", stored.LastError, utf8.RuneCountInString(stored.LastError))
+	}
+}
+
+// TestEventsInTheSameTransaction verifica que el curso y su evento se escriban juntos:
+// si el outbox falla, el curso tampoco queda guardado
+func TestEventsInTheSameTransaction(t *testing.T) {
+	db := tenanttest.NewDB(t,
+		&course.TenantCourse{}, &course.CourseTimezone{}, &audit.Entry{}, &audit.Sequence{}, &outbox.Message{},
+		&catalog.Tag{}, &catalog.Category{}, &catalog.CourseTag{}, &catalog.CourseCategory{},
+	)
+	ctx, _ := tenanttest.Contexts()
+	repo := course.NewRepo(db, tenanttest.Logger())
+	locales := config.Locales{Default: "en", Supported: []string{"en"}}
+	svc := course.NewService(tenanttest.Logger(), repo, nil, course.PrerequisiteRestrict, nil, locales)
+
+	created, err := svc.Create(ctx, "Go basics", "2024-06-01", "2024-06-30", "", course.Classification{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []outbox.Message
+	if err := db.Find(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].EventType != course.EventCourseCreated || events[0].AggregateID != created.ID {
+		t.Fatalf("events = %+v, want one %s for %s", events, course.EventCourseCreated, created.ID)
+	}
+
+	failOutbox := func(tx *gorm.DB) {
+		if tx.Statement.Table == "outbox_messages" {
+			tx.AddError(errors.New("outbox unavailable"))
+		}
+	}
+	if err := db.Callback().Create().Before("gorm:create").Register("test:fail_outbox", failOutbox); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Create(ctx, "Go advanced", "2024-07-01", "2024-07-31", "", course.Classification{}, nil); err == nil {
+		t.Fatal("Create succeeded without its event")
+	}
+	count, err := svc.Count(ctx, course.Filters{Deleted: course.DeletedInclude})
+	if err != nil || count != 1 {
+		t.Errorf("courses = %d, %v; want only the first one", count, err)
+	}
+}
@@ -0,0 +1,107 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	Repository interface {
+		Create(ctx context.Context, messages ...*Message) error
+		// FetchPending bloquea (FOR UPDATE SKIP LOCKED) los mensajes no publicados más
+		// antiguos; debe llamarse dentro de Transaction para que el bloqueo tenga efecto.
+		// SKIP LOCKED evita publicar dos veces, pero dos relays se saltean sus lotes y
+		// publican fuera de orden: el orden sólo vale con un único relay.
+		FetchPending(ctx context.Context, limit int) ([]Message, error)
+		MarkPublished(ctx context.Context, ids []string) error
+		MarkFailed(ctx context.Context, id string, cause error) error
+		Transaction(ctx context.Context, fn func(txRepo Repository) error) error
+	}
+
+	repo struct {
+		db  *gorm.DB
+		log *log.Logger
+	}
+)
+
+// NewRepo crea el repositorio del outbox. Si db es una transacción, los
+// mensajes se escriben dentro de ella.
+func NewRepo(db *gorm.DB, logger *log.Logger) Repository {
+	return &repo{
+		db:  db,
+		log: logger,
+	}
+}
+
+func (r *repo) Create(ctx context.Context, messages ...*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).CreateInBatches(messages, 100).Error; err != nil {
+		r.log.Printf("error: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) FetchPending(ctx context.Context, limit int) ([]Message, error) {
+	var messages []Message
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("published_at IS NULL").
+		Order("created_at asc").
+		Limit(limit).
+		Find(&messages)
+	if result.Error != nil {
+		r.log.Println("Error fetching outbox messages: ", result.Error)
+		return nil, result.Error
+	}
+	return messages, nil
+}
+
+func (r *repo) MarkPublished(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	result := r.db.WithContext(ctx).Model(&Message{}).
+		Where("id IN ?", ids).
+		Update("published_at", time.Now().UTC())
+	if result.Error != nil {
+		r.log.Println("Error marking outbox messages: ", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (r *repo) MarkFailed(ctx context.Context, id string, cause error) error {
+	// 🔧 last_error es varchar(255): se corta por caracteres, no por bytes, para no partir una runa
+	lastError := cause.Error()
+	if utf8.RuneCountInString(lastError) > 255 {
+		lastError = string([]rune(lastError)[:255])
+	}
+	result := r.db.WithContext(ctx).Model(&Message{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": lastError,
+		})
+	if result.Error != nil {
+		r.log.Println("Error marking outbox message as failed: ", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (r *repo) Transaction(ctx context.Context, fn func(txRepo Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repo{
+			db:  tx,
+			log: r.log,
+		})
+	})
+}
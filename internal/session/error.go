@@ -0,0 +1,71 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error es un error de la API con un código estable, el que reciben los clientes
+type Error struct {
+	code    string
+	message string
+}
+
+func newError(code, message string) *Error {
+	return &Error{code: code, message: message}
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Code() string {
+	return e.code
+}
+
+var ErrInvalidRequestType = newError("invalid_request_type", "invalid request type")
+var ErrIDRequired = newError("id_required", "id is required")
+var ErrCourseIDRequired = newError("course_id_required", "course id is required")
+var ErrStartRequired = newError("start_required", "start is required")
+var ErrAtLeastOneFieldRequired = newError("at_least_one_field_required", "at least one field is required")
+var ErrInvalidStart = newError("invalid_start", "invalid start, must be RFC3339 or a local time like 2006-01-02T15:04")
+var ErrInvalidDuration = newError("invalid_duration", "invalid duration_minutes, must be between 1 and 1440")
+var ErrLocationTooLong = newError("location_too_long", "location is too long")
+var ErrInvalidTimezone = newError("invalid_timezone", "invalid timezone, must be an IANA name like America/Argentina/Buenos_Aires")
+var ErrInvalidRRule = newError("invalid_rrule", "invalid rrule")
+var ErrEmptyRRule = newError("empty_rrule", "rrule does not produce any session")
+var ErrTooManySessions = newError("too_many_sessions", "rrule produces too many sessions")
+var ErrOutsideCourse = newError("session_outside_course", "session falls outside the course dates")
+var ErrInvalidRange = newError("invalid_range", "invalid from/to, must be RFC3339")
+var ErrCourseNotFound = newError("course_not_found", "course not found")
+var ErrFailedToSaveSession = newError("failed_to_save_session", "failed to save session")
+var ErrFailedToGetSessions = newError("failed_to_get_sessions", "failed to get sessions")
+var ErrFailedToDeleteSession = newError("failed_to_delete_session", "failed to delete session")
+var ErrFailedToGenerateMetadata = newError("metadata_error", "error generating metadata")
+
+// ErrNotFound indica que la sesión no existe o no pertenece al curso
+type ErrNotFound struct {
+	SessionID string
+}
+
+func (e *ErrNotFound) Error() string {
+	return fmt.Sprintf("session with ID %s not found", e.SessionID)
+}
+
+func (e *ErrNotFound) Code() string {
+	return "session_not_found"
+}
+
+func (e *ErrNotFound) Args() []any {
+	return []any{e.SessionID}
+}
+
+func (e *ErrNotFound) Unwrap() error {
+	return ErrNotFoundBase
+}
+
+func NewErrNotFound(sessionID string) *ErrNotFound {
+	return &ErrNotFound{SessionID: sessionID}
+}
+
+var ErrNotFoundBase = errors.New("session not found")
@@ -0,0 +1,242 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NicoJCastro/go_lib_response/response"
+	"github.com/NicoJCastro/gocourse_course/pkg/apierror"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/NicoJCastro/gocourse_meta/meta"
+)
+
+type (
+	Controller func(ctx context.Context, request interface{}) (interface{}, error)
+
+	Endpoint struct {
+		Create Controller
+		Get    Controller
+		GetAll Controller
+		Update Controller
+		Delete Controller
+	}
+
+	// CreateReq: start acepta RFC3339 o una hora local en timezone; rrule genera una serie
+	CreateReq struct {
+		CourseID string `json:"course_id"`
+		Start    string `json:"start"`
+		Duration int    `json:"duration_minutes"`
+		Location string `json:"location"`
+		Timezone string `json:"timezone"`
+		RRule    string `json:"rrule"`
+	}
+
+	GetReq struct {
+		CourseID string `json:"course_id"`
+		ID       string `json:"id"`
+	}
+
+	// GetAllReq: from y to (RFC3339) filtran por inicio de la sesión
+	GetAllReq struct {
+		CourseID string `json:"course_id"`
+		From     string `json:"from"`
+		To       string `json:"to"`
+		Limit    int    `json:"limit"`
+		Page     int    `json:"page"`
+	}
+
+	UpdateReq struct {
+		CourseID string  `json:"course_id"`
+		ID       string  `json:"id"`
+		Start    *string `json:"start"`
+		Duration *int    `json:"duration_minutes"`
+		Location *string `json:"location"`
+		Timezone *string `json:"timezone"`
+	}
+
+	// DeleteReq: Series elimina todas las sesiones de la misma regla RRULE
+	DeleteReq struct {
+		CourseID string `json:"course_id"`
+		ID       string `json:"id"`
+		Series   bool   `json:"series"`
+	}
+
+	Config struct {
+		Pagination config.Pagination
+	}
+)
+
+func MakeEndpoint(s Service, config Config) Endpoint {
+	return Endpoint{
+		Create: makeCreateEndpoint(s),
+		Get:    makeGetEndpoint(s),
+		GetAll: makeGetAllEndpoint(s, config),
+		Update: makeUpdateEndpoint(s),
+		Delete: makeDeleteEndpoint(s),
+	}
+}
+
+func makeCreateEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(CreateReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.CourseID == "" {
+			return nil, apierror.BadRequest(ErrCourseIDRequired)
+		}
+		if req.Start == "" {
+			return nil, apierror.BadRequest(ErrStartRequired)
+		}
+
+		sessions, err := s.Create(ctx, req.CourseID, Input{
+			Start:    req.Start,
+			Duration: req.Duration,
+			Location: req.Location,
+			Timezone: req.Timezone,
+			RRule:    req.RRule,
+		})
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.Created("Sessions created successfully", sessions, nil), nil
+	}
+}
+
+func makeGetEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.CourseID == "" {
+			return nil, apierror.BadRequest(ErrCourseIDRequired)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		session, err := s.Get(ctx, req.CourseID, req.ID)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Session retrieved successfully", session, nil), nil
+	}
+}
+
+func makeGetAllEndpoint(s Service, config Config) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetAllReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.CourseID == "" {
+			return nil, apierror.BadRequest(ErrCourseIDRequired)
+		}
+
+		var filters Filters
+		var err error
+		if filters.From, err = parseBound(req.From); err != nil {
+			return nil, apierror.BadRequest(err)
+		}
+		if filters.To, err = parseBound(req.To); err != nil {
+			return nil, apierror.BadRequest(err)
+		}
+
+		count, err := s.Count(ctx, req.CourseID, filters)
+		if err != nil {
+			return nil, apierror.InternalServerError(err)
+		}
+		metaData, err := newMeta(req.Page, req.Limit, count, config)
+		if err != nil {
+			return nil, apierror.InternalServerError(fmt.Errorf("%w: %w", ErrFailedToGenerateMetadata, err))
+		}
+
+		sessions, err := s.GetAll(ctx, req.CourseID, filters, metaData.Offset(), metaData.Limit())
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Sessions retrieved successfully", sessions, metaData), nil
+	}
+}
+
+func makeUpdateEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(UpdateReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.CourseID == "" {
+			return nil, apierror.BadRequest(ErrCourseIDRequired)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if req.Start == nil && req.Duration == nil && req.Location == nil && req.Timezone == nil {
+			return nil, apierror.BadRequest(ErrAtLeastOneFieldRequired)
+		}
+
+		session, err := s.Update(ctx, req.CourseID, req.ID, UpdateInput{
+			Start:    req.Start,
+			Duration: req.Duration,
+			Location: req.Location,
+			Timezone: req.Timezone,
+		})
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Session updated successfully", session, nil), nil
+	}
+}
+
+func makeDeleteEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(DeleteReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.CourseID == "" {
+			return nil, apierror.BadRequest(ErrCourseIDRequired)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+
+		deleted, err := s.Delete(ctx, req.CourseID, req.ID, req.Series)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Sessions deleted successfully", map[string]int64{"deleted": deleted}, nil), nil
+	}
+}
+
+// parseBound interpreta un límite opcional de rango en RFC3339
+func parseBound(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, ErrInvalidRange
+	}
+	return &t, nil
+}
+
+func newMeta(page, limit int, count int64, config Config) (*meta.Meta, error) {
+	return config.Pagination.Meta(page, limit, int(count))
+}
+
+// errorResponse traduce los errores del servicio a respuestas HTTP
+func errorResponse(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFoundBase), errors.Is(err, ErrCourseNotFound):
+		return apierror.NotFound(err)
+	case errors.Is(err, ErrInvalidStart), errors.Is(err, ErrInvalidDuration),
+		errors.Is(err, ErrLocationTooLong), errors.Is(err, ErrInvalidTimezone),
+		errors.Is(err, ErrInvalidRRule), errors.Is(err, ErrEmptyRRule),
+		errors.Is(err, ErrTooManySessions), errors.Is(err, ErrOutsideCourse):
+		return apierror.BadRequest(err)
+	}
+	return apierror.InternalServerError(err)
+}
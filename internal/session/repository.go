@@ -0,0 +1,175 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_domain/domain"
+	"gorm.io/gorm"
+)
+
+type (
+	// Filters limita las sesiones a las que empiezan en [From, To)
+	Filters struct {
+		From *time.Time
+		To   *time.Time
+	}
+
+	Repository interface {
+		// GetCourse devuelve el curso padre, sin contar los que están en la papelera,
+		// con sus fechas expresadas en la zona horaria del curso
+		GetCourse(ctx context.Context, courseID string) (*domain.Course, error)
+		Create(ctx context.Context, sessions []Session) error
+		Get(ctx context.Context, courseID, id string) (*Session, error)
+		GetAll(ctx context.Context, courseID string, filters Filters, offset, limit int) ([]Session, error)
+		Count(ctx context.Context, courseID string, filters Filters) (int64, error)
+		// GetByCourse devuelve todas las sesiones del curso, para revalidarlas
+		GetByCourse(ctx context.Context, courseID string) ([]Session, error)
+		Save(ctx context.Context, session *Session) error
+		Delete(ctx context.Context, courseID, id string) error
+		DeleteSeries(ctx context.Context, courseID, seriesID string) (int64, error)
+		// RemoveCourses borra las sesiones de cursos eliminados definitivamente
+		RemoveCourses(ctx context.Context, courseIDs []string) error
+	}
+
+	repo struct {
+		db  *gorm.DB
+		log *log.Logger
+	}
+)
+
+func NewRepo(db *gorm.DB, logger *log.Logger) Repository {
+	return &repo{
+		db:  db,
+		log: logger,
+	}
+}
+
+func (r *repo) GetCourse(ctx context.Context, courseID string) (*domain.Course, error) {
+	course := domain.Course{ID: courseID}
+	if err := r.db.WithContext(ctx).First(&course).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		r.log.Println("Error getting course: ", err)
+		return nil, err
+	}
+
+	// La zona horaria vive en course_timezones (paquete course); sin fila es UTC
+	var timezones []string
+	if err := r.db.WithContext(ctx).Table("course_timezones").
+		Where("course_id = ?", courseID).Pluck("timezone", &timezones).Error; err != nil {
+		r.log.Println("Error getting course timezone: ", err)
+		return nil, err
+	}
+	loc := time.UTC
+	if len(timezones) > 0 {
+		if courseLoc, err := time.LoadLocation(timezones[0]); err == nil {
+			loc = courseLoc
+		}
+	}
+	course.StartDate = course.StartDate.In(loc)
+	course.EndDate = course.EndDate.In(loc)
+	return &course, nil
+}
+
+func (r *repo) Create(ctx context.Context, sessions []Session) error {
+	if err := r.db.WithContext(ctx).Create(&sessions).Error; err != nil {
+		r.log.Printf("error: %v", err)
+		return err
+	}
+	r.log.Println("sessions created: ", len(sessions))
+	return nil
+}
+
+func (r *repo) Get(ctx context.Context, courseID, id string) (*Session, error) {
+	var session Session
+	err := r.db.WithContext(ctx).Where("id = ? AND course_id = ?", id, courseID).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewErrNotFound(id)
+		}
+		r.log.Println("Error getting session: ", err)
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *repo) GetAll(ctx context.Context, courseID string, filters Filters, offset, limit int) ([]Session, error) {
+	var sessions []Session
+	tx := applyFilters(r.db.WithContext(ctx).Model(&sessions), courseID, filters)
+	result := tx.Order("starts_at, id").Limit(limit).Offset(offset).Find(&sessions)
+	if result.Error != nil {
+		r.log.Println("Error getting sessions: ", result.Error)
+		return nil, result.Error
+	}
+	return sessions, nil
+}
+
+func (r *repo) Count(ctx context.Context, courseID string, filters Filters) (int64, error) {
+	var count int64
+	tx := applyFilters(r.db.WithContext(ctx).Model(&Session{}), courseID, filters)
+	if err := tx.Count(&count).Error; err != nil {
+		r.log.Println("Error counting sessions: ", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func applyFilters(tx *gorm.DB, courseID string, filters Filters) *gorm.DB {
+	tx = tx.Where("course_id = ?", courseID)
+	if filters.From != nil {
+		tx = tx.Where("starts_at >= ?", filters.From.UTC())
+	}
+	if filters.To != nil {
+		tx = tx.Where("starts_at < ?", filters.To.UTC())
+	}
+	return tx
+}
+
+func (r *repo) GetByCourse(ctx context.Context, courseID string) ([]Session, error) {
+	return r.GetAll(ctx, courseID, Filters{}, 0, -1)
+}
+
+func (r *repo) Save(ctx context.Context, session *Session) error {
+	if err := r.db.WithContext(ctx).Save(session).Error; err != nil {
+		r.log.Println("Error saving session: ", err)
+		return err
+	}
+	session.localize()
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, courseID, id string) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND course_id = ?", id, courseID).Delete(&Session{})
+	if result.Error != nil {
+		r.log.Println("Error deleting session: ", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewErrNotFound(id)
+	}
+	return nil
+}
+
+func (r *repo) DeleteSeries(ctx context.Context, courseID, seriesID string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("series_id = ? AND course_id = ?", seriesID, courseID).Delete(&Session{})
+	if result.Error != nil {
+		r.log.Println("Error deleting session series: ", result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *repo) RemoveCourses(ctx context.Context, courseIDs []string) error {
+	if len(courseIDs) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Where("course_id IN ?", courseIDs).Delete(&Session{}).Error; err != nil {
+		r.log.Println("Error deleting course sessions: ", err)
+		return err
+	}
+	return nil
+}
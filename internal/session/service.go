@@ -0,0 +1,257 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NicoJCastro/gocourse_domain/domain"
+	"github.com/google/uuid"
+)
+
+type (
+	// Input son los datos de una sesión nueva; RRule la convierte en una serie
+	Input struct {
+		Start    string
+		Duration int
+		Location string
+		Timezone string
+		RRule    string
+	}
+
+	// UpdateInput modifica una sola sesión; los campos nil no cambian
+	UpdateInput struct {
+		Start    *string
+		Duration *int
+		Location *string
+		Timezone *string
+	}
+
+	Service interface {
+		// Create devuelve la sesión creada o todas las de la serie si hay RRule
+		Create(ctx context.Context, courseID string, input Input) ([]Session, error)
+		Get(ctx context.Context, courseID, id string) (*Session, error)
+		GetAll(ctx context.Context, courseID string, filters Filters, offset, limit int) ([]Session, error)
+		Count(ctx context.Context, courseID string, filters Filters) (int64, error)
+		Update(ctx context.Context, courseID, id string, input UpdateInput) (*Session, error)
+		// Delete con series elimina todas las sesiones de la serie de id y devuelve cuántas borró
+		Delete(ctx context.Context, courseID, id string, series bool) (int64, error)
+	}
+
+	service struct {
+		log  *log.Logger
+		repo Repository
+	}
+)
+
+func NewService(log *log.Logger, repo Repository) Service {
+	return &service{
+		log:  log,
+		repo: repo,
+	}
+}
+
+func (s *service) Create(ctx context.Context, courseID string, input Input) ([]Session, error) {
+	s.log.Println("---- Creating session ----")
+
+	course, err := s.getCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	timezone, loc, err := LoadTimezone(input.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	start, err := ParseStart(input.Start, loc)
+	if err != nil {
+		return nil, err
+	}
+	location, err := validateDetails(input.Duration, input.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	starts := []time.Time{start}
+	var seriesID *string
+	if input.RRule != "" {
+		// Sin fin explícito, la serie termina con el último día del curso
+		year, month, day := course.EndDate.Date()
+		until := time.Date(year, month, day, 23, 59, 59, 0, loc)
+		if starts, err = Expand(start, input.RRule, until); err != nil {
+			return nil, err
+		}
+		id := uuid.New().String()
+		seriesID = &id
+	}
+
+	sessions := make([]Session, 0, len(starts))
+	for _, startsAt := range starts {
+		session := Session{
+			CourseID: courseID,
+			SeriesID: seriesID,
+			StartsAt: startsAt,
+			Duration: input.Duration,
+			Location: location,
+			Timezone: timezone,
+		}
+		if err := ValidateWithin(course, session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := s.repo.Create(ctx, sessions); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToSaveSession, err)
+	}
+	for i := range sessions {
+		sessions[i].localize()
+	}
+	return sessions, nil
+}
+
+func (s *service) Get(ctx context.Context, courseID, id string) (*Session, error) {
+	// El curso se valida primero porque sólo la consulta de cursos filtra por tenant
+	if _, err := s.getCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+	session, err := s.repo.Get(ctx, courseID, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFoundBase) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetSessions, err)
+	}
+	return session, nil
+}
+
+func (s *service) GetAll(ctx context.Context, courseID string, filters Filters, offset, limit int) ([]Session, error) {
+	if _, err := s.getCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+	sessions, err := s.repo.GetAll(ctx, courseID, filters, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetSessions, err)
+	}
+	return sessions, nil
+}
+
+func (s *service) Count(ctx context.Context, courseID string, filters Filters) (int64, error) {
+	count, err := s.repo.Count(ctx, courseID, filters)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrFailedToGetSessions, err)
+	}
+	return count, nil
+}
+
+// Update modifica sólo esta sesión aunque sea parte de una serie. Cambiar la
+// zona horaria sin start conserva el instante de inicio.
+func (s *service) Update(ctx context.Context, courseID, id string, input UpdateInput) (*Session, error) {
+	s.log.Println("---- Updating session ----")
+
+	course, err := s.getCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.Get(ctx, courseID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Timezone != nil {
+		timezone, loc, err := LoadTimezone(*input.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		session.Timezone = timezone
+		session.StartsAt = session.StartsAt.In(loc)
+	}
+	if input.Start != nil {
+		if session.StartsAt, err = ParseStart(*input.Start, session.StartsAt.Location()); err != nil {
+			return nil, err
+		}
+	}
+	if input.Duration != nil {
+		session.Duration = *input.Duration
+	}
+	if input.Location != nil {
+		session.Location = *input.Location
+	}
+	if session.Location, err = validateDetails(session.Duration, session.Location); err != nil {
+		return nil, err
+	}
+	if err := ValidateWithin(course, *session); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, session); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToSaveSession, err)
+	}
+	return session, nil
+}
+
+func (s *service) Delete(ctx context.Context, courseID, id string, series bool) (int64, error) {
+	s.log.Println("---- Deleting session ----")
+	if _, err := s.getCourse(ctx, courseID); err != nil {
+		return 0, err
+	}
+
+	if series {
+		session, err := s.Get(ctx, courseID, id)
+		if err != nil {
+			return 0, err
+		}
+		if session.SeriesID != nil {
+			deleted, err := s.repo.DeleteSeries(ctx, courseID, *session.SeriesID)
+			if err != nil {
+				return 0, fmt.Errorf("%w: %v", ErrFailedToDeleteSession, err)
+			}
+			return deleted, nil
+		}
+	}
+
+	if err := s.repo.Delete(ctx, courseID, id); err != nil {
+		if errors.Is(err, ErrNotFoundBase) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("%w: %v", ErrFailedToDeleteSession, err)
+	}
+	return 1, nil
+}
+
+func (s *service) getCourse(ctx context.Context, courseID string) (*domain.Course, error) {
+	course, err := s.repo.GetCourse(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, ErrCourseNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetSessions, err)
+	}
+	return course, nil
+}
+
+// ValidateWithin verifica que la sesión caiga dentro de las fechas del curso
+func ValidateWithin(course *domain.Course, session Session) error {
+	if !session.Within(course.StartDate, course.EndDate) {
+		return fmt.Errorf("%w: %s is not between %s and %s", ErrOutsideCourse,
+			session.StartsAt.Format(time.RFC3339),
+			course.StartDate.Format("2006-01-02"), course.EndDate.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// validateDetails valida la duración y devuelve la ubicación sin espacios alrededor
+func validateDetails(duration int, location string) (string, error) {
+	if duration <= 0 || duration > MaxDuration {
+		return "", ErrInvalidDuration
+	}
+	location = strings.TrimSpace(location)
+	if utf8.RuneCountInString(location) > MaxLocationLength {
+		return "", ErrLocationTooLong
+	}
+	return location, nil
+}
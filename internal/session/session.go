@@ -0,0 +1,150 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // las zonas horarias no dependen de la imagen del contenedor
+
+	"github.com/google/uuid"
+	"github.com/teambition/rrule-go"
+	"gorm.io/gorm"
+)
+
+// Session es una clase concreta de un curso. StartsAt se guarda en UTC y se
+// devuelve en la zona horaria de la sesión.
+type Session struct {
+	ID       string `json:"id" gorm:"type:char(36);not null;primary_key"`
+	CourseID string `json:"course_id" gorm:"type:char(36);not null;index:idx_sessions_course,priority:1"`
+	TenantID string `json:"-" gorm:"type:varchar(64);not null;default:'';index"`
+	// SeriesID agrupa las sesiones generadas por una misma regla de recurrencia
+	SeriesID  *string    `json:"series_id,omitempty" gorm:"type:char(36);index"`
+	StartsAt  time.Time  `json:"starts_at" gorm:"not null;index:idx_sessions_course,priority:2"`
+	EndsAt    time.Time  `json:"ends_at" gorm:"-"`
+	Duration  int        `json:"duration_minutes" gorm:"not null"`
+	Location  string     `json:"location" gorm:"type:varchar(255)"`
+	Timezone  string     `json:"timezone" gorm:"type:varchar(64);not null"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+const (
+	// MaxDuration es la duración máxima de una sesión en minutos
+	MaxDuration = 24 * 60
+	// MaxSeriesSessions limita las sesiones que puede generar una regla RRULE
+	MaxSeriesSessions = 366
+	MaxLocationLength = 255
+	DefaultTimezone   = "UTC"
+)
+
+// localLayouts son los formatos aceptados para una hora local, sin offset
+var localLayouts = []string{"2006-01-02T15:04", "2006-01-02T15:04:05"}
+
+func (Session) TableName() string {
+	return "course_sessions"
+}
+
+func (s *Session) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return
+}
+
+func (s *Session) BeforeSave(tx *gorm.DB) (err error) {
+	s.StartsAt = s.StartsAt.UTC()
+	return
+}
+
+func (s *Session) AfterFind(tx *gorm.DB) (err error) {
+	s.localize()
+	return
+}
+
+// localize expresa StartsAt y EndsAt en la zona horaria de la sesión
+func (s *Session) localize() {
+	if loc, err := time.LoadLocation(s.Timezone); err == nil {
+		s.StartsAt = s.StartsAt.In(loc)
+	}
+	s.EndsAt = s.StartsAt.Add(time.Duration(s.Duration) * time.Minute)
+}
+
+// Within indica si la sesión cae entre las fechas del curso (ambas inclusive),
+// comparando fechas de calendario: las de la sesión en su zona horaria y las
+// del curso en la zona en que vienen expresadas, la del curso
+func (s Session) Within(startDate, endDate time.Time) bool {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	end := s.StartsAt.Add(time.Duration(s.Duration)*time.Minute - time.Nanosecond)
+	return !civilDate(s.StartsAt.In(loc)).Before(civilDate(startDate)) &&
+		!civilDate(end.In(loc)).After(civilDate(endDate))
+}
+
+// civilDate descarta la hora y la zona horaria de t
+func civilDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// ParseStart acepta RFC3339 (con offset) o una hora local ("2006-01-02T15:04")
+// que se interpreta en loc
+func ParseStart(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidStart, value)
+}
+
+// LoadTimezone valida un nombre IANA; vacío es DefaultTimezone
+func LoadTimezone(name string) (string, *time.Location, error) {
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, name)
+	}
+	return name, loc, nil
+}
+
+// Expand genera los inicios de una regla RRULE a partir de start, en la zona
+// horaria de start para que la hora local se mantenga aunque cambie el horario
+// de verano. Sin COUNT ni UNTIL la serie termina en until.
+func Expand(start time.Time, rule string, until time.Time) ([]time.Time, error) {
+	option, err := rrule.StrToROption(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+	}
+	option.Dtstart = start
+	if option.Count == 0 && option.Until.IsZero() {
+		option.Until = until
+	}
+	if option.Count > MaxSeriesSessions {
+		return nil, ErrTooManySessions
+	}
+
+	recurrence, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+	}
+
+	var starts []time.Time
+	next := recurrence.Iterator()
+	for occurrence, ok := next(); ok; occurrence, ok = next() {
+		if len(starts) == MaxSeriesSessions {
+			return nil, ErrTooManySessions
+		}
+		starts = append(starts, occurrence)
+	}
+	if len(starts) == 0 {
+		return nil, ErrEmptyRRule
+	}
+	return starts, nil
+}
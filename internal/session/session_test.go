@@ -0,0 +1,121 @@
+package session_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/session"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+func TestExpand(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 3, 4, 10, 0, 0, 0, newYork)
+	until := time.Date(2024, 3, 31, 23, 59, 59, 0, newYork)
+
+	tests := []struct {
+		name  string
+		rule  string
+		count int
+		last  time.Time
+		err   error
+	}{
+		{"count", "FREQ=WEEKLY;COUNT=3", 3, time.Date(2024, 3, 18, 10, 0, 0, 0, newYork), nil},
+		{"prefix and until", "RRULE:FREQ=DAILY;UNTIL=20240306T235959Z", 3, time.Date(2024, 3, 6, 10, 0, 0, 0, newYork), nil},
+		{"ends with the course", "FREQ=WEEKLY;BYDAY=MO,WE", 8, time.Date(2024, 3, 27, 10, 0, 0, 0, newYork), nil},
+		{"count over the limit", "FREQ=DAILY;COUNT=400", 0, time.Time{}, session.ErrTooManySessions},
+		{"too many until the end", "FREQ=HOURLY", 0, time.Time{}, session.ErrTooManySessions},
+		{"invalid", "FREQ=SOMETIMES", 0, time.Time{}, session.ErrInvalidRRule},
+		{"empty", "FREQ=DAILY;UNTIL=20240301T000000Z", 0, time.Time{}, session.ErrEmptyRRule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			starts, err := session.Expand(start, tt.rule, until)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expand = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if len(starts) != tt.count || !starts[len(starts)-1].Equal(tt.last) {
+				t.Errorf("Expand = %d sessions ending %s, want %d ending %s", len(starts), starts[len(starts)-1], tt.count, tt.last)
+			}
+			// El horario de verano empieza el 10 de marzo: la hora local no cambia
+			for _, s := range starts {
+				if s.In(newYork).Hour() != 10 {
+					t.Errorf("session at %s, want 10:00 local", s.In(newYork))
+				}
+			}
+		})
+	}
+}
+
+func TestWithin(t *testing.T) {
+	startDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		startsAt time.Time
+		duration int
+		timezone string
+		want     bool
+	}{
+		{"first day", time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC), 60, "UTC", true},
+		{"ends at midnight of the last day", time.Date(2024, 6, 30, 23, 0, 0, 0, time.UTC), 60, "UTC", true},
+		{"ends the next day", time.Date(2024, 6, 30, 23, 30, 0, 0, time.UTC), 60, "UTC", false},
+		// 31/5 22:00 en Buenos Aires es 1/6 01:00 UTC: cuenta la fecha local de la sesión
+		{"local date before the course", time.Date(2024, 6, 1, 1, 0, 0, 0, time.UTC), 60, "America/Argentina/Buenos_Aires", false},
+		{"local date on the last day", time.Date(2024, 7, 1, 1, 0, 0, 0, time.UTC), 60, "America/Argentina/Buenos_Aires", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := session.Session{StartsAt: tt.startsAt, Duration: tt.duration, Timezone: tt.timezone}
+			if got := s.Within(startDate, endDate); got != tt.want {
+				t.Errorf("Within = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateSeries(t *testing.T) {
+	db := tenanttest.NewDB(t, &course.TenantCourse{}, &course.CourseTimezone{}, &session.Session{})
+	ctx, _ := tenanttest.Contexts()
+	c := domain.Course{
+		ID:        "0b6f3c1e-1d2a-4e5f-9a8b-7c6d5e4f3a2b",
+		Name:      "Go basics",
+		StartDate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	if err := db.WithContext(ctx).Create(&course.TenantCourse{Course: c}).Error; err != nil {
+		t.Fatal(err)
+	}
+	svc := session.NewService(tenanttest.Logger(), session.NewRepo(db, tenanttest.Logger()))
+
+	// Sin COUNT ni UNTIL la serie termina con el curso: los lunes de junio
+	sessions, err := svc.Create(ctx, c.ID, session.Input{Start: "2024-06-03T10:00", Duration: 90, RRule: "FREQ=WEEKLY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 4 || sessions[0].SeriesID == nil || *sessions[0].SeriesID != *sessions[3].SeriesID {
+		t.Fatalf("sessions = %+v, want 4 in the same series", sessions)
+	}
+
+	// Una serie que se pasa del curso no crea ninguna sesión
+	if _, err := svc.Create(ctx, c.ID, session.Input{Start: "2024-06-24T10:00", Duration: 90, RRule: "FREQ=WEEKLY;COUNT=3"}); !errors.Is(err, session.ErrOutsideCourse) {
+		t.Errorf("Create past the course = %v, want %v", err, session.ErrOutsideCourse)
+	}
+	if count, err := svc.Count(ctx, c.ID, session.Filters{}); err != nil || count != 4 {
+		t.Errorf("sessions = %d, %v; want 4", count, err)
+	}
+
+	deleted, err := svc.Delete(ctx, c.ID, sessions[1].ID, true)
+	if err != nil || deleted != 4 {
+		t.Errorf("Delete series = %d, %v; want 4", deleted, err)
+	}
+}
@@ -0,0 +1,79 @@
+package session_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/session"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	db := tenanttest.NewDB(t, &course.TenantCourse{}, &course.CourseTimezone{}, &session.Session{})
+
+	ctxA, ctxB := tenanttest.Contexts()
+
+	courseA := domain.Course{
+		ID:        "0b6f3c1e-1d2a-4e5f-9a8b-7c6d5e4f3a2b",
+		Name:      "Go basics",
+		StartDate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+	if err := db.WithContext(ctxA).Create(&course.TenantCourse{Course: courseA}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	repo := session.NewRepo(db, tenanttest.Logger())
+	svc := session.NewService(tenanttest.Logger(), repo)
+	sessions, err := svc.Create(ctxA, courseA.ID, session.Input{
+		Start:    "2024-06-03T10:00:00Z",
+		Duration: 90,
+		RRule:    "FREQ=WEEKLY;COUNT=2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := sessions[0].ID
+
+	tenanttest.AssertStamped(t, db, "course_sessions")
+
+	// A través del servicio el curso de otro tenant no existe
+	if _, err := svc.Get(ctxB, courseA.ID, id); !errors.Is(err, session.ErrCourseNotFound) {
+		t.Errorf("Get = %v, want %v", err, session.ErrCourseNotFound)
+	}
+	if _, err := svc.GetAll(ctxB, courseA.ID, session.Filters{}, 0, 10); !errors.Is(err, session.ErrCourseNotFound) {
+		t.Errorf("GetAll = %v, want %v", err, session.ErrCourseNotFound)
+	}
+	location := "Room 1"
+	if _, err := svc.Update(ctxB, courseA.ID, id, session.UpdateInput{Location: &location}); !errors.Is(err, session.ErrCourseNotFound) {
+		t.Errorf("Update = %v, want %v", err, session.ErrCourseNotFound)
+	}
+	if _, err := svc.Delete(ctxB, courseA.ID, id, true); !errors.Is(err, session.ErrCourseNotFound) {
+		t.Errorf("Delete = %v, want %v", err, session.ErrCourseNotFound)
+	}
+
+	// y en el repositorio la tabla se filtra sola
+	if _, err := repo.Get(ctxB, courseA.ID, id); !errors.Is(err, session.ErrNotFoundBase) {
+		t.Errorf("repo.Get = %v, want not found", err)
+	}
+	found, err := repo.GetAll(ctxB, courseA.ID, session.Filters{}, 0, 10)
+	if err != nil || len(found) != 0 {
+		t.Errorf("repo.GetAll = %d sessions, %v; want none", len(found), err)
+	}
+	if count, err := repo.Count(ctxB, courseA.ID, session.Filters{}); err != nil || count != 0 {
+		t.Errorf("repo.Count = %d, %v; want 0", count, err)
+	}
+	if err := repo.Delete(ctxB, courseA.ID, id); !errors.Is(err, session.ErrNotFoundBase) {
+		t.Errorf("repo.Delete = %v, want not found", err)
+	}
+	if deleted, err := repo.DeleteSeries(ctxB, courseA.ID, *sessions[0].SeriesID); err != nil || deleted != 0 {
+		t.Errorf("repo.DeleteSeries = %d, %v; want 0", deleted, err)
+	}
+
+	if count, err := svc.Count(ctxA, courseA.ID, session.Filters{}); err != nil || count != 2 {
+		t.Errorf("Count of the owner = %d, %v; want 2", count, err)
+	}
+}
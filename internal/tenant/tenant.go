@@ -0,0 +1,115 @@
+// Package tenant aísla los datos por organización: el tenant viaja en el contexto
+// y un callback de GORM lo agrega como condición a las consultas de las tablas
+// registradas, así ningún repositorio puede olvidarse de filtrarlo
+package tenant
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type contextKey string
+
+const (
+	tenantKey contextKey = "tenant_id"
+
+	// Column es la columna que identifica al tenant en las tablas aisladas
+	Column = "tenant_id"
+)
+
+var (
+	ErrInvalidTenant = errors.New("tenant ID must have up to 64 letters, digits, '-' or '_'")
+
+	validTenant = regexp.MustCompile(`^[A-Za-z0-9_-]{0,64}$`)
+)
+
+// WithTenant fija el tenant de la operación; el vacío es el tenant por defecto
+// de los despliegues sin multi-tenancy
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenantID)
+}
+
+// FromContext devuelve el tenant y si el contexto tiene uno. Sin tenant (procesos
+// en segundo plano) las consultas no se filtran.
+func FromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantKey).(string)
+	return tenantID, ok
+}
+
+// Validate rechaza los IDs que no se pueden guardar en la columna
+func Validate(tenantID string) error {
+	if !validTenant.MatchString(tenantID) {
+		return ErrInvalidTenant
+	}
+	return nil
+}
+
+// Scope filtra por tenant una tabla que no es la principal de la consulta (ej: en un JOIN)
+func Scope(ctx context.Context, table string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		tenantID, ok := FromContext(ctx)
+		if !ok {
+			return tx
+		}
+		return tx.Where(condition(table, tenantID))
+	}
+}
+
+// Register agrega la condición del tenant a los SELECT, UPDATE y DELETE cuya
+// tabla principal está en tables y, en los INSERT, guarda el tenant del contexto
+// en el campo TenantID del modelo.
+func Register(db *gorm.DB, tables ...string) error {
+	scope := func(tx *gorm.DB) {
+		if tx.Statement.Context == nil || !slices.Contains(tables, tx.Statement.Table) {
+			return
+		}
+		if tenantID, ok := FromContext(tx.Statement.Context); ok {
+			tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{condition(tx.Statement.Table, tenantID)}})
+		}
+	}
+	stamp := func(tx *gorm.DB) {
+		if tx.Statement.Context == nil || tx.Statement.Schema == nil || !slices.Contains(tables, tx.Statement.Table) {
+			return
+		}
+		tenantID, ok := FromContext(tx.Statement.Context)
+		field := tx.Statement.Schema.LookUpField(Column)
+		if !ok || field == nil {
+			return
+		}
+		// 🔧 Una fila o un lote: cada elemento queda en el tenant de la operación
+		value := reflect.Indirect(tx.Statement.ReflectValue)
+		switch value.Kind() {
+		case reflect.Struct:
+			tx.AddError(field.Set(tx.Statement.Context, value, tenantID))
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < value.Len(); i++ {
+				tx.AddError(field.Set(tx.Statement.Context, reflect.Indirect(value.Index(i)), tenantID))
+			}
+		}
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("tenant:create", stamp); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scope); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scope); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scope); err != nil {
+		return err
+	}
+	return callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scope)
+}
+
+func condition(table, tenantID string) clause.Expression {
+	return clause.Eq{Column: clause.Column{Table: table, Name: Column}, Value: tenantID}
+}
@@ -0,0 +1,73 @@
+// Package tenanttest arma lo que comparten las pruebas de aislamiento por tenant:
+// una base SQLite con el filtro registrado como en producción y dos organizaciones
+package tenanttest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"path/filepath"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/NicoJCastro/gocourse_course/pkg/bootstrap"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Los dos tenants de las pruebas: A es el dueño de los datos y B intenta verlos
+const (
+	A = "tenant-a"
+	B = "tenant-b"
+)
+
+// NewDB abre una base SQLite temporal con las tablas de models y el callback de
+// tenant sobre bootstrap.TenantTables, igual que bootstrap.DBConnection
+func NewDB(t testing.TB, models ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tenant.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tenant.Register(db, bootstrap.TenantTables...); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// Contexts devuelve los contextos de los tenants A y B
+func Contexts() (ctxA, ctxB context.Context) {
+	return tenant.WithTenant(context.Background(), A), tenant.WithTenant(context.Background(), B)
+}
+
+// Logger descarta los logs de los repositorios y servicios
+func Logger() *log.Logger {
+	return log.New(io.Discard, "", 0)
+}
+
+// AssertNotFound falla si la operación de otro tenant no devolvió target
+func AssertNotFound(t testing.TB, op string, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Errorf("%s from another tenant = %v, want %v", op, err, target)
+	}
+}
+
+// AssertStamped falla si alguna fila de tables no quedó con el tenant A
+func AssertStamped(t testing.TB, db *gorm.DB, tables ...string) {
+	t.Helper()
+	for _, table := range tables {
+		var tenants []string
+		if err := db.Table(table).Distinct().Pluck(tenant.Column, &tenants).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(tenants) != 1 || tenants[0] != A {
+			t.Errorf("%s tenants = %v, want [%s]", table, tenants, A)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+type (
+	// DispatcherConfig controla los reintentos de los envíos
+	DispatcherConfig struct {
+		// Interval entre búsquedas de envíos vencidos
+		Interval time.Duration
+		// BatchSize es la cantidad de envíos tomados por búsqueda
+		BatchSize int
+		// MaxAttempts antes de pasar el envío a dead letter
+		MaxAttempts int
+		// BaseBackoff se duplica en cada intento hasta MaxBackoff
+		BaseBackoff time.Duration
+		MaxBackoff  time.Duration
+		// Timeout de cada POST al destino
+		Timeout time.Duration
+		// AllowPrivateNetworks permite destinos en loopback o redes privadas (sólo desarrollo)
+		AllowPrivateNetworks bool
+	}
+
+	// Dispatcher envía los webhooks pendientes firmados con HMAC-SHA256
+	Dispatcher struct {
+		log    *log.Logger
+		repo   Repository
+		client *http.Client
+		config DispatcherConfig
+	}
+)
+
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// DefaultDispatcherConfig reintenta durante ~1 día: 30s, 1m, 2m ... hasta 6h
+func DefaultDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		Interval:    5 * time.Second,
+		BatchSize:   50,
+		MaxAttempts: 10,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  6 * time.Hour,
+		Timeout:     10 * time.Second,
+	}
+}
+
+func NewDispatcher(logger *log.Logger, repo Repository, config DispatcherConfig) *Dispatcher {
+	return &Dispatcher{
+		log:    logger,
+		repo:   repo,
+		client: &http.Client{Timeout: config.Timeout, Transport: newTransport(config.AllowPrivateNetworks)},
+		config: config,
+	}
+}
+
+// Sign calcula la firma de un envío: hex(HMAC-SHA256(secret, "<timestamp>.<body>")).
+// El header X-Webhook-Signature tiene el formato "t=<timestamp>,v1=<firma>"; el
+// destino debe recalcularla y rechazar timestamps viejos para evitar replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run bloquea hasta que se cancele ctx; conviene lanzarlo en una goroutine
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		d.DispatchDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue envía los webhooks vencidos y devuelve cuántos procesó
+func (d *Dispatcher) DispatchDue(ctx context.Context) int {
+	now := time.Now().UTC()
+	// El claim dura más que el timeout para que nadie más tome el envío en curso
+	deliveries, err := d.repo.ClaimDue(ctx, now, now.Add(2*d.config.Timeout), d.config.BatchSize)
+	if err != nil {
+		d.log.Println("error claiming webhook deliveries: ", err)
+		return 0
+	}
+
+	for i := range deliveries {
+		d.dispatch(ctx, &deliveries[i])
+	}
+	return len(deliveries)
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, delivery *Delivery) {
+	sub, err := d.repo.Get(ctx, delivery.SubscriptionID)
+	if err != nil {
+		// La suscripción se eliminó: no tiene sentido seguir reintentando
+		d.fail(ctx, delivery, 0, err, true)
+		return
+	}
+
+	statusCode, err := d.send(ctx, sub, delivery)
+	if err != nil {
+		d.fail(ctx, delivery, statusCode, err, false)
+		return
+	}
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.Status = StatusSucceeded
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+	delivery.DeliveredAt = &now
+	if err := d.repo.SaveDelivery(ctx, delivery); err != nil {
+		d.log.Printf("error saving webhook delivery %s: %v\n", delivery.ID, err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub *Subscription, delivery *Delivery) (int, error) {
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gocourse-webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderSignature, fmt.Sprintf("t=%d,v1=%s", timestamp, Sign(sub.Secret, timestamp, delivery.Payload)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// fail registra el intento fallido y programa el reintento con backoff exponencial
+// y jitter, o pasa el envío a dead letter si se agotaron los intentos
+func (d *Dispatcher) fail(ctx context.Context, delivery *Delivery, statusCode int, cause error, dead bool) {
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = truncate(cause.Error(), 255)
+
+	if dead || delivery.Attempts >= d.config.MaxAttempts {
+		d.log.Printf("webhook delivery %s moved to dead letter: %v\n", delivery.ID, cause)
+		delivery.Status = StatusDead
+	} else {
+		delivery.NextAttemptAt = time.Now().UTC().Add(d.backoff(delivery.Attempts))
+	}
+
+	if err := d.repo.SaveDelivery(ctx, delivery); err != nil {
+		d.log.Printf("error saving webhook delivery %s: %v\n", delivery.ID, err)
+	}
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	backoff := d.config.BaseBackoff
+	for i := 1; i < attempts && backoff < d.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > d.config.MaxBackoff {
+		backoff = d.config.MaxBackoff
+	}
+	// Jitter de ±20% para no sincronizar los reintentos de muchos envíos
+	jitter := time.Duration(rand.Int64N(int64(backoff)/5+1)) * 2
+	return backoff - backoff/5 + jitter
+}
+
+// truncate corta s a max caracteres sin partir una runa: la columna es varchar(max),
+// que en utf8mb4 cuenta caracteres y rechaza una secuencia UTF-8 incompleta
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
@@ -0,0 +1,29 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"short", "timeout", "timeout"},
+		{"ascii", strings.Repeat("a", 300), strings.Repeat("a", 255)},
+		// 254 bytes de ASCII y una ñ de 2 bytes: cortar en el byte 255 la partiría
+		{"rune on the boundary", strings.Repeat("a", 254) + "ñandú", strings.Repeat("a", 254) + "ñ"},
+		{"multibyte", strings.Repeat("ü", 300), strings.Repeat("ü", 255)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.value, 255)
+			if got != tt.want || !utf8.ValidString(got) {
+				t.Errorf("truncate = %q (%d runes), want %q", got, utf8.RuneCountInString(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error es un error de la API con un código estable, el que reciben los clientes
+type Error struct {
+	code    string
+	message string
+}
+
+func newError(code, message string) *Error {
+	return &Error{code: code, message: message}
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Code() string {
+	return e.code
+}
+
+var ErrInvalidRequestType = newError("invalid_request_type", "invalid request type")
+var ErrIDRequired = newError("id_required", "id is required")
+var ErrURLRequired = newError("url_required", "url is required")
+var ErrInvalidURL = newError("invalid_url", "url must be an absolute http or https URL")
+var ErrURLNotAllowed = newError("url_not_allowed", "url must resolve to a public address")
+var ErrURLUnresolvable = newError("url_unresolvable", "url host could not be resolved")
+var ErrEventTypesRequired = newError("event_types_required", "event_types is required")
+var ErrInvalidEventType = newError("invalid_event_type", "invalid event type")
+var ErrAtLeastOneFieldRequired = newError("at_least_one_field_required", "at least one field is required")
+var ErrDeliveryNotDead = newError("delivery_not_dead", "only dead deliveries can be retried")
+var ErrFailedToCreateSubscription = newError("failed_to_create_subscription", "failed to create webhook subscription")
+var ErrFailedToGetSubscription = newError("failed_to_get_subscription", "failed to get webhook subscription")
+var ErrFailedToUpdateSubscription = newError("failed_to_update_subscription", "failed to update webhook subscription")
+var ErrFailedToDeleteSubscription = newError("failed_to_delete_subscription", "failed to delete webhook subscription")
+var ErrFailedToGetDeliveries = newError("failed_to_get_deliveries", "failed to get webhook deliveries")
+var ErrFailedToEnqueueDeliveries = newError("failed_to_enqueue_deliveries", "failed to enqueue webhook deliveries")
+var ErrFailedToGenerateMetadata = newError("metadata_error", "error generating metadata")
+
+// ErrNotFound indica que no existe la suscripción o el envío pedido
+type ErrNotFound struct {
+	Resource string
+	ID       string
+}
+
+func (e *ErrNotFound) Error() string {
+	return fmt.Sprintf("%s with ID %s not found", e.Resource, e.ID)
+}
+
+// Code es "webhook_subscription_not_found" o "webhook_delivery_not_found"
+func (e *ErrNotFound) Code() string {
+	return strings.ReplaceAll(e.Resource, " ", "_") + "_not_found"
+}
+
+func (e *ErrNotFound) Args() []any {
+	return []any{e.ID}
+}
+
+func (e *ErrNotFound) Unwrap() error {
+	return ErrNotFoundBase
+}
+
+func NewErrNotFound(resource, id string) *ErrNotFound {
+	return &ErrNotFound{Resource: resource, ID: id}
+}
+
+var ErrNotFoundBase = errors.New("webhook resource not found")
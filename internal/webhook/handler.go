@@ -0,0 +1,247 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NicoJCastro/go_lib_response/response"
+	"github.com/NicoJCastro/gocourse_course/pkg/apierror"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/NicoJCastro/gocourse_meta/meta"
+)
+
+type (
+	Controller func(ctx context.Context, request interface{}) (interface{}, error)
+
+	Endpoint struct {
+		Create     Controller
+		Get        Controller
+		GetAll     Controller
+		Update     Controller
+		Delete     Controller
+		Deliveries Controller
+		Retry      Controller
+	}
+
+	CreateReq struct {
+		URL        string   `json:"url"`
+		EventTypes []string `json:"event_types"`
+		Secret     string   `json:"secret"`
+	}
+
+	GetReq struct {
+		ID string `json:"id"`
+	}
+
+	GetAllReq struct {
+		Limit int `json:"limit"`
+		Page  int `json:"page"`
+	}
+
+	UpdateReq struct {
+		ID         string    `json:"id"`
+		URL        *string   `json:"url"`
+		EventTypes *[]string `json:"event_types"`
+		Secret     *string   `json:"secret"`
+		Active     *bool     `json:"active"`
+	}
+
+	DeleteReq struct {
+		ID string `json:"id"`
+	}
+
+	DeliveriesReq struct {
+		ID    string `json:"id"`
+		Limit int    `json:"limit"`
+		Page  int    `json:"page"`
+	}
+
+	RetryReq struct {
+		ID         string `json:"id"`
+		DeliveryID string `json:"delivery_id"`
+	}
+
+	// CreatedSubscription incluye el secreto, que sólo se muestra al crear la suscripción
+	CreatedSubscription struct {
+		*Subscription
+		Secret string `json:"secret"`
+	}
+
+	Config struct {
+		Pagination config.Pagination
+	}
+)
+
+func MakeEndpoint(s Service, config Config) Endpoint {
+	return Endpoint{
+		Create:     makeCreateEndpoint(s),
+		Get:        makeGetEndpoint(s),
+		GetAll:     makeGetAllEndpoint(s, config),
+		Update:     makeUpdateEndpoint(s),
+		Delete:     makeDeleteEndpoint(s),
+		Deliveries: makeDeliveriesEndpoint(s, config),
+		Retry:      makeRetryEndpoint(s),
+	}
+}
+
+func makeCreateEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(CreateReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		sub, err := s.Create(ctx, req.URL, req.EventTypes, req.Secret)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.Created("Webhook subscription created successfully", CreatedSubscription{Subscription: sub, Secret: sub.Secret}, nil), nil
+	}
+}
+
+func makeGetEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		sub, err := s.Get(ctx, req.ID)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Webhook subscription retrieved successfully", sub, nil), nil
+	}
+}
+
+func makeGetAllEndpoint(s Service, config Config) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetAllReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+
+		count, err := s.Count(ctx)
+		if err != nil {
+			return nil, apierror.InternalServerError(err)
+		}
+		metaData, err := newMeta(req.Page, req.Limit, count, config)
+		if err != nil {
+			return nil, apierror.InternalServerError(fmt.Errorf("%w: %w", ErrFailedToGenerateMetadata, err))
+		}
+
+		subs, err := s.GetAll(ctx, metaData.Offset(), metaData.Limit())
+		if err != nil {
+			return nil, apierror.InternalServerError(err)
+		}
+		return response.OK("Webhook subscriptions retrieved successfully", subs, metaData), nil
+	}
+}
+
+func makeUpdateEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(UpdateReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if req.URL == nil && req.EventTypes == nil && req.Secret == nil && req.Active == nil {
+			return nil, apierror.BadRequest(ErrAtLeastOneFieldRequired)
+		}
+
+		var eventTypes []string
+		if req.EventTypes != nil {
+			// Una lista vacía explícita es inválida, no "sin cambios"
+			eventTypes = append([]string{}, *req.EventTypes...)
+		}
+
+		sub, err := s.Update(ctx, req.ID, req.URL, eventTypes, req.Secret, req.Active)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Webhook subscription updated successfully", sub, nil), nil
+	}
+}
+
+func makeDeleteEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(DeleteReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		if err := s.Delete(ctx, req.ID); err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Webhook subscription deleted successfully", nil, nil), nil
+	}
+}
+
+func makeDeliveriesEndpoint(s Service, config Config) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(DeliveriesReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+
+		count, err := s.CountDeliveries(ctx, req.ID)
+		if err != nil {
+			return nil, apierror.InternalServerError(err)
+		}
+		metaData, err := newMeta(req.Page, req.Limit, count, config)
+		if err != nil {
+			return nil, apierror.InternalServerError(fmt.Errorf("%w: %w", ErrFailedToGenerateMetadata, err))
+		}
+
+		deliveries, err := s.Deliveries(ctx, req.ID, metaData.Offset(), metaData.Limit())
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.OK("Webhook deliveries retrieved successfully", deliveries, metaData), nil
+	}
+}
+
+func makeRetryEndpoint(s Service) Controller {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(RetryReq)
+		if !ok {
+			return nil, apierror.BadRequest(ErrInvalidRequestType)
+		}
+		if req.ID == "" || req.DeliveryID == "" {
+			return nil, apierror.BadRequest(ErrIDRequired)
+		}
+		delivery, err := s.RetryDelivery(ctx, req.ID, req.DeliveryID)
+		if err != nil {
+			return nil, errorResponse(err)
+		}
+		return response.Accepted("Webhook delivery scheduled for retry", delivery, nil), nil
+	}
+}
+
+func newMeta(page, limit int, count int64, config Config) (*meta.Meta, error) {
+	return config.Pagination.Meta(page, limit, int(count))
+}
+
+// errorResponse traduce los errores del servicio a respuestas HTTP
+func errorResponse(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFoundBase):
+		return apierror.NotFound(err)
+	case errors.Is(err, ErrURLRequired), errors.Is(err, ErrInvalidURL),
+		errors.Is(err, ErrURLNotAllowed), errors.Is(err, ErrURLUnresolvable),
+		errors.Is(err, ErrEventTypesRequired), errors.Is(err, ErrInvalidEventType):
+		return apierror.BadRequest(err)
+	case errors.Is(err, ErrDeliveryNotDead):
+		return apierror.Conflict(err)
+	}
+	return apierror.InternalServerError(err)
+}
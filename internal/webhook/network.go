@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// sharedAddressSpace es 100.64.0.0/10 (CGNAT), que netip no considera privada
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// publicAddr indica si el servicio puede enviar webhooks a addr: nada de loopback,
+// redes privadas, link-local (ej: 169.254.169.254, la metadata de la nube) ni multicast
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsUnspecified() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// checkHost resuelve host y lo rechaza si alguna de sus direcciones no es pública
+func checkHost(ctx context.Context, resolver *net.Resolver, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !publicAddr(addr) {
+			return ErrURLNotAllowed
+		}
+		return nil
+	}
+
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: %s", ErrURLUnresolvable, host)
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return ErrURLNotAllowed
+		}
+	}
+	return nil
+}
+
+// newTransport vuelve a revisar la dirección al conectar: el DNS pudo cambiar desde
+// que se creó la suscripción (DNS rebinding) y las redirecciones también pasan por acá
+func newTransport(allowPrivate bool) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if allowPrivate {
+				return nil
+			}
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || !publicAddr(addr) {
+				return fmt.Errorf("%w: %s", ErrURLNotAllowed, address)
+			}
+			return nil
+		},
+	}
+	return &http.Transport{
+		// 🔧 Sin proxy: la dirección que se revisa tiene que ser la del destino
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       90 * time.Second,
+	}
+}
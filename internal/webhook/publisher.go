@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/NicoJCastro/gocourse_course/internal/outbox"
+)
+
+// Publisher adapta el servicio de webhooks a outbox.Publisher: el relay del outbox
+// entrega cada evento y acá se transforma en envíos pendientes por suscripción
+type Publisher struct {
+	service Service
+}
+
+func NewPublisher(service Service) *Publisher {
+	return &Publisher{service: service}
+}
+
+func (p *Publisher) Publish(ctx context.Context, msg outbox.Message) error {
+	return p.service.Enqueue(ctx, msg)
+}
+
+func (p *Publisher) Close() error {
+	return nil
+}
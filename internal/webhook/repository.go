@@ -0,0 +1,203 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	Repository interface {
+		Create(ctx context.Context, sub *Subscription) error
+		Get(ctx context.Context, id string) (*Subscription, error)
+		GetAll(ctx context.Context, offset, limit int) ([]Subscription, error)
+		Count(ctx context.Context) (int64, error)
+		Save(ctx context.Context, sub *Subscription) error
+		Delete(ctx context.Context, id string) error
+		GetActive(ctx context.Context) ([]Subscription, error)
+
+		// CreateDeliveries ignora los envíos ya existentes para el mismo (suscripción, evento),
+		// así un evento republicado por el outbox no se entrega dos veces
+		CreateDeliveries(ctx context.Context, deliveries []*Delivery) error
+		GetDelivery(ctx context.Context, subscriptionID, id string) (*Delivery, error)
+		GetDeliveries(ctx context.Context, subscriptionID string, offset, limit int) ([]Delivery, error)
+		CountDeliveries(ctx context.Context, subscriptionID string) (int64, error)
+		// ClaimDue toma los envíos pendientes vencidos y corre su próximo intento a
+		// claimUntil para que otra instancia no los tome mientras se envían
+		ClaimDue(ctx context.Context, now, claimUntil time.Time, limit int) ([]Delivery, error)
+		SaveDelivery(ctx context.Context, delivery *Delivery) error
+	}
+
+	repo struct {
+		db  *gorm.DB
+		log *log.Logger
+	}
+)
+
+func NewRepo(db *gorm.DB, logger *log.Logger) Repository {
+	return &repo{
+		db:  db,
+		log: logger,
+	}
+}
+
+func (r *repo) Create(ctx context.Context, sub *Subscription) error {
+	if err := r.db.WithContext(ctx).Create(sub).Error; err != nil {
+		r.log.Printf("error: %v", err)
+		return err
+	}
+	r.log.Println("webhook subscription created with id: ", sub.ID)
+	return nil
+}
+
+func (r *repo) Get(ctx context.Context, id string) (*Subscription, error) {
+	sub := Subscription{ID: id}
+	if err := r.db.WithContext(ctx).First(&sub).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewErrNotFound("webhook subscription", id)
+		}
+		r.log.Println("Error getting webhook subscription: ", err)
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *repo) GetAll(ctx context.Context, offset, limit int) ([]Subscription, error) {
+	var subs []Subscription
+	result := r.db.WithContext(ctx).Order("created_at desc").Limit(limit).Offset(offset).Find(&subs)
+	if result.Error != nil {
+		r.log.Println("Error getting webhook subscriptions: ", result.Error)
+		return nil, result.Error
+	}
+	return subs, nil
+}
+
+func (r *repo) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&Subscription{}).Count(&count).Error; err != nil {
+		r.log.Println("Error counting webhook subscriptions: ", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *repo) Save(ctx context.Context, sub *Subscription) error {
+	if err := r.db.WithContext(ctx).Save(sub).Error; err != nil {
+		r.log.Println("Error saving webhook subscription: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&Subscription{ID: id})
+	if result.Error != nil {
+		r.log.Println("Error deleting webhook subscription: ", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return NewErrNotFound("webhook subscription", id)
+	}
+	return nil
+}
+
+func (r *repo) GetActive(ctx context.Context) ([]Subscription, error) {
+	var subs []Subscription
+	if err := r.db.WithContext(ctx).Where("active = ?", true).Find(&subs).Error; err != nil {
+		r.log.Println("Error getting active webhook subscriptions: ", err)
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *repo) CreateDeliveries(ctx context.Context, deliveries []*Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(deliveries, 100).Error
+	if err != nil {
+		r.log.Println("Error creating webhook deliveries: ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repo) GetDelivery(ctx context.Context, subscriptionID, id string) (*Delivery, error) {
+	var delivery Delivery
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND subscription_id = ?", id, subscriptionID).
+		First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewErrNotFound("webhook delivery", id)
+		}
+		r.log.Println("Error getting webhook delivery: ", err)
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *repo) GetDeliveries(ctx context.Context, subscriptionID string, offset, limit int) ([]Delivery, error) {
+	var deliveries []Delivery
+	result := r.db.WithContext(ctx).
+		Where("subscription_id = ?", subscriptionID).
+		Order("created_at desc").
+		Limit(limit).Offset(offset).
+		Find(&deliveries)
+	if result.Error != nil {
+		r.log.Println("Error getting webhook deliveries: ", result.Error)
+		return nil, result.Error
+	}
+	return deliveries, nil
+}
+
+func (r *repo) CountDeliveries(ctx context.Context, subscriptionID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&Delivery{}).
+		Where("subscription_id = ?", subscriptionID).
+		Count(&count).Error
+	if err != nil {
+		r.log.Println("Error counting webhook deliveries: ", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *repo) ClaimDue(ctx context.Context, now, claimUntil time.Time, limit int) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", StatusPending, now).
+			Order("next_attempt_at asc").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(deliveries))
+		for i, d := range deliveries {
+			ids[i] = d.ID
+		}
+		return tx.Model(&Delivery{}).Where("id IN ?", ids).Update("next_attempt_at", claimUntil).Error
+	})
+	if err != nil {
+		r.log.Println("Error claiming webhook deliveries: ", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *repo) SaveDelivery(ctx context.Context, delivery *Delivery) error {
+	if err := r.db.WithContext(ctx).Save(delivery).Error; err != nil {
+		r.log.Println("Error saving webhook delivery: ", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/outbox"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+)
+
+type (
+	Service interface {
+		Create(ctx context.Context, rawURL string, eventTypes []string, secret string) (*Subscription, error)
+		Get(ctx context.Context, id string) (*Subscription, error)
+		GetAll(ctx context.Context, offset, limit int) ([]Subscription, error)
+		Count(ctx context.Context) (int64, error)
+		Update(ctx context.Context, id string, rawURL *string, eventTypes []string, secret *string, active *bool) (*Subscription, error)
+		Delete(ctx context.Context, id string) error
+		Deliveries(ctx context.Context, subscriptionID string, offset, limit int) ([]Delivery, error)
+		CountDeliveries(ctx context.Context, subscriptionID string) (int64, error)
+		RetryDelivery(ctx context.Context, subscriptionID, deliveryID string) (*Delivery, error)
+		// Enqueue crea un envío por cada suscripción activa interesada en el evento
+		Enqueue(ctx context.Context, msg outbox.Message) error
+	}
+
+	// Envelope es el cuerpo JSON que recibe el destino del webhook
+	Envelope struct {
+		ID          string          `json:"id"`
+		Type        string          `json:"type"`
+		AggregateID string          `json:"aggregate_id"`
+		CreatedAt   time.Time       `json:"created_at"`
+		Data        json.RawMessage `json:"data"`
+	}
+
+	service struct {
+		log          *log.Logger
+		repo         Repository
+		eventTypes   map[string]bool
+		allowPrivate bool
+		resolver     *net.Resolver
+	}
+)
+
+// NewService recibe los tipos de evento a los que se puede suscribir. Sin allowPrivate
+// (sólo para desarrollo) las URLs tienen que resolver a direcciones públicas.
+func NewService(log *log.Logger, repo Repository, eventTypes []string, allowPrivate bool) Service {
+	allowed := make(map[string]bool, len(eventTypes)+1)
+	allowed[AllEvents] = true
+	for _, e := range eventTypes {
+		allowed[e] = true
+	}
+	return &service{
+		log:          log,
+		repo:         repo,
+		eventTypes:   allowed,
+		allowPrivate: allowPrivate,
+		resolver:     net.DefaultResolver,
+	}
+}
+
+func (s *service) Create(ctx context.Context, rawURL string, eventTypes []string, secret string) (*Subscription, error) {
+	s.log.Println("---- Creating webhook subscription ----")
+
+	if err := s.validateURL(ctx, rawURL); err != nil {
+		return nil, err
+	}
+	if err := s.validateEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+
+	// 🔧 Si el cliente no envía un secreto generamos uno; sólo se devuelve en el alta
+	if secret == "" {
+		var err error
+		if secret, err = newSecret(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrFailedToCreateSubscription, err)
+		}
+	}
+
+	sub := &Subscription{
+		URL:    rawURL,
+		Secret: secret,
+		Events: eventTypes,
+		Active: true,
+	}
+	if err := s.repo.Create(ctx, sub); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToCreateSubscription, err)
+	}
+	return sub, nil
+}
+
+func (s *service) Get(ctx context.Context, id string) (*Subscription, error) {
+	sub, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFoundBase) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetSubscription, err)
+	}
+	return sub, nil
+}
+
+func (s *service) GetAll(ctx context.Context, offset, limit int) ([]Subscription, error) {
+	subs, err := s.repo.GetAll(ctx, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetSubscription, err)
+	}
+	return subs, nil
+}
+
+func (s *service) Count(ctx context.Context) (int64, error) {
+	count, err := s.repo.Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrFailedToGetSubscription, err)
+	}
+	return count, nil
+}
+
+func (s *service) Update(ctx context.Context, id string, rawURL *string, eventTypes []string, secret *string, active *bool) (*Subscription, error) {
+	s.log.Println("---- Updating webhook subscription ----")
+
+	sub, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if rawURL != nil {
+		if err := s.validateURL(ctx, *rawURL); err != nil {
+			return nil, err
+		}
+		sub.URL = *rawURL
+	}
+	if eventTypes != nil {
+		if err := s.validateEventTypes(eventTypes); err != nil {
+			return nil, err
+		}
+		sub.Events = eventTypes
+	}
+	if secret != nil && *secret != "" {
+		sub.Secret = *secret
+	}
+	if active != nil {
+		sub.Active = *active
+	}
+
+	if err := s.repo.Save(ctx, sub); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToUpdateSubscription, err)
+	}
+	return sub, nil
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	s.log.Println("---- Deleting webhook subscription ----")
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, ErrNotFoundBase) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrFailedToDeleteSubscription, err)
+	}
+	return nil
+}
+
+func (s *service) Deliveries(ctx context.Context, subscriptionID string, offset, limit int) ([]Delivery, error) {
+	if _, err := s.Get(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.repo.GetDeliveries(ctx, subscriptionID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetDeliveries, err)
+	}
+	return deliveries, nil
+}
+
+func (s *service) CountDeliveries(ctx context.Context, subscriptionID string) (int64, error) {
+	count, err := s.repo.CountDeliveries(ctx, subscriptionID)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrFailedToGetDeliveries, err)
+	}
+	return count, nil
+}
+
+// RetryDelivery vuelve a encolar un envío que quedó en dead letter
+func (s *service) RetryDelivery(ctx context.Context, subscriptionID, deliveryID string) (*Delivery, error) {
+	s.log.Println("---- Retrying webhook delivery ----")
+	delivery, err := s.repo.GetDelivery(ctx, subscriptionID, deliveryID)
+	if err != nil {
+		if errors.Is(err, ErrNotFoundBase) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetDeliveries, err)
+	}
+	if delivery.Status != StatusDead {
+		return nil, ErrDeliveryNotDead
+	}
+
+	delivery.Status = StatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+	if err := s.repo.SaveDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFailedToGetDeliveries, err)
+	}
+	return delivery, nil
+}
+
+func (s *service) Enqueue(ctx context.Context, msg outbox.Message) error {
+	// 🎯 El relay corre sin tenant: el del evento limita las suscripciones y marca los envíos
+	ctx = tenant.WithTenant(ctx, msg.TenantID)
+	subs, err := s.repo.GetActive(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFailedToEnqueueDeliveries, err)
+	}
+
+	payload, err := json.Marshal(Envelope{
+		ID:          msg.ID,
+		Type:        msg.EventType,
+		AggregateID: msg.AggregateID,
+		CreatedAt:   msg.CreatedAt,
+		Data:        msg.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFailedToEnqueueDeliveries, err)
+	}
+
+	now := time.Now().UTC()
+	deliveries := make([]*Delivery, 0, len(subs))
+	for i := range subs {
+		if !subs[i].Matches(msg.EventType) {
+			continue
+		}
+		deliveries = append(deliveries, &Delivery{
+			SubscriptionID: subs[i].ID,
+			EventID:        msg.ID,
+			EventType:      msg.EventType,
+			Payload:        payload,
+			Status:         StatusPending,
+			NextAttemptAt:  now,
+			TenantID:       msg.TenantID,
+		})
+	}
+
+	if err := s.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		return fmt.Errorf("%w: %v", ErrFailedToEnqueueDeliveries, err)
+	}
+	return nil
+}
+
+func (s *service) validateEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return ErrEventTypesRequired
+	}
+	for _, e := range eventTypes {
+		if !s.eventTypes[e] {
+			return fmt.Errorf("%w: %s", ErrInvalidEventType, e)
+		}
+	}
+	return nil
+}
+
+// validateURL acepta sólo http/https y, para que la API no sirva para llegar a la red
+// interna (SSRF), sólo hosts con direcciones públicas. El dispatcher lo vuelve a
+// revisar al conectar.
+func (s *service) validateURL(ctx context.Context, rawURL string) error {
+	if rawURL == "" {
+		return ErrURLRequired
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+		return ErrInvalidURL
+	}
+	if s.allowPrivate {
+		return nil
+	}
+	return checkHost(ctx, s.resolver, u.Hostname())
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,94 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/outbox"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant/tenanttest"
+	"github.com/NicoJCastro/gocourse_course/internal/webhook"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	db := tenanttest.NewDB(t, &webhook.Subscription{}, &webhook.Delivery{})
+
+	ctxA, ctxB := tenanttest.Contexts()
+	events := []string{"course.created"}
+
+	repo := webhook.NewRepo(db, tenanttest.Logger())
+	svc := webhook.NewService(tenanttest.Logger(), repo, events, false)
+	subA, err := svc.Create(ctxA, "https://203.0.113.10/a", events, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subB, err := svc.Create(ctxB, "https://203.0.113.20/b", events, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 🎯 El relay corre sin tenant: el evento de A sólo genera envíos para las suscripciones de A
+	msg := outbox.Message{
+		ID:          "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a",
+		EventType:   "course.created",
+		AggregateID: "0b6f3c1e-1d2a-4e5f-9a8b-7c6d5e4f3a2b",
+		Payload:     json.RawMessage(`{}`),
+		CreatedAt:   time.Now().UTC(),
+		TenantID:    tenanttest.A,
+	}
+	if err := svc.Enqueue(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+
+	var deliveries []webhook.Delivery
+	if err := db.Find(&deliveries).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].SubscriptionID != subA.ID || deliveries[0].TenantID != tenanttest.A {
+		t.Fatalf("deliveries = %+v, want one for the subscription of tenant A", deliveries)
+	}
+	deliveryID := deliveries[0].ID
+
+	t.Run("subscriptions", func(t *testing.T) {
+		if _, err := svc.Get(ctxB, subA.ID); !errors.Is(err, webhook.ErrNotFoundBase) {
+			t.Errorf("Get = %v, want not found", err)
+		}
+		subs, err := svc.GetAll(ctxB, 0, 10)
+		if err != nil || len(subs) != 1 || subs[0].ID != subB.ID {
+			t.Errorf("GetAll = %+v, %v; want only the subscription of tenant B", subs, err)
+		}
+		if count, err := svc.Count(ctxB); err != nil || count != 1 {
+			t.Errorf("Count = %d, %v; want 1", count, err)
+		}
+		active := false
+		if _, err := svc.Update(ctxB, subA.ID, nil, nil, nil, &active); !errors.Is(err, webhook.ErrNotFoundBase) {
+			t.Errorf("Update = %v, want not found", err)
+		}
+		if err := svc.Delete(ctxB, subA.ID); !errors.Is(err, webhook.ErrNotFoundBase) {
+			t.Errorf("Delete = %v, want not found", err)
+		}
+		if sub, err := svc.Get(ctxA, subA.ID); err != nil || !sub.Active {
+			t.Errorf("Get of the owner = %+v, %v; want the active subscription", sub, err)
+		}
+	})
+
+	t.Run("deliveries", func(t *testing.T) {
+		if _, err := svc.Deliveries(ctxB, subA.ID, 0, 10); !errors.Is(err, webhook.ErrNotFoundBase) {
+			t.Errorf("Deliveries = %v, want not found", err)
+		}
+		if _, err := svc.RetryDelivery(ctxB, subA.ID, deliveryID); !errors.Is(err, webhook.ErrNotFoundBase) {
+			t.Errorf("RetryDelivery = %v, want not found", err)
+		}
+		if _, err := repo.GetDelivery(ctxB, subA.ID, deliveryID); !errors.Is(err, webhook.ErrNotFoundBase) {
+			t.Errorf("repo.GetDelivery = %v, want not found", err)
+		}
+		if count, err := repo.CountDeliveries(ctxB, subA.ID); err != nil || count != 0 {
+			t.Errorf("repo.CountDeliveries = %d, %v; want 0", count, err)
+		}
+		if count, err := svc.CountDeliveries(ctxA, subA.ID); err != nil || count != 1 {
+			t.Errorf("CountDeliveries of the owner = %d, %v; want 1", count, err)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	// Subscription es un endpoint de un sistema externo que recibe eventos de cursos
+	Subscription struct {
+		ID         string         `json:"id" gorm:"type:char(36);not null;primary_key"`
+		URL        string         `json:"url" gorm:"type:varchar(500);not null"`
+		Secret     string         `json:"-" gorm:"type:varchar(100);not null"`
+		EventTypes string         `json:"-" gorm:"type:varchar(255);not null"`
+		Events     []string       `json:"event_types" gorm:"-"`
+		Active     bool           `json:"active" gorm:"not null;default:true"`
+		CreatedAt  *time.Time     `json:"created_at"`
+		UpdatedAt  *time.Time     `json:"updated_at"`
+		DeletedAt  gorm.DeletedAt `json:"-"`
+		TenantID   string         `json:"-" gorm:"type:varchar(64);not null;default:'';index"`
+	}
+
+	// DeliveryStatus es el estado de un envío
+	DeliveryStatus string
+
+	// Delivery es el registro (log) de entrega de un evento a una suscripción
+	Delivery struct {
+		ID             string          `json:"id" gorm:"type:char(36);not null;primary_key"`
+		SubscriptionID string          `json:"subscription_id" gorm:"type:char(36);not null;uniqueIndex:idx_delivery_event,priority:1"`
+		EventID        string          `json:"event_id" gorm:"type:char(36);not null;uniqueIndex:idx_delivery_event,priority:2"`
+		EventType      string          `json:"event_type" gorm:"type:varchar(50);not null"`
+		Payload        json.RawMessage `json:"payload" gorm:"type:json;not null"`
+		Status         DeliveryStatus  `json:"status" gorm:"type:varchar(20);not null;index:idx_delivery_due,priority:1"`
+		Attempts       int             `json:"attempts" gorm:"not null;default:0"`
+		NextAttemptAt  time.Time       `json:"next_attempt_at" gorm:"index:idx_delivery_due,priority:2"`
+		LastStatusCode int             `json:"last_status_code,omitempty"`
+		LastError      string          `json:"last_error,omitempty" gorm:"type:varchar(255)"`
+		DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+		CreatedAt      time.Time       `json:"created_at"`
+		UpdatedAt      time.Time       `json:"updated_at"`
+		TenantID       string          `json:"-" gorm:"type:varchar(64);not null;default:'';index"`
+	}
+)
+
+const (
+	// StatusPending: esperando el primer intento o un reintento
+	StatusPending DeliveryStatus = "pending"
+	// StatusSucceeded: el destino respondió 2xx
+	StatusSucceeded DeliveryStatus = "succeeded"
+	// StatusDead: se agotaron los reintentos (dead letter)
+	StatusDead DeliveryStatus = "dead"
+
+	// AllEvents suscribe a todos los tipos de evento
+	AllEvents = "*"
+)
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (s *Subscription) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return
+}
+
+// BeforeSave guarda la lista de eventos como texto separado por comas
+func (s *Subscription) BeforeSave(tx *gorm.DB) (err error) {
+	s.EventTypes = strings.Join(s.Events, ",")
+	return
+}
+
+func (s *Subscription) AfterFind(tx *gorm.DB) (err error) {
+	s.Events = strings.Split(s.EventTypes, ",")
+	return
+}
+
+func (d *Delivery) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return
+}
+
+// Matches indica si la suscripción recibe el tipo de evento dado
+func (s *Subscription) Matches(eventType string) bool {
+	for _, e := range s.Events {
+		if e == AllEvents || e == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+// Package apierror arma las respuestas de error de la API sin perder el error que
+// las originó: el encoder toma de él (errors.As) el código estable y lo traduce
+package apierror
+
+import (
+	"net/http"
+
+	"github.com/NicoJCastro/go_lib_response/response"
+)
+
+// Response es una respuesta de error que conserva el error original
+type Response struct {
+	response.ErrorResponse
+	err error
+}
+
+// New crea la respuesta con el status y el texto del error
+func New(status int, err error) *Response {
+	return &Response{
+		ErrorResponse: response.ErrorResponse{Status: status, Message: err.Error()},
+		err:           err,
+	}
+}
+
+// Unwrap permite usar errors.As() para llegar al error original
+func (r *Response) Unwrap() error {
+	return r.err
+}
+
+func BadRequest(err error) *Response {
+	return New(http.StatusBadRequest, err)
+}
+
+func Unauthorized(err error) *Response {
+	return New(http.StatusUnauthorized, err)
+}
+
+func Forbidden(err error) *Response {
+	return New(http.StatusForbidden, err)
+}
+
+func NotFound(err error) *Response {
+	return New(http.StatusNotFound, err)
+}
+
+func Conflict(err error) *Response {
+	return New(http.StatusConflict, err)
+}
+
+func InternalServerError(err error) *Response {
+	return New(http.StatusInternalServerError, err)
+}
@@ -1,41 +1,104 @@
-package bootstrap
-
-import (
-	"fmt"
-	"log"
-	"os"
-
-	"github.com/NicoJCastro/gocourse_domain/domain"
-
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
-)
-
-func DBConnection() (*gorm.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		os.Getenv("DATABASE_USER"),
-		os.Getenv("DATABASE_PASSWORD"),
-		os.Getenv("DATABASE_HOST"),
-		os.Getenv("DATABASE_PORT"),
-		os.Getenv("DATABASE_NAME"),
-	)
-
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
-	if err != nil {
-		return nil, err
-	}
-	if os.Getenv("DATABASE_DEBUG") == "true" {
-		db = db.Debug()
-	}
-
-	if os.Getenv("DATABASE_MIGRATE") == "true" {
-		if err := db.AutoMigrate(&domain.Course{}); err != nil {
-			return nil, err
-		}
-	}
-	return db, nil
-}
-
-func InitLogger() *log.Logger {
-	return log.New(os.Stdout, "course-api ", log.LstdFlags|log.Lshortfile)
-}
+package bootstrap
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/NicoJCastro/gocourse_course/pkg/replica"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// TenantTables son las tablas con columna tenant_id: todo lo que cuelga de un curso,
+// su auditoría, el catálogo, los eventos del outbox y los webhooks
+var TenantTables = []string{
+	"courses",
+	"audit_entries",
+	"course_sessions",
+	"course_translations",
+	"course_instructors",
+	"course_prerequisites",
+	"course_timezones",
+	"tags",
+	"categories",
+	"course_tags",
+	"course_categories",
+	"outbox_messages",
+	"webhook_subscriptions",
+	"webhook_deliveries",
+}
+
+func DBConnection(cfg config.Database) (*gorm.DB, error) {
+	db, err := gorm.Open(mysql.Open(dsn(cfg, cfg.Host, cfg.Port)), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	// 🎯 Réplicas de lectura: Get, GetAll y Count leen de una réplica salvo que la
+	// misma request haya escrito dentro de la ventana de read-your-writes
+	if len(cfg.ReplicaHosts) > 0 {
+		replicas := make([]gorm.Dialector, 0, len(cfg.ReplicaHosts))
+		for _, hostPort := range cfg.ReplicaHosts {
+			host, port, err := net.SplitHostPort(hostPort)
+			if err != nil {
+				return nil, fmt.Errorf("invalid replica host %s: %w", hostPort, err)
+			}
+			replicas = append(replicas, mysql.Open(dsn(cfg, host, port)))
+		}
+		resolver, err := replica.Register(db, replicas, cfg.ReadYourWritesWindow)
+		if err != nil {
+			return nil, err
+		}
+		resolver.SetMaxOpenConns(cfg.MaxOpenConns).
+			SetMaxIdleConns(cfg.MaxIdleConns).
+			SetConnMaxLifetime(cfg.ConnMaxLifetime).
+			SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+
+	// 🎯 Multi-tenancy: las consultas a las tablas de cada organización se filtran por el tenant del contexto
+	if err := tenant.Register(db, TenantTables...); err != nil {
+		return nil, err
+	}
+
+	if cfg.Debug {
+		db = db.Debug()
+	}
+
+	if cfg.Migrate {
+		if err := Migrate(db); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// dsn arma la conexión a un host de MySQL con el usuario y la base configurados.
+// 🔧 loc=UTC: las fechas se guardan y se leen en UTC; cada servicio las expresa
+// después en la zona horaria del curso o de la sesión. Las filas escritas antes con
+// loc=Local en un servidor fuera de UTC se convierten con "migrate utc -from <zona>".
+func dsn(cfg config.Database, host, port string) string {
+	return fmt.Sprintf("%s:%s@(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=UTC",
+		cfg.User,
+		cfg.Password,
+		host,
+		port,
+		cfg.Name,
+	)
+}
+
+func InitLogger() *log.Logger {
+	return log.New(os.Stdout, "course-api ", log.LstdFlags|log.Lshortfile)
+}
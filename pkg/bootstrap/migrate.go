@@ -0,0 +1,315 @@
+package bootstrap
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/catalog"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/outbox"
+	"github.com/NicoJCastro/gocourse_course/internal/session"
+	"github.com/NicoJCastro/gocourse_course/internal/webhook"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// TableConversion es la cantidad de filas de una tabla que pasó ConvertToUTC
+type TableConversion struct {
+	Table string `json:"table"`
+	Rows  int    `json:"rows"`
+}
+
+// TableStatus es el estado de la tabla de un modelo: si existe y qué columnas le faltan
+type TableStatus struct {
+	Table          string   `json:"table"`
+	Exists         bool     `json:"exists"`
+	MissingColumns []string `json:"missing_columns"`
+}
+
+// models son las tablas del servicio, en orden de creación
+func models() []interface{} {
+	return []interface{}{
+		&course.TenantCourse{},
+		&audit.Entry{},
+		&audit.Sequence{},
+		&outbox.Message{},
+		&webhook.Subscription{},
+		&webhook.Delivery{},
+		&catalog.Tag{},
+		&catalog.Category{},
+		&catalog.CourseTag{},
+		&catalog.CourseCategory{},
+		&course.Prerequisite{},
+		&course.PrerequisiteLock{},
+		&course.CourseTimezone{},
+		&course.Instructor{},
+		&course.Translation{},
+		&session.Session{},
+	}
+}
+
+// Migrate crea o actualiza todas las tablas y el índice FULLTEXT de la búsqueda
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(models()...); err != nil {
+		return err
+	}
+	// El nombre del tag pasó de único global a único por tenant
+	if db.Migrator().HasIndex(&catalog.Tag{}, "idx_tags_name") {
+		if err := db.Migrator().DropIndex(&catalog.Tag{}, "idx_tags_name"); err != nil {
+			return err
+		}
+	}
+	if err := backfillTenants(db); err != nil {
+		return err
+	}
+	if err := backfillAuditSeq(db); err != nil {
+		return err
+	}
+	return course.EnsureFullTextIndex(db)
+}
+
+// auditSeqBatch es cuántos registros numera backfillAuditSeq por transacción
+const auditSeqBatch = 1000
+
+// backfillAuditSeq numera en orden cronológico los registros de auditoría anteriores a
+// la columna seq, a continuación del contador de su tipo de entidad
+func backfillAuditSeq(db *gorm.DB) error {
+	for {
+		var pending []audit.Entry
+		err := db.Select("id", "entity_type").Where("seq = 0").
+			Order("created_at, id").Limit(auditSeqBatch).Find(&pending).Error
+		if err != nil {
+			return fmt.Errorf("error backfilling audit seq: %w", err)
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			last := map[string]int64{}
+			for _, entry := range pending {
+				if _, ok := last[entry.EntityType]; !ok {
+					var seq audit.Sequence
+					// Bloquea el contador como audit.Repository.Create mientras se numera el lote
+					err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+						Where("entity_type = ?", entry.EntityType).Limit(1).Find(&seq).Error
+					if err != nil {
+						return err
+					}
+					last[entry.EntityType] = seq.LastSeq
+				}
+				last[entry.EntityType]++
+				if err := tx.Model(&audit.Entry{}).Where("id = ?", entry.ID).Update("seq", last[entry.EntityType]).Error; err != nil {
+					return err
+				}
+			}
+			for entityType, seq := range last {
+				if err := tx.Save(&audit.Sequence{EntityType: entityType, LastSeq: seq}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error backfilling audit seq: %w", err)
+		}
+	}
+}
+
+// tenantBackfills son las tablas que heredan el tenant del curso al que pertenecen
+var tenantBackfills = []struct{ table, courseColumn string }{
+	{"course_sessions", "course_id"},
+	{"course_translations", "course_id"},
+	{"course_instructors", "course_id"},
+	{"course_prerequisites", "course_id"},
+	{"course_timezones", "course_id"},
+	{"course_tags", "course_id"},
+	{"course_categories", "course_id"},
+	{"outbox_messages", "aggregate_id"},
+}
+
+// catalogBackfill es una tabla del catálogo, que toma el tenant de los cursos que la usan
+type catalogBackfill struct {
+	table, linkTable, linkColumn string
+	// columns son las que se copian cuando la fila se reparte entre tenants
+	columns string
+}
+
+var catalogBackfills = []catalogBackfill{
+	{"tags", "course_tags", "tag_id", "name, created_at, updated_at"},
+	{"categories", "course_categories", "category_id", "name, parent_id, created_at, updated_at"},
+}
+
+// backfillTenants asigna a las filas anteriores a la columna tenant_id el tenant de
+// su curso. Un tag o categoría usado por un solo tenant pasa a ese tenant; si lo
+// usaban varios, cada uno recibe su copia. El resto, igual que las suscripciones de
+// webhooks, queda en el tenant por defecto.
+func backfillTenants(db *gorm.DB) error {
+	for _, backfill := range tenantBackfills {
+		err := db.Exec(fmt.Sprintf(
+			`UPDATE %[1]s SET tenant_id = (SELECT courses.tenant_id FROM courses WHERE courses.id = %[1]s.%[2]s)
+			WHERE tenant_id = '' AND EXISTS (SELECT 1 FROM courses WHERE courses.id = %[1]s.%[2]s AND courses.tenant_id <> '')`,
+			backfill.table, backfill.courseColumn,
+		)).Error
+		if err != nil {
+			return fmt.Errorf("error backfilling tenant of %s: %w", backfill.table, err)
+		}
+	}
+	for _, backfill := range catalogBackfills {
+		err := db.Exec(fmt.Sprintf(
+			`UPDATE %[1]s SET tenant_id = (SELECT MIN(%[2]s.tenant_id) FROM %[2]s WHERE %[2]s.%[3]s = %[1]s.id)
+			WHERE tenant_id = '' AND (
+				SELECT COUNT(DISTINCT %[2]s.tenant_id) FROM %[2]s WHERE %[2]s.%[3]s = %[1]s.id AND %[2]s.tenant_id <> ''
+			) = 1 AND NOT EXISTS (SELECT 1 FROM %[2]s WHERE %[2]s.%[3]s = %[1]s.id AND %[2]s.tenant_id = '')`,
+			backfill.table, backfill.linkTable, backfill.linkColumn,
+		)).Error
+		if err == nil {
+			err = splitShared(db, backfill)
+		}
+		if err != nil {
+			return fmt.Errorf("error backfilling tenant of %s: %w", backfill.table, err)
+		}
+	}
+	return nil
+}
+
+// splitShared copia en cada tenant las filas del tenant por defecto que usan sus
+// cursos y mueve las relaciones de esos cursos a la copia
+func splitShared(db *gorm.DB, backfill catalogBackfill) error {
+	var shared []struct {
+		ID       string
+		TenantID string
+	}
+	err := db.Raw(fmt.Sprintf(
+		`SELECT DISTINCT %[2]s.%[3]s AS id, %[2]s.tenant_id FROM %[2]s
+		JOIN %[1]s ON %[1]s.id = %[2]s.%[3]s
+		WHERE %[1]s.tenant_id = '' AND %[2]s.tenant_id <> ''`,
+		backfill.table, backfill.linkTable, backfill.linkColumn,
+	)).Scan(&shared).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range shared {
+			id := uuid.New().String()
+			err := tx.Exec(fmt.Sprintf(
+				`INSERT INTO %[1]s (id, tenant_id, %[2]s) SELECT ?, ?, %[2]s FROM %[1]s WHERE id = ?`,
+				backfill.table, backfill.columns,
+			), id, row.TenantID, row.ID).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Exec(fmt.Sprintf(
+				`UPDATE %[1]s SET %[2]s = ? WHERE %[2]s = ? AND tenant_id = ?`,
+				backfill.linkTable, backfill.linkColumn,
+			), id, row.ID, row.TenantID).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MigrateDown borra todas las tablas del servicio (y sus datos) en orden inverso
+func MigrateDown(db *gorm.DB) error {
+	tables := models()
+	slices.Reverse(tables)
+	return db.Migrator().DropTable(tables...)
+}
+
+// MigrationStatus compara las tablas de la base con los modelos
+func MigrationStatus(db *gorm.DB) ([]TableStatus, error) {
+	migrator := db.Migrator()
+	var statuses []TableStatus
+	for _, model := range models() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		status := TableStatus{Table: stmt.Schema.Table, Exists: migrator.HasTable(model), MissingColumns: []string{}}
+		if status.Exists {
+			for _, field := range stmt.Schema.Fields {
+				if field.DBName != "" && !migrator.HasColumn(model, field.DBName) {
+					status.MissingColumns = append(status.MissingColumns, field.DBName)
+				}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// ConvertToUTC pasa a UTC las fechas guardadas con la conexión anterior (loc=Local),
+// que quedaron como la hora de pared de from, la zona del servidor que las escribió.
+// Se corre una sola vez al actualizar: volver a correrla corre las fechas de nuevo.
+func ConvertToUTC(db *gorm.DB, from *time.Location) ([]TableConversion, error) {
+	var conversions []TableConversion
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range models() {
+			stmt := &gorm.Statement{DB: tx}
+			if err := stmt.Parse(model); err != nil {
+				return err
+			}
+			if !tx.Migrator().HasTable(model) {
+				continue
+			}
+			rows, err := convertTable(tx, stmt.Schema, from)
+			if err != nil {
+				return fmt.Errorf("error converting %s: %w", stmt.Schema.Table, err)
+			}
+			conversions = append(conversions, TableConversion{Table: stmt.Schema.Table, Rows: rows})
+		}
+		return nil
+	})
+	return conversions, err
+}
+
+// convertTable actualiza fila por fila (por clave primaria): cada fecha se convierte
+// con el offset que tenía from ese día, y así ninguna se convierte dos veces
+func convertTable(tx *gorm.DB, s *schema.Schema, from *time.Location) (int, error) {
+	var keys, columns []string
+	for _, field := range s.PrimaryFields {
+		keys = append(keys, field.DBName)
+	}
+	for _, field := range s.Fields {
+		if field.DBName != "" && field.DataType == schema.Time {
+			columns = append(columns, field.DBName)
+		}
+	}
+	if len(columns) == 0 || len(keys) == 0 {
+		return 0, nil
+	}
+
+	var rows []map[string]interface{}
+	if err := tx.Table(s.Table).Select(append(slices.Clone(keys), columns...)).Find(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	for _, row := range rows {
+		where := map[string]interface{}{}
+		for _, key := range keys {
+			where[key] = row[key]
+		}
+		updates := map[string]interface{}{}
+		for _, column := range columns {
+			if t, ok := row[column].(time.Time); ok {
+				// 🔧 Con loc=UTC la base devuelve la hora de pared como si fuera UTC
+				updates[column] = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), from).UTC()
+			}
+		}
+		if len(updates) == 0 {
+			continue
+		}
+		if err := tx.Table(s.Table).Where(where).Updates(updates).Error; err != nil {
+			return 0, fmt.Errorf("row %v: %w", where, err)
+		}
+	}
+	return len(rows), nil
+}
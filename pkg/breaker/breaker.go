@@ -0,0 +1,87 @@
+// Package breaker implementa un circuit breaker simple para dependencias externas
+package breaker
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+type (
+	state int
+
+	// Breaker se abre después de threshold fallos seguidos: mientras está abierto
+	// Allow rechaza las llamadas durante openTimeout, y luego deja pasar una sola
+	// llamada de prueba (half-open) que lo cierra o lo vuelve a abrir
+	Breaker struct {
+		log         *log.Logger
+		name        string
+		threshold   int
+		openTimeout time.Duration
+
+		mu       sync.Mutex
+		state    state
+		failures int
+		openedAt time.Time
+	}
+)
+
+const (
+	closed state = iota
+	open
+	halfOpen
+)
+
+func New(logger *log.Logger, name string, threshold int, openTimeout time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return &Breaker{
+		log:         logger,
+		name:        name,
+		threshold:   threshold,
+		openTimeout: openTimeout,
+	}
+}
+
+// Allow indica si se puede llamar a la dependencia; si no, devuelve cuánto falta
+// para la próxima llamada de prueba (para Retry-After)
+func (b *Breaker) Allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case open:
+		if wait := b.openTimeout - time.Since(b.openedAt); wait > 0 {
+			return false, wait
+		}
+		b.state = halfOpen
+		return true, 0
+	case halfOpen:
+		// Ya hay una llamada de prueba en curso
+		return false, b.openTimeout
+	}
+	return true, 0
+}
+
+// Record registra el resultado de una llamada permitida por Allow
+func (b *Breaker) Record(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ok {
+		if b.state != closed {
+			b.log.Printf("%s circuit closed\n", b.name)
+		}
+		b.state = closed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == halfOpen || b.failures >= b.threshold {
+		if b.state != open {
+			b.log.Printf("%s circuit open for %s after %d failures\n", b.name, b.openTimeout, b.failures)
+		}
+		b.state = open
+		b.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,408 @@
+// Package config carga la configuración tipada del servicio: valores por defecto,
+// un archivo YAML opcional y las variables de entorno (incluido .env), en ese orden
+// de prioridad creciente, y la valida una sola vez al arrancar
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/internal/locale"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/NicoJCastro/gocourse_meta/meta"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	Config struct {
+		Server      Server      `json:"server" yaml:"server"`
+		Database    Database    `json:"database" yaml:"database"`
+		Pagination  Pagination  `json:"pagination" yaml:"pagination"`
+		Courses     Courses     `json:"courses" yaml:"courses"`
+		Search      Search      `json:"search" yaml:"search"`
+		UserService UserService `json:"user_service" yaml:"user_service"`
+		NATS        NATS        `json:"nats" yaml:"nats"`
+		Webhooks    Webhooks    `json:"webhooks" yaml:"webhooks"`
+		Tenancy     Tenancy     `json:"tenancy" yaml:"tenancy"`
+		Locales     Locales     `json:"locales" yaml:"locales"`
+	}
+
+	Server struct {
+		// Host es la dirección de escucha: vacío o 0.0.0.0 atiende en todas las interfaces
+		Host string `json:"host" yaml:"host"`
+		Port string `json:"port" yaml:"port"`
+
+		ReadTimeout       time.Duration `json:"read_timeout" yaml:"read_timeout"`
+		ReadHeaderTimeout time.Duration `json:"read_header_timeout" yaml:"read_header_timeout"`
+		WriteTimeout      time.Duration `json:"write_timeout" yaml:"write_timeout"`
+		IdleTimeout       time.Duration `json:"idle_timeout" yaml:"idle_timeout"`
+
+		// HTTP2 habilita h2 sobre TLS y h2c (prior knowledge) en texto plano
+		HTTP2 bool `json:"http2" yaml:"http2"`
+		TLS   TLS  `json:"tls" yaml:"tls"`
+	}
+
+	// TLS sin CertFile sirve en texto plano. Con ClientCAFile se exige un
+	// certificado de cliente firmado por esas CAs (mTLS entre servicios).
+	TLS struct {
+		CertFile     string `json:"cert_file" yaml:"cert_file"`
+		KeyFile      string `json:"key_file" yaml:"key_file"`
+		ClientCAFile string `json:"client_ca_file" yaml:"client_ca_file"`
+		// ReloadInterval es cada cuánto se revisan los archivos para recargar certificados rotados
+		ReloadInterval time.Duration `json:"reload_interval" yaml:"reload_interval"`
+	}
+
+	Database struct {
+		User     string `json:"user" yaml:"user"`
+		Password string `json:"password" yaml:"password"`
+		Host     string `json:"host" yaml:"host"`
+		Port     string `json:"port" yaml:"port"`
+		Name     string `json:"name" yaml:"name"`
+		Debug    bool   `json:"debug" yaml:"debug"`
+		Migrate  bool   `json:"migrate" yaml:"migrate"`
+
+		// Pool de conexiones: cero deja el default de database/sql
+		MaxOpenConns    int           `json:"max_open_conns" yaml:"max_open_conns"`
+		MaxIdleConns    int           `json:"max_idle_conns" yaml:"max_idle_conns"`
+		ConnMaxLifetime time.Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime"`
+		ConnMaxIdleTime time.Duration `json:"conn_max_idle_time" yaml:"conn_max_idle_time"`
+
+		// ReplicaHosts son réplicas de lectura (host:port) con el mismo usuario y base
+		ReplicaHosts         []string      `json:"replica_hosts" yaml:"replica_hosts"`
+		ReadYourWritesWindow time.Duration `json:"read_your_writes_window" yaml:"read_your_writes_window"`
+	}
+
+	// Pagination son los límites de los listados: DefaultLimit cuando el cliente
+	// no manda limit y MaxLimit como tope de lo que puede pedir
+	Pagination struct {
+		DefaultLimit int `json:"default_limit" yaml:"default_limit"`
+		MaxLimit     int `json:"max_limit" yaml:"max_limit"`
+	}
+
+	Courses struct {
+		// UpsertOnReplace permite que PUT cree el curso con el ID del cliente si no existe
+		UpsertOnReplace bool `json:"upsert_on_replace" yaml:"upsert_on_replace"`
+		// AdminToken (header X-Admin-Token) habilita DELETE ?purge=true y la API de
+		// webhooks; si está vacío quedan deshabilitados
+		AdminToken string `json:"admin_token" yaml:"admin_token"`
+		// PrerequisiteDeletePolicy: restrict rechaza borrar un curso requerido, cascade lo quita
+		PrerequisiteDeletePolicy string `json:"prerequisite_delete_policy" yaml:"prerequisite_delete_policy"`
+		// TrashRetention activa el purge de la papelera (cero lo deshabilita)
+		TrashRetention     time.Duration `json:"trash_retention" yaml:"trash_retention"`
+		TrashPurgeInterval time.Duration `json:"trash_purge_interval" yaml:"trash_purge_interval"`
+	}
+
+	Search struct {
+		// Backend: mysql (FULLTEXT) o bleve (índice embebido, no filtra por tag ni categoría)
+		Backend string `json:"backend" yaml:"backend"`
+	}
+
+	// UserService sin URL deshabilita la verificación de instructores y creadores
+	UserService struct {
+		URL     string        `json:"url" yaml:"url"`
+		Timeout time.Duration `json:"timeout" yaml:"timeout"`
+	}
+
+	// NATS sin URL publica los eventos del outbox sólo como webhooks
+	NATS struct {
+		URL string `json:"url" yaml:"url"`
+	}
+
+	// Webhooks: AllowPrivateNetworks deja suscribir URLs de loopback o redes privadas,
+	// que por defecto se rechazan para que la API no sirva para llegar a la red interna
+	Webhooks struct {
+		AllowPrivateNetworks bool `json:"allow_private_networks" yaml:"allow_private_networks"`
+	}
+
+	// Tenancy define de dónde sale la organización de cada request. Con JWTSecret el
+	// tenant sólo se toma del claim de un Bearer token válido (HS256); si no, del header.
+	Tenancy struct {
+		Header    string `json:"header" yaml:"header"`
+		JWTSecret string `json:"jwt_secret" yaml:"jwt_secret"`
+		JWTClaim  string `json:"jwt_claim" yaml:"jwt_claim"`
+		// Required rechaza las requests sin tenant; si no, usan Default
+		Required bool   `json:"required" yaml:"required"`
+		Default  string `json:"default" yaml:"default"`
+	}
+
+	// Locales son los idiomas de las traducciones de los cursos. Default es el que se
+	// usa cuando el cliente no acepta ninguno de Supported.
+	Locales struct {
+		Default   string   `json:"default" yaml:"default"`
+		Supported []string `json:"supported" yaml:"supported"`
+	}
+)
+
+// Default es la configuración antes de aplicar el YAML y el entorno
+func Default() Config {
+	return Config{
+		Server: Server{
+			Host:              "0.0.0.0",
+			Port:              "8080",
+			ReadTimeout:       5 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      5 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			HTTP2:             true,
+			TLS:               TLS{ReloadInterval: time.Minute},
+		},
+		Database: Database{
+			Host:                 "localhost",
+			Port:                 "3306",
+			MaxIdleConns:         2,
+			ReadYourWritesWindow: 5 * time.Second,
+		},
+		Pagination: Pagination{DefaultLimit: 10, MaxLimit: 100},
+		Courses: Courses{
+			PrerequisiteDeletePolicy: "restrict",
+			TrashPurgeInterval:       time.Hour,
+		},
+		Search:      Search{Backend: "mysql"},
+		UserService: UserService{Timeout: 2 * time.Second},
+		Tenancy:     Tenancy{Header: "X-Tenant-ID", JWTClaim: "tenant_id"},
+		Locales:     Locales{Default: "es", Supported: []string{"es", "en", "pt"}},
+	}
+}
+
+// Load arma la configuración: carga .env (sin pisar el entorno), lee el YAML de
+// path (o de CONFIG_FILE si path está vacío) y aplica las variables de entorno.
+// Devuelve todos los errores juntos para corregirlos de una vez.
+func Load(path string) (*Config, error) {
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error loading .env: %w", err)
+	}
+
+	config := Default()
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	}
+
+	if err := config.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *Config) applyEnv() error {
+	env := &envReader{}
+	env.str("SERVER_HOST", &c.Server.Host)
+	env.str("PORT", &c.Server.Port)
+	env.duration("SERVER_READ_TIMEOUT", &c.Server.ReadTimeout)
+	env.duration("SERVER_READ_HEADER_TIMEOUT", &c.Server.ReadHeaderTimeout)
+	env.duration("SERVER_WRITE_TIMEOUT", &c.Server.WriteTimeout)
+	env.duration("SERVER_IDLE_TIMEOUT", &c.Server.IdleTimeout)
+	env.boolean("SERVER_HTTP2", &c.Server.HTTP2)
+	env.str("TLS_CERT_FILE", &c.Server.TLS.CertFile)
+	env.str("TLS_KEY_FILE", &c.Server.TLS.KeyFile)
+	env.str("TLS_CLIENT_CA_FILE", &c.Server.TLS.ClientCAFile)
+	env.duration("TLS_RELOAD_INTERVAL", &c.Server.TLS.ReloadInterval)
+
+	env.str("DATABASE_USER", &c.Database.User)
+	env.str("DATABASE_PASSWORD", &c.Database.Password)
+	env.str("DATABASE_HOST", &c.Database.Host)
+	env.str("DATABASE_PORT", &c.Database.Port)
+	env.str("DATABASE_NAME", &c.Database.Name)
+	env.boolean("DATABASE_DEBUG", &c.Database.Debug)
+	env.boolean("DATABASE_MIGRATE", &c.Database.Migrate)
+	env.integer("DATABASE_MAX_OPEN_CONNS", &c.Database.MaxOpenConns)
+	env.integer("DATABASE_MAX_IDLE_CONNS", &c.Database.MaxIdleConns)
+	env.duration("DATABASE_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime)
+	env.duration("DATABASE_CONN_MAX_IDLE_TIME", &c.Database.ConnMaxIdleTime)
+	env.list("DATABASE_REPLICA_HOSTS", &c.Database.ReplicaHosts)
+	env.duration("DATABASE_READ_YOUR_WRITES_WINDOW", &c.Database.ReadYourWritesWindow)
+
+	// 🔧 PAGINATION_LIMIT_DEFAUL (con el typo original) se sigue aceptando para no
+	// romper los despliegues existentes; PAGINATION_LIMIT_DEFAULT tiene prioridad
+	env.integer("PAGINATION_LIMIT_DEFAUL", &c.Pagination.DefaultLimit)
+	env.integer("PAGINATION_LIMIT_DEFAULT", &c.Pagination.DefaultLimit)
+	env.integer("PAGINATION_LIMIT_MAX", &c.Pagination.MaxLimit)
+
+	env.boolean("COURSE_PUT_UPSERT", &c.Courses.UpsertOnReplace)
+	env.str("ADMIN_TOKEN", &c.Courses.AdminToken)
+	env.str("PREREQUISITE_DELETE_POLICY", &c.Courses.PrerequisiteDeletePolicy)
+	env.duration("TRASH_RETENTION", &c.Courses.TrashRetention)
+	env.duration("TRASH_PURGE_INTERVAL", &c.Courses.TrashPurgeInterval)
+
+	env.str("SEARCH_BACKEND", &c.Search.Backend)
+	env.str("USER_SERVICE_URL", &c.UserService.URL)
+	env.duration("USER_SERVICE_TIMEOUT", &c.UserService.Timeout)
+	env.str("NATS_URL", &c.NATS.URL)
+	env.boolean("WEBHOOK_ALLOW_PRIVATE_NETWORKS", &c.Webhooks.AllowPrivateNetworks)
+
+	env.str("TENANT_HEADER", &c.Tenancy.Header)
+	env.str("TENANT_JWT_SECRET", &c.Tenancy.JWTSecret)
+	env.str("TENANT_JWT_CLAIM", &c.Tenancy.JWTClaim)
+	env.boolean("TENANT_REQUIRED", &c.Tenancy.Required)
+	env.str("TENANT_DEFAULT", &c.Tenancy.Default)
+
+	env.str("DEFAULT_LOCALE", &c.Locales.Default)
+	env.list("SUPPORTED_LOCALES", &c.Locales.Supported)
+	return errors.Join(env.errs...)
+}
+
+// Validate revisa la configuración completa y devuelve un error por cada campo inválido
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port != "", "server.port (PORT) is required")
+	if c.Server.Port != "" {
+		port, err := strconv.Atoi(c.Server.Port)
+		check(err == nil && port > 0 && port <= 65535, "server.port (PORT) must be a valid port: %s", c.Server.Port)
+	}
+	check(c.Server.ReadTimeout >= 0, "server.read_timeout (SERVER_READ_TIMEOUT) must not be negative")
+	check(c.Server.ReadHeaderTimeout >= 0, "server.read_header_timeout (SERVER_READ_HEADER_TIMEOUT) must not be negative")
+	check(c.Server.WriteTimeout >= 0, "server.write_timeout (SERVER_WRITE_TIMEOUT) must not be negative")
+	check(c.Server.IdleTimeout >= 0, "server.idle_timeout (SERVER_IDLE_TIMEOUT) must not be negative")
+	check((c.Server.TLS.CertFile == "") == (c.Server.TLS.KeyFile == ""),
+		"server.tls.cert_file (TLS_CERT_FILE) and server.tls.key_file (TLS_KEY_FILE) must be set together")
+	check(c.Server.TLS.ClientCAFile == "" || c.Server.TLS.CertFile != "",
+		"server.tls.client_ca_file (TLS_CLIENT_CA_FILE) requires server.tls.cert_file")
+	check(c.Server.TLS.CertFile == "" || c.Server.TLS.ReloadInterval > 0,
+		"server.tls.reload_interval (TLS_RELOAD_INTERVAL) must be positive")
+
+	check(c.Database.Host != "", "database.host (DATABASE_HOST) is required")
+	check(c.Database.Port != "", "database.port (DATABASE_PORT) is required")
+	check(c.Database.Name != "", "database.name (DATABASE_NAME) is required")
+	check(c.Database.MaxOpenConns >= 0, "database.max_open_conns (DATABASE_MAX_OPEN_CONNS) must not be negative")
+	check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns (DATABASE_MAX_IDLE_CONNS) must not be negative")
+	check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns (DATABASE_MAX_IDLE_CONNS) must not exceed database.max_open_conns")
+	check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime (DATABASE_CONN_MAX_LIFETIME) must not be negative")
+	check(c.Database.ConnMaxIdleTime >= 0, "database.conn_max_idle_time (DATABASE_CONN_MAX_IDLE_TIME) must not be negative")
+	check(c.Database.ReadYourWritesWindow >= 0, "database.read_your_writes_window (DATABASE_READ_YOUR_WRITES_WINDOW) must not be negative")
+	for _, host := range c.Database.ReplicaHosts {
+		_, _, err := net.SplitHostPort(host)
+		check(err == nil, "database.replica_hosts (DATABASE_REPLICA_HOSTS) must be host:port: %s", host)
+	}
+
+	check(c.Pagination.DefaultLimit > 0, "pagination.default_limit (PAGINATION_LIMIT_DEFAULT) must be positive")
+	check(c.Pagination.MaxLimit >= c.Pagination.DefaultLimit,
+		"pagination.max_limit (PAGINATION_LIMIT_MAX) must be at least pagination.default_limit")
+
+	switch c.Courses.PrerequisiteDeletePolicy {
+	case "restrict", "cascade":
+	default:
+		check(false, "courses.prerequisite_delete_policy (PREREQUISITE_DELETE_POLICY) must be restrict or cascade: %s", c.Courses.PrerequisiteDeletePolicy)
+	}
+	check(c.Courses.TrashRetention >= 0, "courses.trash_retention (TRASH_RETENTION) must not be negative")
+	check(c.Courses.TrashPurgeInterval > 0, "courses.trash_purge_interval (TRASH_PURGE_INTERVAL) must be positive")
+
+	switch c.Search.Backend {
+	case "mysql", "bleve":
+	default:
+		check(false, "search.backend (SEARCH_BACKEND) must be mysql or bleve: %s", c.Search.Backend)
+	}
+
+	check(c.UserService.Timeout > 0, "user_service.timeout (USER_SERVICE_TIMEOUT) must be positive")
+
+	check(c.Tenancy.Header != "", "tenancy.header (TENANT_HEADER) is required")
+	check(c.Tenancy.JWTSecret == "" || c.Tenancy.JWTClaim != "",
+		"tenancy.jwt_claim (TENANT_JWT_CLAIM) is required with tenancy.jwt_secret")
+	check(tenant.Validate(c.Tenancy.Default) == nil, "tenancy.default (TENANT_DEFAULT): %s", tenant.ErrInvalidTenant)
+
+	check(len(c.Locales.Supported) > 0, "locales.supported (SUPPORTED_LOCALES) must not be empty")
+	for _, code := range c.Locales.Supported {
+		normalized, err := locale.Normalize(code)
+		check(err == nil && normalized == code, "locales.supported (SUPPORTED_LOCALES) must be language codes like es or en: %s", code)
+	}
+	check(slices.Contains(c.Locales.Supported, c.Locales.Default),
+		"locales.default (DEFAULT_LOCALE) must be one of locales.supported: %s", c.Locales.Default)
+	return errors.Join(errs...)
+}
+
+// Limit devuelve el limit efectivo de una página: el default si el cliente no lo
+// manda y nunca más que MaxLimit
+func (p Pagination) Limit(limit int) int {
+	if limit <= 0 {
+		return p.DefaultLimit
+	}
+	return min(limit, p.MaxLimit)
+}
+
+// Meta arma la metadata de una página con el limit ya acotado
+func (p Pagination) Meta(page, limit, count int) (*meta.Meta, error) {
+	if page <= 0 {
+		page = 1
+	}
+	return meta.New(page, p.Limit(limit), count, strconv.Itoa(p.DefaultLimit))
+}
+
+// envReader aplica las variables definidas y acumula los errores de formato
+type envReader struct {
+	errs []error
+}
+
+func (e *envReader) str(key string, target *string) {
+	if value, ok := os.LookupEnv(key); ok {
+		*target = value
+	}
+}
+
+func (e *envReader) integer(key string, target *int) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s must be an integer: %s", key, value))
+			return
+		}
+		*target = n
+	}
+}
+
+func (e *envReader) boolean(key string, target *bool) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s must be true or false: %s", key, value))
+			return
+		}
+		*target = b
+	}
+}
+
+func (e *envReader) duration(key string, target *time.Duration) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s must be a duration (ej: 30s, 5m): %s", key, value))
+			return
+		}
+		*target = d
+	}
+}
+
+func (e *envReader) list(key string, target *[]string) {
+	if value, ok := os.LookupEnv(key); ok {
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*target = items
+	}
+}
@@ -0,0 +1,154 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+)
+
+// writeConfig deja el YAML en un archivo temporal y devuelve su ruta
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+server:
+  port: "9090"
+  read_timeout: 30s
+database:
+  name: courses
+  replica_hosts: ["replica-1:3306"]
+pagination:
+  default_limit: 20
+  max_limit: 50
+`)
+	// El entorno pisa al YAML
+	t.Setenv("SERVER_READ_TIMEOUT", "10s")
+	t.Setenv("PAGINATION_LIMIT_MAX", "200")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != "9090" || cfg.Server.ReadTimeout != 10*time.Second || cfg.Database.Name != "courses" {
+		t.Errorf("server = %+v, database = %+v", cfg.Server, cfg.Database)
+	}
+	if cfg.Pagination.DefaultLimit != 20 || cfg.Pagination.MaxLimit != 200 {
+		t.Errorf("pagination = %+v, want 20 from the YAML and 200 from the environment", cfg.Pagination)
+	}
+	// Lo que no está en el YAML conserva el default
+	if cfg.Database.Host != "localhost" || cfg.Search.Backend != "mysql" {
+		t.Errorf("defaults = %s, %s", cfg.Database.Host, cfg.Search.Backend)
+	}
+}
+
+func TestPaginationLimitEnv(t *testing.T) {
+	path := writeConfig(t, "database:\n  name: courses\n")
+	tests := []struct {
+		name  string
+		env   map[string]string
+		limit int
+	}{
+		{"default", nil, 10},
+		{"legacy name", map[string]string{"PAGINATION_LIMIT_DEFAUL": "25"}, 25},
+		{"new name wins", map[string]string{"PAGINATION_LIMIT_DEFAUL": "25", "PAGINATION_LIMIT_DEFAULT": "30"}, 30},
+		{"empty new name falls back", map[string]string{"PAGINATION_LIMIT_DEFAUL": "25", "PAGINATION_LIMIT_DEFAULT": ""}, 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg, err := config.Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Pagination.DefaultLimit != tt.limit {
+				t.Errorf("DefaultLimit = %d, want %d", cfg.Pagination.DefaultLimit, tt.limit)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	path := writeConfig(t, "database:\n  name: courses\n")
+
+	t.Run("environment format", func(t *testing.T) {
+		t.Setenv("PAGINATION_LIMIT_DEFAUL", "ten")
+		t.Setenv("SERVER_HTTP2", "maybe")
+		t.Setenv("USER_SERVICE_TIMEOUT", "2")
+		_, err := config.Load(path)
+		for _, want := range []string{"PAGINATION_LIMIT_DEFAUL must be an integer", "SERVER_HTTP2 must be true or false", "USER_SERVICE_TIMEOUT must be a duration"} {
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("Load = %v, want %q", err, want)
+			}
+		}
+	})
+
+	t.Run("invalid YAML", func(t *testing.T) {
+		if _, err := config.Load(writeConfig(t, "server: [")); err == nil {
+			t.Error("Load with invalid YAML succeeded")
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database.Name = "courses"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate of the defaults = %v", err)
+	}
+
+	// Todos los errores salen juntos
+	cfg.Server.Port = "70000"
+	cfg.Server.TLS.CertFile = "server.crt"
+	cfg.Database.MaxOpenConns = 1
+	cfg.Database.MaxIdleConns = 2
+	cfg.Database.ReplicaHosts = []string{"replica-1"}
+	cfg.Pagination.MaxLimit = 5
+	cfg.Courses.PrerequisiteDeletePolicy = "ignore"
+	cfg.Search.Backend = "elastic"
+	cfg.Tenancy.Default = "tenant a"
+	cfg.Locales.Supported = []string{"es", "EN"}
+	cfg.Locales.Default = "pt"
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate succeeded")
+	}
+	for _, want := range []string{
+		"server.port (PORT) must be a valid port: 70000",
+		"server.tls.cert_file (TLS_CERT_FILE) and server.tls.key_file (TLS_KEY_FILE) must be set together",
+		"database.max_idle_conns (DATABASE_MAX_IDLE_CONNS) must not exceed database.max_open_conns",
+		"database.replica_hosts (DATABASE_REPLICA_HOSTS) must be host:port: replica-1",
+		"pagination.max_limit (PAGINATION_LIMIT_MAX) must be at least pagination.default_limit",
+		"courses.prerequisite_delete_policy (PREREQUISITE_DELETE_POLICY) must be restrict or cascade: ignore",
+		"search.backend (SEARCH_BACKEND) must be mysql or bleve: elastic",
+		"tenancy.default (TENANT_DEFAULT)",
+		"locales.supported (SUPPORTED_LOCALES) must be language codes like es or en: EN",
+		"locales.default (DEFAULT_LOCALE) must be one of locales.supported: pt",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate = %v\nwant %q", err, want)
+		}
+	}
+}
+
+func TestPaginationLimit(t *testing.T) {
+	p := config.Pagination{DefaultLimit: 10, MaxLimit: 100}
+	for limit, want := range map[int]int{0: 10, -1: 10, 25: 25, 500: 100} {
+		if got := p.Limit(limit); got != want {
+			t.Errorf("Limit(%d) = %d, want %d", limit, got, want)
+		}
+	}
+}
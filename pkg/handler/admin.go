@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/NicoJCastro/gocourse_course/pkg/apierror"
+)
+
+// HeaderAdminToken es el header con el que se autentican las operaciones de administración
+const HeaderAdminToken = "X-Admin-Token"
+
+var ErrAdminRequired = newError("admin_required", "admin privileges required")
+
+// RequireAdmin deja pasar sólo las requests con el AdminToken configurado; si está
+// vacío la ruta queda deshabilitada, igual que DELETE ?purge=true
+func RequireAdmin(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get(HeaderAdminToken)), []byte(token)) != 1 {
+			encodeError(r.Context(), apierror.Forbidden(ErrAdminRequired), w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/NicoJCastro/gocourse_course/internal/catalog"
+	"github.com/NicoJCastro/gocourse_course/pkg/apierror"
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+func NewCatalogHTTPServer(ctx context.Context, endpoints catalog.Endpoint) http.Handler {
+	mux := mux.NewRouter()
+
+	opts := []httptransport.ServerOption{
+		httptransport.ServerBefore(requestContext),
+		httptransport.ServerAfter(requestIDHeader),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// 🎯 POST /tags - Crear tag (también se crean al asignarlos a un curso)
+	mux.Handle("/tags", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.CreateTag),
+		decodeCreateTag,
+		encodeResponse,
+		opts...,
+	)).Methods("POST")
+
+	// 🎯 GETALL /tags - Listar tags por nombre (paginado)
+	mux.Handle("/tags", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.GetTags),
+		decodeGetAllCatalog,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 GET /tags/{id} - Obtener tag
+	mux.Handle("/tags/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.GetTag),
+		decodeGetCatalog,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 PATCH /tags/{id} - Renombrar tag
+	mux.Handle("/tags/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.UpdateTag),
+		decodeUpdateTag,
+		encodeResponse,
+		opts...,
+	)).Methods("PATCH")
+
+	// 🎯 DELETE /tags/{id} - Eliminar tag y quitarlo de los cursos
+	mux.Handle("/tags/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.DeleteTag),
+		decodeDeleteCatalog,
+		encodeResponse,
+		opts...,
+	)).Methods("DELETE")
+
+	// 🎯 POST /categories - Crear categoría (parent_id opcional)
+	mux.Handle("/categories", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.CreateCategory),
+		decodeCreateCategory,
+		encodeResponse,
+		opts...,
+	)).Methods("POST")
+
+	// 🎯 GETALL /categories - Listar categorías; parent_id filtra por padre y parent_id=root sólo raíces
+	mux.Handle("/categories", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.GetCategories),
+		decodeGetCategories,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 GET /categories/{id} - Obtener categoría con sus subcategorías directas
+	mux.Handle("/categories/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.GetCategory),
+		decodeGetCatalog,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 PATCH /categories/{id} - Renombrar o mover categoría
+	mux.Handle("/categories/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.UpdateCategory),
+		decodeUpdateCategory,
+		encodeResponse,
+		opts...,
+	)).Methods("PATCH")
+
+	// 🎯 DELETE /categories/{id} - Eliminar categoría sin subcategorías
+	mux.Handle("/categories/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.DeleteCategory),
+		decodeDeleteCatalog,
+		encodeResponse,
+		opts...,
+	)).Methods("DELETE")
+
+	return mux
+}
+
+// 🎯 Decoders de tags
+func decodeCreateTag(_ context.Context, r *http.Request) (interface{}, error) {
+	var req catalog.CreateTagReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	return req, nil
+}
+
+func decodeUpdateTag(_ context.Context, r *http.Request) (interface{}, error) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		return nil, apierror.BadRequest(catalog.ErrIDRequired)
+	}
+	var req catalog.UpdateTagReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	req.ID = id
+	return req, nil
+}
+
+// 🎯 Decoders de categorías
+func decodeCreateCategory(_ context.Context, r *http.Request) (interface{}, error) {
+	var req catalog.CreateCategoryReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	return req, nil
+}
+
+func decodeGetCategories(_ context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	page, _ := strconv.Atoi(query.Get("page"))
+
+	req := catalog.GetCategoriesReq{Limit: limit, Page: page}
+	if parentIDs, ok := query["parent_id"]; ok {
+		// parent_id=root equivale a parent_id= (sólo raíces)
+		parentID := parentIDs[0]
+		if parentID == "root" {
+			parentID = ""
+		}
+		req.ParentID = &parentID
+	}
+	return req, nil
+}
+
+func decodeUpdateCategory(_ context.Context, r *http.Request) (interface{}, error) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		return nil, apierror.BadRequest(catalog.ErrIDRequired)
+	}
+	var req catalog.UpdateCategoryReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	req.ID = id
+	return req, nil
+}
+
+// 🎯 Decoders compartidos por tags y categorías
+func decodeGetAllCatalog(_ context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	page, _ := strconv.Atoi(query.Get("page"))
+	return catalog.GetAllReq{Limit: limit, Page: page}, nil
+}
+
+func decodeGetCatalog(_ context.Context, r *http.Request) (interface{}, error) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		return nil, apierror.BadRequest(catalog.ErrIDRequired)
+	}
+	return catalog.GetReq{ID: id}, nil
+}
+
+func decodeDeleteCatalog(_ context.Context, r *http.Request) (interface{}, error) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		return nil, apierror.BadRequest(catalog.ErrIDRequired)
+	}
+	return catalog.DeleteReq{ID: id}, nil
+}
@@ -1,169 +1,837 @@
-package handler
-
-import (
-	"context"
-	"encoding/json"
-	"net/http"
-	"strconv"
-
-	"github.com/NicoJCastro/go_lib_response/response"
-	"github.com/NicoJCastro/gocourse_course/internal/course"
-	"github.com/go-kit/kit/endpoint"
-	httptransport "github.com/go-kit/kit/transport/http"
-	"github.com/gorilla/mux"
-)
-
-func NewCourseHTTPServer(ctx context.Context, endpoints course.Endpoint) http.Handler {
-	mux := mux.NewRouter()
-
-	opts := []httptransport.ServerOption{
-		httptransport.ServerErrorEncoder(encodeError),
-	}
-
-	// 🎯 POST /courses - Crear course
-	mux.Handle("/courses", httptransport.NewServer(
-		endpoint.Endpoint(endpoints.Create),
-		decodeCreateCourse,
-		encodeResponse,
-		opts...,
-	)).Methods("POST")
-
-	// 🎯 GETALL /courses - Obtener todos los cursos (con paginación y filtros)
-	mux.Handle("/courses", httptransport.NewServer(
-		endpoint.Endpoint(endpoints.GetAll),
-		decodeGetAllCourses,
-		encodeResponse,
-		opts...,
-	)).Methods("GET")
-
-	// 🎯 GET /courses/{id} - Obtener un curso por ID
-	mux.Handle("/courses/{id}", httptransport.NewServer(
-		endpoint.Endpoint(endpoints.Get),
-		decodeGetCourse,
-		encodeResponse,
-		opts...,
-	)).Methods("GET")
-
-	// 🎯 PATCH /courses/{id} - Actualizar curso
-	mux.Handle("/courses/{id}", httptransport.NewServer(
-		endpoint.Endpoint(endpoints.Update),
-		decodeUpdateCourse,
-		encodeResponse,
-		opts...,
-	)).Methods("PATCH")
-
-	// 🎯 DELETE /courses/{id} - Eliminar curso
-	mux.Handle("/courses/{id}", httptransport.NewServer(
-		endpoint.Endpoint(endpoints.Delete),
-		decodeDeleteCourse,
-		encodeResponse,
-		opts...,
-	)).Methods("DELETE")
-	return mux
-}
-
-// 🎯 Decoder para CREATE: decodifica el body JSON
-func decodeCreateCourse(_ context.Context, r *http.Request) (interface{}, error) {
-	var req course.CreateReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return nil, response.BadRequest("invalid JSON format")
-	}
-	return req, nil
-}
-
-// 🎯 Decoder para GET: extrae el ID de la URL
-func decodeGetCourse(_ context.Context, r *http.Request) (interface{}, error) {
-	vars := mux.Vars(r)
-	id, ok := vars["id"]
-	if !ok || id == "" {
-		return nil, response.BadRequest(course.ErrIDRequired.Error())
-	}
-	return course.GetReq{ID: id}, nil
-}
-
-// 🎯 Decoder para GET ALL: extrae query parameters (limit, page, filters)
-func decodeGetAllCourses(_ context.Context, r *http.Request) (interface{}, error) {
-	// Extraer query parameters
-	query := r.URL.Query()
-
-	// Convertir limit y page a int
-	limit := 0
-	if limitStr := query.Get("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil {
-			limit = l
-		}
-	}
-
-	page := 0
-	if pageStr := query.Get("page"); pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil {
-			page = p
-		}
-	}
-
-	// Construir GetAllReq con los query parameters
-	req := course.GetAllReq{
-		Name:  query.Get("name"),
-		Limit: limit,
-		Page:  page,
-	}
-
-	return req, nil
-}
-
-// 🎯 Decoder para UPDATE: extrae ID de la URL y body JSON
-func decodeUpdateCourse(_ context.Context, r *http.Request) (interface{}, error) {
-	vars := mux.Vars(r)
-	id, ok := vars["id"]
-	if !ok || id == "" {
-		return nil, response.BadRequest(course.ErrIDRequired.Error())
-	}
-
-	var req course.UpdateReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return nil, response.BadRequest("invalid JSON format")
-	}
-
-	// Asignar el ID extraído de la URL
-	req.ID = id
-	return req, nil
-}
-
-// 🎯 Decoder para DELETE: extrae el ID de la URL
-func decodeDeleteCourse(_ context.Context, r *http.Request) (interface{}, error) {
-	vars := mux.Vars(r)
-	id, ok := vars["id"]
-	if !ok || id == "" {
-		return nil, response.BadRequest(course.ErrIDRequired.Error())
-	}
-	return course.DeleteReq{ID: id}, nil
-}
-
-// 🎯 Encoder para todas las respuestas exitosas
-func encodeResponse(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
-	respObj, ok := resp.(response.Response)
-	if !ok {
-		// Si no es response.Response, es un error de programación
-		// encodeError debería haberlo manejado
-		respObj = response.InternalServerError("invalid response type")
-	}
-
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(respObj.StatusCode())
-	return json.NewEncoder(w).Encode(respObj)
-}
-
-func encodeError(_ context.Context, err error, w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-
-	// 🔍 Intentamos convertir el error a response.Response
-	resp, ok := err.(response.Response)
-	if !ok {
-		// ❌ Si no es response.Response, es un error estándar de Go
-		// 💡 Lo convertimos a InternalServerError como fallback seguro
-		resp = response.InternalServerError(err.Error())
-	}
-
-	w.WriteHeader(resp.StatusCode())
-	_ = json.NewEncoder(w).Encode(resp)
-}
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NicoJCastro/go_lib_response/response"
+	"github.com/NicoJCastro/gocourse_course/internal/audit"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/pkg/apierror"
+	"github.com/NicoJCastro/gocourse_course/pkg/replica"
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// maxImportSize limita el tamaño de los archivos de /courses/import (10 MB)
+const maxImportSize = 10 << 20
+
+// maxPatchSize limita el body de PATCH /courses/{id} (1 MB): Merge Patch y JSON Patch
+// se leen enteros en memoria antes de aplicarlos
+const maxPatchSize = 1 << 20
+
+func NewCourseHTTPServer(ctx context.Context, endpoints course.Endpoint) http.Handler {
+	mux := mux.NewRouter()
+
+	opts := []httptransport.ServerOption{
+		httptransport.ServerBefore(requestContext),
+		httptransport.ServerAfter(requestIDHeader),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// 🎯 POST /courses - Crear course
+	mux.Handle("/courses", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Create),
+		decodeCreateCourse,
+		encodeResponse,
+		opts...,
+	)).Methods("POST")
+
+	// 🎯 POST/PATCH/DELETE /courses:batch - Operaciones en lote en una única transacción
+	// El body incluye "mode": "atomic" (todo o nada, por defecto) o "best_effort"
+	mux.Handle("/courses:batch", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.CreateBatch),
+		decodeCreateBatch,
+		encodeResponse,
+		opts...,
+	)).Methods("POST")
+
+	mux.Handle("/courses:batch", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.UpdateBatch),
+		decodeUpdateBatch,
+		encodeResponse,
+		opts...,
+	)).Methods("PATCH")
+
+	mux.Handle("/courses:batch", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.DeleteBatch),
+		decodeDeleteBatch,
+		encodeResponse,
+		opts...,
+	)).Methods("DELETE")
+
+	// 🎯 POST /courses/import - Importar cursos desde CSV o XLSX
+	// Query params: dry_run=true (sólo valida), columns=name:Nombre,start_date:Inicio,end_date:Fin
+	mux.Handle("/courses/import", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Import),
+		decodeImportCourses,
+		encodeResponse,
+		opts...,
+	)).Methods("POST")
+
+	// 🎯 GET /courses/export - Exportar todos los cursos filtrados en streaming
+	// format=csv|ndjson|ics, acepta los mismos filtros que GET /courses (name, deleted,
+	// tag, tag_match, category) pero sin q ni paginación
+	mux.Handle("/courses/export", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Export),
+		decodeExportCourses,
+		encodeExport,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 GET /courses/suggest - Autocompletado de nombres por prefijo (?q=&limit=)
+	mux.Handle("/courses/suggest", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Suggest),
+		decodeSuggestCourses,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 GET /courses/events - Stream SSE de altas, cambios y bajas de cursos
+	// Acepta name y deleted como GET /courses (por defecto incluye las bajas) y
+	// reanuda desde el header Last-Event-ID
+	mux.Handle("/courses/events", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Events),
+		decodeCourseEvents,
+		encodeEvents,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 GETALL /courses - Obtener todos los cursos (con paginación y filtros)
+	// q= busca por texto completo y ordena por relevancia, con los fragmentos resaltados
+	// deleted=include suma los cursos de la papelera, deleted=only lista sólo la papelera
+	// tag=a,b con tag_match=any|all y category=<id> (incluye subcategorías)
+	mux.Handle("/courses", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.GetAll),
+		decodeGetAllCourses,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 GET /courses/{id} - Obtener un curso por ID
+	mux.Handle("/courses/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Get),
+		decodeGetCourse,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 PATCH /courses/{id} - Actualizar curso
+	mux.Handle("/courses/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Update),
+		decodeUpdateCourse,
+		encodeResponse,
+		opts...,
+	)).Methods("PATCH")
+
+	// 🎯 PUT /courses/{id} - Reemplazar curso completo
+	// A diferencia de PATCH, PUT exige todos los campos (name, start_date, end_date)
+	// y sobrescribe el recurso. Si UpsertOnReplace está activo y el curso no existe,
+	// se crea con el ID de la URL y se responde 201.
+	mux.Handle("/courses/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Replace),
+		decodeReplaceCourse,
+		encodeResponse,
+		opts...,
+	)).Methods("PUT")
+
+	// 🎯 GET /courses/{id}/history - Historial de auditoría del curso (paginado)
+	mux.Handle("/courses/{id}/history", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.History),
+		decodeHistoryCourse,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 GET /courses/{id}/prerequisites - Árbol transitivo de prerequisitos
+	mux.Handle("/courses/{id}/prerequisites", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Prerequisites),
+		decodeGetCourse,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 POST /courses/{id}/prerequisites - Agregar prerequisito ({"prerequisite_id": "..."})
+	// Se rechazan las autorreferencias (400) y los ciclos (409)
+	mux.Handle("/courses/{id}/prerequisites", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.AddPrerequisite),
+		decodeAddPrerequisite,
+		encodeResponse,
+		opts...,
+	)).Methods("POST")
+
+	// 🎯 DELETE /courses/{id}/prerequisites/{prerequisite_id} - Quitar prerequisito
+	mux.Handle("/courses/{id}/prerequisites/{prerequisite_id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.RemovePrerequisite),
+		decodeRemovePrerequisite,
+		encodeResponse,
+		opts...,
+	)).Methods("DELETE")
+
+	// 🎯 GET /courses/{id}/instructors - Instructores asignados al curso
+	mux.Handle("/courses/{id}/instructors", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Instructors),
+		decodeGetCourse,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 POST /courses/{id}/instructors - Asignar instructor ({"user_id": "..."})
+	// Si el instructor ya da otro curso en esas fechas responde 409 con los cursos en conflicto
+	mux.Handle("/courses/{id}/instructors", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.AssignInstructor),
+		decodeAssignInstructor,
+		encodeResponse,
+		opts...,
+	)).Methods("POST")
+
+	// 🎯 DELETE /courses/{id}/instructors/{user_id} - Quitar instructor
+	mux.Handle("/courses/{id}/instructors/{user_id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.RemoveInstructor),
+		decodeRemoveInstructor,
+		encodeResponse,
+		opts...,
+	)).Methods("DELETE")
+
+	// 🎯 GET /courses/{id}/translations - Traducciones del curso en todos los idiomas
+	mux.Handle("/courses/{id}/translations", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Translations),
+		decodeGetCourse,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 PUT /courses/{id}/translations/{locale} - Crear o reemplazar una traducción
+	// ({"name": "...", "description": "..."})
+	mux.Handle("/courses/{id}/translations/{locale}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.SetTranslation),
+		decodeSetTranslation,
+		encodeResponse,
+		opts...,
+	)).Methods("PUT")
+
+	// 🎯 DELETE /courses/{id}/translations/{locale} - Quitar una traducción
+	mux.Handle("/courses/{id}/translations/{locale}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.RemoveTranslation),
+		decodeRemoveTranslation,
+		encodeResponse,
+		opts...,
+	)).Methods("DELETE")
+
+	// 🎯 POST /courses/{id}/restore - Recuperar un curso de la papelera
+	mux.Handle("/courses/{id}/restore", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Restore),
+		decodeRestoreCourse,
+		encodeResponse,
+		opts...,
+	)).Methods("POST")
+
+	// 🎯 DELETE /courses/{id} - Eliminar curso (soft delete)
+	// Con ?purge=true y el header X-Admin-Token se elimina definitivamente
+	mux.Handle("/courses/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Delete),
+		decodeDeleteCourse,
+		encodeResponse,
+		opts...,
+	)).Methods("DELETE")
+	return mux
+}
+
+// 🎯 Decoder para CREATE: decodifica el body JSON
+func decodeCreateCourse(_ context.Context, r *http.Request) (interface{}, error) {
+	var req course.CreateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	return req, nil
+}
+
+// 🎯 Decoder para GET: extrae el ID de la URL
+func decodeGetCourse(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+	return course.GetReq{ID: id}, nil
+}
+
+// 🎯 Decoder para GET ALL: extrae query parameters (limit, page, filters)
+func decodeGetAllCourses(_ context.Context, r *http.Request) (interface{}, error) {
+	// Extraer query parameters
+	query := r.URL.Query()
+
+	// Convertir limit y page a int
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	page := 0
+	if pageStr := query.Get("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil {
+			page = p
+		}
+	}
+
+	// Construir GetAllReq con los query parameters
+	req := course.GetAllReq{
+		Q:        query.Get("q"),
+		Name:     query.Get("name"),
+		Limit:    limit,
+		Page:     page,
+		Deleted:  query.Get("deleted"),
+		Tags:     queryTags(query),
+		TagMatch: query.Get("tag_match"),
+		Category: query.Get("category"),
+	}
+
+	return req, nil
+}
+
+// 🎯 Decoder para UPDATE: extrae ID de la URL y body JSON
+// Según el Content-Type acepta application/json (UpdateReq), application/merge-patch+json
+// (RFC 7386) o application/json-patch+json (RFC 6902, incluyendo operaciones "test")
+func decodeUpdateCourse(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+
+	r.Body = http.MaxBytesReader(nil, r.Body, maxPatchSize)
+
+	// 🔧 Merge Patch y JSON Patch se pasan crudos al servicio, que los aplica sobre el curso
+	contentType, err := mediaType(r)
+	if err != nil {
+		return nil, err
+	}
+	switch course.PatchType(contentType) {
+	case course.MergePatch, course.JSONPatch:
+		patch, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, apierror.BadRequest(ErrInvalidRequestBody)
+		}
+		return course.PatchReq{ID: id, Type: course.PatchType(contentType), Patch: patch}, nil
+	}
+
+	var req course.UpdateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+
+	// Asignar el ID extraído de la URL
+	req.ID = id
+	return req, nil
+}
+
+// mediaType devuelve el tipo del Content-Type sin parámetros y en minúsculas
+// (ej: "application/merge-patch+json; charset=utf-8"); sin header devuelve ""
+func mediaType(r *http.Request) (string, error) {
+	header := r.Header.Get("Content-Type")
+	if header == "" {
+		return "", nil
+	}
+	contentType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return "", apierror.BadRequest(ErrInvalidContentType)
+	}
+	return contentType, nil
+}
+
+// 🎯 Decoder para PUT: extrae ID de la URL y body JSON completo
+func decodeReplaceCourse(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+
+	var req course.ReplaceReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+
+	// El ID de la URL siempre prevalece sobre el del body
+	req.ID = id
+	return req, nil
+}
+
+// 🎯 Decoder para DELETE: extrae el ID de la URL
+func decodeDeleteCourse(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+	return course.DeleteReq{
+		ID:         id,
+		Purge:      r.URL.Query().Get("purge") == "true",
+		AdminToken: r.Header.Get(HeaderAdminToken),
+	}, nil
+}
+
+// 🎯 Decoder para HISTORY: extrae el ID de la URL y la paginación
+func decodeHistoryCourse(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	page, _ := strconv.Atoi(query.Get("page"))
+
+	return course.HistoryReq{ID: id, Limit: limit, Page: page}, nil
+}
+
+// 🎯 Decoder para RESTORE: extrae el ID de la URL
+func decodeRestoreCourse(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+	return course.RestoreReq{ID: id}, nil
+}
+
+// 🎯 Decoders para PREREQUISITES: el ID del curso viene en la URL
+func decodeAddPrerequisite(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+
+	var req course.PrerequisiteReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	req.ID = id
+	return req, nil
+}
+
+func decodeRemovePrerequisite(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+	return course.PrerequisiteReq{ID: id, PrerequisiteID: vars["prerequisite_id"]}, nil
+}
+
+// 🎯 Decoders para INSTRUCTORS: el ID del curso viene en la URL
+func decodeAssignInstructor(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+
+	var req course.InstructorReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	req.ID = id
+	return req, nil
+}
+
+func decodeRemoveInstructor(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+	return course.InstructorReq{ID: id, UserID: vars["user_id"]}, nil
+}
+
+func decodeSetTranslation(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+
+	var req course.TranslationReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	req.ID = id
+	req.Locale = vars["locale"]
+	return req, nil
+}
+
+func decodeRemoveTranslation(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || id == "" {
+		return nil, apierror.BadRequest(course.ErrIDRequired)
+	}
+	return course.TranslationReq{ID: id, Locale: vars["locale"]}, nil
+}
+
+// 🎯 Decoders para lotes: decodifican el body JSON con el modo y los ítems
+func decodeCreateBatch(_ context.Context, r *http.Request) (interface{}, error) {
+	var req course.BatchCreateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	return req, nil
+}
+
+func decodeUpdateBatch(_ context.Context, r *http.Request) (interface{}, error) {
+	var req course.BatchUpdateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	return req, nil
+}
+
+func decodeDeleteBatch(_ context.Context, r *http.Request) (interface{}, error) {
+	var req course.BatchDeleteReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	return req, nil
+}
+
+// 🎯 Decoder para IMPORT: acepta multipart/form-data (campo "file") o el archivo
+// crudo en el body. El formato se toma de ?format=, del Content-Type o de la extensión.
+func decodeImportCourses(_ context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+
+	mapping, err := course.ParseColumnMapping(query.Get("columns"))
+	if err != nil {
+		return nil, apierror.BadRequest(err)
+	}
+
+	r.Body = http.MaxBytesReader(nil, r.Body, maxImportSize)
+
+	var data []byte
+	format := course.ImportFormat(strings.ToLower(query.Get("format")))
+	contentType, err := mediaType(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType == "multipart/form-data" {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return nil, apierror.BadRequest(ErrFileRequired)
+		}
+		defer file.Close()
+		if data, err = io.ReadAll(file); err != nil {
+			return nil, apierror.BadRequest(ErrInvalidRequestBody)
+		}
+		if format == "" {
+			format = importFormatFromName(header.Filename)
+		}
+	} else {
+		if data, err = io.ReadAll(r.Body); err != nil {
+			return nil, apierror.BadRequest(ErrInvalidRequestBody)
+		}
+		if format == "" {
+			format = importFormatFromContentType(contentType)
+		}
+	}
+
+	if format == "" {
+		return nil, apierror.BadRequest(course.ErrUnsupportedImportFormat)
+	}
+
+	return course.ImportReq{
+		Format:  format,
+		Data:    data,
+		Mapping: mapping,
+		DryRun:  query.Get("dry_run") == "true",
+	}, nil
+}
+
+func importFormatFromName(name string) course.ImportFormat {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return course.ImportCSV
+	case ".xlsx":
+		return course.ImportXLSX
+	}
+	return ""
+}
+
+func importFormatFromContentType(contentType string) course.ImportFormat {
+	switch contentType {
+	case "text/csv":
+		return course.ImportCSV
+	case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+		return course.ImportXLSX
+	}
+	return ""
+}
+
+// 🎯 Decoder para EXPORT: extrae formato y filtros de los query parameters
+func decodeExportCourses(_ context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+
+	format := course.ExportFormat(strings.ToLower(query.Get("format")))
+	if format == "" {
+		format = course.ExportCSV
+	}
+
+	return course.ExportReq{
+		Name:     query.Get("name"),
+		Deleted:  query.Get("deleted"),
+		Tags:     queryTags(query),
+		TagMatch: query.Get("tag_match"),
+		Category: query.Get("category"),
+		Format:   format,
+	}, nil
+}
+
+// queryTags lee ?tag=, que se puede repetir (?tag=go&tag=web) o separar por comas
+func queryTags(query url.Values) []string {
+	var tags []string
+	for _, value := range query["tag"] {
+		tags = append(tags, strings.Split(value, ",")...)
+	}
+	return tags
+}
+
+// 🎯 Encoder para EXPORT: escribe el archivo en streaming
+func encodeExport(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+	exportResp, ok := resp.(course.ExportResp)
+	if !ok {
+		return encodeResponse(ctx, w, resp)
+	}
+
+	// 🔧 El catálogo completo puede tardar más que el WriteTimeout del servidor,
+	// así que quitamos el deadline sólo para esta respuesta
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", exportResp.Format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="courses.%s"`, exportResp.Format))
+	w.WriteHeader(http.StatusOK)
+
+	// Una vez enviados los headers no se puede cambiar el status: el servicio
+	// registra el error y la respuesta queda truncada
+	_ = exportResp.Write(w)
+	return nil
+}
+
+// 🎯 Decoder para SUGGEST: prefijo y cantidad máxima de sugerencias
+func decodeSuggestCourses(_ context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	return course.SuggestReq{
+		Prefix: query.Get("q"),
+		Limit:  limit,
+	}, nil
+}
+
+// 🎯 Decoder para EVENTS: filtros de los query parameters y cursor de reanudación.
+// En el stream las bajas interesan por defecto, así que sin deleted se usa "include"
+// y deleted=exclude las oculta.
+func decodeCourseEvents(_ context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+
+	deleted := query.Get("deleted")
+	switch deleted {
+	case "":
+		deleted = string(course.DeletedInclude)
+	case "exclude":
+		deleted = string(course.DeletedExclude)
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		// EventSource no permite headers propios al reconectar manualmente
+		lastEventID = query.Get("last_event_id")
+	}
+
+	return course.EventsReq{
+		Name:        query.Get("name"),
+		Deleted:     deleted,
+		Tags:        queryTags(query),
+		TagMatch:    query.Get("tag_match"),
+		Category:    query.Get("category"),
+		LastEventID: lastEventID,
+	}, nil
+}
+
+const (
+	// eventsPollInterval es cada cuánto se consulta el log de cambios
+	eventsPollInterval = time.Second
+	// eventsHeartbeat mantiene viva la conexión a través de proxies cuando no hay cambios
+	eventsHeartbeat = 15 * time.Second
+	// eventsWriteWindow es el deadline de cada escritura; se renueva en cada una para
+	// convivir con el WriteTimeout del servidor sin quitarlo del todo
+	eventsWriteWindow = 10 * time.Second
+)
+
+// 🎯 Encoder para EVENTS: mantiene la conexión abierta hasta que el cliente se va
+func encodeEvents(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+	eventsResp, ok := resp.(course.EventsResp)
+	if !ok {
+		return encodeResponse(ctx, w, resp)
+	}
+
+	rc := http.NewResponseController(w)
+	// 🔧 Sin deadline extendible no se puede sostener el stream más allá del WriteTimeout
+	if err := rc.SetWriteDeadline(time.Now().Add(eventsWriteWindow)); err != nil {
+		return encodeResponse(ctx, w, apierror.InternalServerError(ErrStreamingNotSupported))
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(payload string) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(eventsWriteWindow)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, payload); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	// retry indica al cliente cuánto esperar antes de reconectar
+	if err := write(fmt.Sprintf("retry: %d\n\n", (3 * time.Second).Milliseconds())); err != nil {
+		return nil
+	}
+
+	poll := time.NewTicker(eventsPollInterval)
+	defer poll.Stop()
+	lastWrite := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-poll.C:
+		}
+
+		events, err := eventsResp.Feed.Next(ctx)
+		if err != nil {
+			// Un error puntual de la base no corta el stream; se reintenta en el próximo poll
+			continue
+		}
+
+		for _, event := range events {
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if err := write(fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)); err != nil {
+				return nil
+			}
+			lastWrite = time.Now()
+		}
+
+		if time.Since(lastWrite) >= eventsHeartbeat {
+			if err := write(": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			lastWrite = time.Now()
+		}
+	}
+}
+
+// requestContext guarda en el contexto el actor (X-User-ID), el request ID
+// (X-Request-ID, o uno nuevo) que usa la auditoría y el registro de escrituras
+// para leer del primario lo que la misma request acaba de modificar
+func requestContext(ctx context.Context, r *http.Request) context.Context {
+	ctx = replica.WithTracker(ctx)
+	if actor := r.Header.Get("X-User-ID"); actor != "" {
+		ctx = audit.WithActor(ctx, actor)
+	}
+	requestID := r.Header.Get("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return audit.WithRequestID(ctx, requestID)
+}
+
+// requestIDHeader devuelve el request ID al cliente para poder correlacionar
+func requestIDHeader(ctx context.Context, w http.ResponseWriter) context.Context {
+	if requestID := audit.RequestIDFrom(ctx); requestID != "" {
+		w.Header().Set("X-Request-ID", requestID)
+	}
+	return ctx
+}
+
+// 🎯 Encoder para todas las respuestas exitosas
+func encodeResponse(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+	respObj, ok := resp.(response.Response)
+	if !ok {
+		// Si no es response.Response, es un error de programación
+		// encodeError debería haberlo manejado
+		respObj = apierror.InternalServerError(ErrInvalidResponseType)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(respObj.StatusCode())
+	return json.NewEncoder(w).Encode(respObj)
+}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	// 🔍 Intentamos convertir el error a response.Response
+	resp, ok := err.(response.Response)
+	if !ok {
+		// ❌ Si no es response.Response, es un error estándar de Go
+		// 💡 Lo convertimos a InternalServerError como fallback seguro
+		resp = apierror.InternalServerError(err)
+	}
+
+	// 🔧 Un 503 por base de datos caída indica al cliente cuándo reintentar
+	if unavailable, ok := resp.(*course.UnavailableResponse); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(unavailable.RetryAfter))
+	}
+
+	// 🏷️ El código estable es el del primer error de la cadena que tenga uno; si
+	// ninguno lo tiene (ej: un error de MySQL) es el genérico del status
+	code, ok := statusCodes[resp.StatusCode()]
+	if !ok {
+		code = "error"
+	}
+	var coded codedError
+	if errors.As(resp, &coded) {
+		code = coded.Code()
+	}
+
+	// 🌐 Se pasa por un map para no perder los campos extra (retry_after, conflicts) y
+	// agregar el código estable junto al mensaje traducido según Accept-Language
+	body := map[string]json.RawMessage{}
+	if raw, err := json.Marshal(resp); err == nil {
+		_ = json.Unmarshal(raw, &body)
+	}
+	body["code"], _ = json.Marshal(code)
+	body["message"], _ = json.Marshal(localizeError(ctx, resp))
+
+	w.WriteHeader(resp.StatusCode())
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/gorilla/mux"
+)
+
+func TestDecodeUpdateCourse(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        interface{}
+		err         error
+	}{
+		{
+			name:        "merge patch with parameters",
+			contentType: "application/merge-patch+json; charset=utf-8",
+			body:        `{"name":"Go"}`,
+			want:        course.PatchReq{ID: "42", Type: course.MergePatch, Patch: []byte(`{"name":"Go"}`)},
+		},
+		{
+			name:        "media type is case insensitive",
+			contentType: "Application/JSON-Patch+JSON",
+			body:        `[]`,
+			want:        course.PatchReq{ID: "42", Type: course.JSONPatch, Patch: []byte(`[]`)},
+		},
+		{
+			name: "json without Content-Type",
+			body: `{"name":"Go"}`,
+			want: course.UpdateReq{ID: "42", Name: stringPtr("Go")},
+		},
+		{
+			name:        "malformed Content-Type",
+			contentType: "application/json; charset",
+			body:        `{}`,
+			err:         ErrInvalidContentType,
+		},
+		{
+			name:        "body too large",
+			contentType: "application/merge-patch+json",
+			body:        `{"name":"` + strings.Repeat("a", maxPatchSize) + `"}`,
+			err:         ErrInvalidRequestBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PATCH", "/courses/42", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			r = mux.SetURLVars(r, map[string]string{"id": "42"})
+
+			got, err := decodeUpdateCourse(context.Background(), r)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("decodeUpdateCourse error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertRequest(t, got, tt.want)
+		})
+	}
+}
+
+func assertRequest(t *testing.T, got, want interface{}) {
+	t.Helper()
+	switch want := want.(type) {
+	case course.PatchReq:
+		got, ok := got.(course.PatchReq)
+		if !ok || got.ID != want.ID || got.Type != want.Type || string(got.Patch) != string(want.Patch) {
+			t.Errorf("request = %+v, want %+v", got, want)
+		}
+	case course.UpdateReq:
+		got, ok := got.(course.UpdateReq)
+		if !ok || got.ID != want.ID || got.Name == nil || *got.Name != *want.Name {
+			t.Errorf("request = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
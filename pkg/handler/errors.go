@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+)
+
+// Error es un error de la API con un código estable, el que reciben los clientes
+type Error struct {
+	code    string
+	message string
+}
+
+func newError(code, message string) *Error {
+	return &Error{code: code, message: message}
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Code() string {
+	return e.code
+}
+
+var ErrInvalidJSON = newError("invalid_json", "invalid JSON format")
+var ErrInvalidRequestBody = newError("invalid_request_body", "invalid request body")
+var ErrInvalidResponseType = newError("invalid_response_type", "invalid response type")
+var ErrInvalidContentType = newError("invalid_content_type", "invalid Content-Type header")
+var ErrFileRequired = newError("file_required", "file is required")
+var ErrStreamingNotSupported = newError("streaming_not_supported", "streaming not supported")
+var ErrInvalidBearerToken = newError("invalid_bearer_token", "invalid bearer token")
+var ErrTenantRequired = newError("tenant_required", "tenant is required")
+var ErrInvalidTenant = newError("invalid_tenant", tenant.ErrInvalidTenant.Error())
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/NicoJCastro/gocourse_course/internal/locale"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+)
+
+// WithLocale deja en el contexto los idiomas de Accept-Language que soporta el
+// servicio; las respuestas con cursos se traducen según ellos
+func WithLocale(cfg config.Locales, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		preference := locale.Negotiate(r.Header.Get("Accept-Language"), cfg.Supported, cfg.Default)
+		// 🔧 La misma URL cambia según el idioma: los caches deben distinguirlo
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(locale.WithPreference(r.Context(), preference)))
+	})
+}
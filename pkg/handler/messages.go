@@ -0,0 +1,351 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/NicoJCastro/gocourse_course/internal/locale"
+)
+
+type (
+	// translations son los textos de un mensaje de error por idioma
+	translations map[string]string
+
+	// codedError es un error con un código estable: el Error de cada paquete o un ErrNotFound
+	codedError interface {
+		error
+		Code() string
+	}
+
+	// argsError es un error con partes variables en el mensaje, como el ID de un ErrNotFound
+	argsError interface {
+		Args() []any
+	}
+)
+
+// statusCodes son los códigos de los errores que no tienen uno propio
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          "bad_request",
+	http.StatusUnauthorized:        "unauthorized",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "not_found",
+	http.StatusConflict:            "conflict",
+	http.StatusInternalServerError: "internal_error",
+	http.StatusServiceUnavailable:  "unavailable",
+}
+
+// errorMessages es el catálogo de los errores que devuelve la API, por código. El
+// texto en inglés es el del propio error; un código compartido por varios paquetes
+// (ej: "id_required") tiene una sola entrada.
+var errorMessages = map[string]translations{
+	// Requests
+	"invalid_json":                {"es": "formato JSON inválido", "pt": "formato JSON inválido"},
+	"invalid_request_body":        {"es": "cuerpo de la request inválido", "pt": "corpo da requisição inválido"},
+	"invalid_request_type":        {"es": "tipo de request inválido", "pt": "tipo de requisição inválido"},
+	"invalid_response_type":       {"es": "tipo de respuesta inválido", "pt": "tipo de resposta inválido"},
+	"id_required":                 {"es": "el id es obligatorio", "pt": "o id é obrigatório"},
+	"invalid_id":                  {"es": "formato de id inválido, debe ser un UUID", "pt": "formato de id inválido, deve ser um UUID"},
+	"at_least_one_field_required": {"es": "se requiere al menos un campo", "pt": "pelo menos um campo é obrigatório"},
+	"invalid_content_type":        {"es": "header Content-Type inválido", "pt": "header Content-Type inválido"},
+	"file_required":               {"es": "el archivo es obligatorio", "pt": "o arquivo é obrigatório"},
+	"metadata_error":              {"es": "error al generar la metadata", "pt": "erro ao gerar os metadados"},
+	"streaming_not_supported":     {"es": "streaming no soportado", "pt": "streaming não suportado"},
+	"admin_required":              {"es": "se requieren privilegios de administrador", "pt": "são necessários privilégios de administrador"},
+
+	// Tenants
+	"invalid_bearer_token": {"es": "bearer token inválido", "pt": "bearer token inválido"},
+	"tenant_required":      {"es": "el tenant es obligatorio", "pt": "o tenant é obrigatório"},
+	"invalid_tenant": {
+		"es": "el ID de tenant debe tener hasta 64 letras, dígitos, '-' o '_'",
+		"pt": "o ID de tenant deve ter até 64 letras, dígitos, '-' ou '_'"},
+
+	// Cursos
+	"course_not_found":     {"es": "curso no encontrado", "pt": "curso não encontrado"},
+	"name_required":        {"es": "el nombre es obligatorio", "pt": "o nome é obrigatório"},
+	"name_too_long":        {"es": "el nombre es demasiado largo", "pt": "o nome é muito longo"},
+	"description_too_long": {"es": "la descripción es demasiado larga", "pt": "a descrição é muito longa"},
+	"dates_required":       {"es": "start_date y end_date son obligatorios", "pt": "start_date e end_date são obrigatórios"},
+	"invalid_start_date": {
+		"es": "formato de fecha de inicio inválido, debe ser 2006-01-02 o RFC 3339",
+		"pt": "formato de data de início inválido, deve ser 2006-01-02 ou RFC 3339"},
+	"invalid_end_date": {
+		"es": "formato de fecha de fin inválido, debe ser 2006-01-02 o RFC 3339",
+		"pt": "formato de data de término inválido, deve ser 2006-01-02 ou RFC 3339"},
+	"start_date_after_end_date": {
+		"es": "la fecha de inicio es posterior a la fecha de fin",
+		"pt": "a data de início é posterior à data de término"},
+	"end_date_before_start_date": {
+		"es": "la fecha de fin es anterior a la fecha de inicio",
+		"pt": "a data de término é anterior à data de início"},
+	"invalid_timezone": {
+		"es": "zona horaria inválida, debe ser un nombre IANA como America/Argentina/Buenos_Aires",
+		"pt": "fuso horário inválido, deve ser um nome IANA como America/Sao_Paulo"},
+	"unsupported_patch_type": {"es": "content type de patch no soportado", "pt": "content type de patch não suportado"},
+	"invalid_patch":          {"es": "documento de patch inválido", "pt": "documento de patch inválido"},
+	"patch_test_failed":      {"es": "falló la operación test del patch", "pt": "a operação test do patch falhou"},
+	"id_immutable":           {"es": "el id no se puede modificar", "pt": "o id não pode ser modificado"},
+	"invalid_deleted_scope": {
+		"es": "filtro deleted inválido, debe ser only o include",
+		"pt": "filtro deleted inválido, deve ser only ou include"},
+	"purge_forbidden":          {"es": "el purge requiere privilegios de administrador", "pt": "o purge requer privilégios de administrador"},
+	"failed_to_create_course":  {"es": "no se pudo crear el curso", "pt": "não foi possível criar o curso"},
+	"failed_to_get_course":     {"es": "no se pudo obtener el curso", "pt": "não foi possível obter o curso"},
+	"failed_to_get_courses":    {"es": "no se pudieron obtener los cursos", "pt": "não foi possível obter os cursos"},
+	"failed_to_update_course":  {"es": "no se pudo actualizar el curso", "pt": "não foi possível atualizar o curso"},
+	"failed_to_delete_course":  {"es": "no se pudo eliminar el curso", "pt": "não foi possível excluir o curso"},
+	"failed_to_count_courses":  {"es": "no se pudieron contar los cursos", "pt": "não foi possível contar os cursos"},
+	"failed_to_replace_course": {"es": "no se pudo reemplazar el curso", "pt": "não foi possível substituir o curso"},
+	"failed_to_restore_course": {"es": "no se pudo recuperar el curso", "pt": "não foi possível restaurar o curso"},
+	"failed_to_purge_course": {
+		"es": "no se pudo eliminar definitivamente el curso",
+		"pt": "não foi possível excluir definitivamente o curso"},
+	"failed_to_record_audit": {"es": "no se pudo registrar la auditoría", "pt": "não foi possível registrar a auditoria"},
+	"failed_to_get_history": {
+		"es": "no se pudo obtener el historial del curso",
+		"pt": "não foi possível obter o histórico do curso"},
+	"failed_to_record_events": {
+		"es": "no se pudieron registrar los eventos del curso",
+		"pt": "não foi possível registrar os eventos do curso"},
+	"database_unavailable": {"es": "base de datos no disponible temporalmente", "pt": "banco de dados temporariamente indisponível"},
+
+	// Lotes, importación y exportación
+	"batch_empty":     {"es": "el lote debe tener al menos un ítem", "pt": "o lote deve ter pelo menos um item"},
+	"batch_too_large": {"es": "el lote supera la cantidad máxima de ítems", "pt": "o lote excede a quantidade máxima de itens"},
+	"invalid_batch_mode": {
+		"es": "modo de lote inválido, debe ser atomic o best_effort",
+		"pt": "modo de lote inválido, deve ser atomic ou best_effort"},
+	"batch_rolled_back": {"es": "se revirtió el lote porque falló un ítem", "pt": "o lote foi revertido porque um item falhou"},
+	"unsupported_import_format": {
+		"es": "formato de importación no soportado, debe ser csv o xlsx",
+		"pt": "formato de importação não suportado, deve ser csv ou xlsx"},
+	"invalid_import_file":      {"es": "archivo de importación inválido", "pt": "arquivo de importação inválido"},
+	"import_file_empty":        {"es": "el archivo de importación está vacío", "pt": "o arquivo de importação está vazio"},
+	"workbook_without_sheets":  {"es": "la planilla no tiene hojas", "pt": "a planilha não tem abas"},
+	"import_column_missing":    {"es": "no se encontró la columna a importar", "pt": "coluna de importação não encontrada"},
+	"invalid_column_mapping":   {"es": "mapeo de columnas inválido", "pt": "mapeamento de colunas inválido"},
+	"failed_to_import_courses": {"es": "no se pudieron importar los cursos", "pt": "não foi possível importar os cursos"},
+	"unsupported_export_format": {
+		"es": "formato de exportación no soportado, debe ser csv, ndjson o ics",
+		"pt": "formato de exportação não suportado, deve ser csv, ndjson ou ics"},
+	"failed_to_export_courses": {"es": "no se pudieron exportar los cursos", "pt": "não foi possível exportar os cursos"},
+
+	// Búsqueda
+	"search_query_required": {"es": "la búsqueda es obligatoria", "pt": "a consulta de busca é obrigatória"},
+	"search_query_too_long": {"es": "la búsqueda es demasiado larga", "pt": "a consulta de busca é muito longa"},
+	"failed_to_search":      {"es": "no se pudieron buscar los cursos", "pt": "não foi possível buscar os cursos"},
+	"search_filter_unsupported": {
+		"es": "el motor de búsqueda no admite filtros por tag o categoría",
+		"pt": "o mecanismo de busca não suporta filtros por tag ou categoria",
+	},
+	"change_filter_unsupported": {
+		"es": "el stream de cambios no admite filtros por tag o categoría",
+		"pt": "o stream de alterações não suporta filtros por tag ou categoria",
+	},
+
+	// Clasificación
+	"category_not_found": {"es": "categoría no encontrada", "pt": "categoria não encontrada"},
+	"invalid_tag":        {"es": "tag inválido", "pt": "tag inválida"},
+	"too_many_tags":      {"es": "demasiados tags", "pt": "tags demais"},
+	"invalid_tag_match":  {"es": "tag_match inválido, debe ser any o all", "pt": "tag_match inválido, deve ser any ou all"},
+	"failed_to_classify_course": {
+		"es": "no se pudo asignar la categoría y los tags del curso",
+		"pt": "não foi possível definir a categoria e as tags do curso"},
+
+	// Prerequisitos
+	"prerequisite_id_required": {"es": "prerequisite_id es obligatorio", "pt": "prerequisite_id é obrigatório"},
+	"self_prerequisite": {
+		"es": "un curso no puede ser su propio prerequisito",
+		"pt": "um curso não pode ser seu próprio pré-requisito"},
+	"prerequisite_cycle":     {"es": "el prerequisito generaría un ciclo", "pt": "o pré-requisito criaria um ciclo"},
+	"prerequisite_not_found": {"es": "prerequisito no encontrado", "pt": "pré-requisito não encontrado"},
+	"invalid_prerequisite_policy": {
+		"es": "política de prerequisitos inválida, debe ser restrict o cascade",
+		"pt": "política de pré-requisitos inválida, deve ser restrict ou cascade"},
+	"course_is_prerequisite": {"es": "el curso es prerequisito de otros cursos", "pt": "o curso é pré-requisito de outros cursos"},
+	"failed_to_get_prerequisites": {
+		"es": "no se pudieron obtener los prerequisitos",
+		"pt": "não foi possível obter os pré-requisitos"},
+	"failed_to_update_prerequisites": {
+		"es": "no se pudieron actualizar los prerequisitos",
+		"pt": "não foi possível atualizar os pré-requisitos"},
+
+	// Instructores
+	"schedule_conflict": {
+		"es": "el instructor tiene conflicto de horario con los cursos",
+		"pt": "o instrutor tem conflito de horário com os cursos"},
+	"sessions_outside_course": {
+		"es": "las nuevas fechas dejan sesiones del curso afuera",
+		"pt": "as novas datas deixam sessões do curso de fora"},
+	"user_id_required": {"es": "user_id es obligatorio", "pt": "user_id é obrigatório"},
+	"invalid_user_id": {
+		"es": "formato de id de usuario inválido, debe ser un UUID",
+		"pt": "formato de id de usuário inválido, deve ser um UUID"},
+	"instructor_not_found":      {"es": "instructor no encontrado", "pt": "instrutor não encontrado"},
+	"user_not_found":            {"es": "usuario no encontrado", "pt": "usuário não encontrado"},
+	"creator_not_found":         {"es": "no se encontró el usuario creador (X-User-ID)", "pt": "usuário criador (X-User-ID) não encontrado"},
+	"failed_to_get_instructors": {"es": "no se pudieron obtener los instructores", "pt": "não foi possível obter os instrutores"},
+	"failed_to_update_instructors": {
+		"es": "no se pudieron actualizar los instructores",
+		"pt": "não foi possível atualizar os instrutores"},
+
+	// Traducciones
+	"unsupported_locale":         {"es": "idioma no soportado", "pt": "idioma não suportado"},
+	"translation_not_found":      {"es": "traducción no encontrada", "pt": "tradução não encontrada"},
+	"failed_to_get_translations": {"es": "no se pudieron obtener las traducciones", "pt": "não foi possível obter as traduções"},
+	"failed_to_update_translations": {
+		"es": "no se pudieron actualizar las traducciones",
+		"pt": "não foi possível atualizar as traduções"},
+
+	// Sesiones
+	"course_id_required": {"es": "el id del curso es obligatorio", "pt": "o id do curso é obrigatório"},
+	"start_required":     {"es": "start es obligatorio", "pt": "start é obrigatório"},
+	"invalid_start": {
+		"es": "start inválido, debe ser RFC3339 o una hora local como 2006-01-02T15:04",
+		"pt": "start inválido, deve ser RFC3339 ou um horário local como 2006-01-02T15:04"},
+	"invalid_duration": {
+		"es": "duration_minutes inválido, debe estar entre 1 y 1440",
+		"pt": "duration_minutes inválido, deve estar entre 1 e 1440"},
+	"location_too_long":        {"es": "la ubicación es demasiado larga", "pt": "o local é muito longo"},
+	"invalid_rrule":            {"es": "rrule inválida", "pt": "rrule inválida"},
+	"empty_rrule":              {"es": "la rrule no genera ninguna sesión", "pt": "a rrule não gera nenhuma sessão"},
+	"too_many_sessions":        {"es": "la rrule genera demasiadas sesiones", "pt": "a rrule gera sessões demais"},
+	"session_outside_course":   {"es": "la sesión queda fuera de las fechas del curso", "pt": "a sessão fica fora das datas do curso"},
+	"invalid_range":            {"es": "from/to inválidos, deben ser RFC3339", "pt": "from/to inválidos, devem ser RFC3339"},
+	"failed_to_save_session":   {"es": "no se pudo guardar la sesión", "pt": "não foi possível salvar a sessão"},
+	"failed_to_get_sessions":   {"es": "no se pudieron obtener las sesiones", "pt": "não foi possível obter as sessões"},
+	"failed_to_delete_session": {"es": "no se pudo eliminar la sesión", "pt": "não foi possível excluir a sessão"},
+
+	// Catálogo
+	"tag_exists":                {"es": "ya existe un tag con ese nombre", "pt": "já existe uma tag com esse nome"},
+	"parent_category_not_found": {"es": "categoría padre no encontrada", "pt": "categoria pai não encontrada"},
+	"category_cycle": {
+		"es": "una categoría no puede moverse debajo de sí misma o de una de sus descendientes",
+		"pt": "uma categoria não pode ser movida para baixo de si mesma ou de uma de suas descendentes"},
+	"category_too_deep": {
+		"es": "la jerarquía de categorías es demasiado profunda",
+		"pt": "a hierarquia de categorias é profunda demais"},
+	"category_has_children": {
+		"es": "la categoría tiene subcategorías, muévalas o elimínelas primero",
+		"pt": "a categoria tem subcategorias, mova-as ou exclua-as primeiro"},
+	"failed_to_save_tag":        {"es": "no se pudo guardar el tag", "pt": "não foi possível salvar a tag"},
+	"failed_to_get_tags":        {"es": "no se pudieron obtener los tags", "pt": "não foi possível obter as tags"},
+	"failed_to_delete_tag":      {"es": "no se pudo eliminar el tag", "pt": "não foi possível excluir a tag"},
+	"failed_to_save_category":   {"es": "no se pudo guardar la categoría", "pt": "não foi possível salvar a categoria"},
+	"failed_to_get_categories":  {"es": "no se pudieron obtener las categorías", "pt": "não foi possível obter as categorias"},
+	"failed_to_delete_category": {"es": "no se pudo eliminar la categoría", "pt": "não foi possível excluir a categoria"},
+
+	// Webhooks
+	"url_required":         {"es": "la url es obligatoria", "pt": "a url é obrigatória"},
+	"invalid_url":          {"es": "la url debe ser una URL http o https absoluta", "pt": "a url deve ser uma URL http ou https absoluta"},
+	"url_not_allowed":      {"es": "la url debe resolver a una dirección pública", "pt": "a url deve resolver para um endereço público"},
+	"url_unresolvable":     {"es": "no se pudo resolver el host de la url", "pt": "não foi possível resolver o host da url"},
+	"event_types_required": {"es": "event_types es obligatorio", "pt": "event_types é obrigatório"},
+	"invalid_event_type":   {"es": "tipo de evento inválido", "pt": "tipo de evento inválido"},
+	"delivery_not_dead":    {"es": "sólo se pueden reintentar los envíos muertos", "pt": "apenas entregas mortas podem ser reenviadas"},
+	"failed_to_create_subscription": {
+		"es": "no se pudo crear la suscripción de webhook",
+		"pt": "não foi possível criar a assinatura de webhook"},
+	"failed_to_get_subscription": {
+		"es": "no se pudo obtener la suscripción de webhook",
+		"pt": "não foi possível obter a assinatura de webhook"},
+	"failed_to_update_subscription": {
+		"es": "no se pudo actualizar la suscripción de webhook",
+		"pt": "não foi possível atualizar a assinatura de webhook"},
+	"failed_to_delete_subscription": {
+		"es": "no se pudo eliminar la suscripción de webhook",
+		"pt": "não foi possível excluir a assinatura de webhook"},
+	"failed_to_get_deliveries": {
+		"es": "no se pudieron obtener los envíos de webhook",
+		"pt": "não foi possível obter as entregas de webhook"},
+	"failed_to_enqueue_deliveries": {
+		"es": "no se pudieron encolar los envíos de webhook",
+		"pt": "não foi possível enfileirar as entregas de webhook"},
+}
+
+// errorMessagesWithArgs traduce los errores con partes variables (Args), con un %s
+// para cada una; sin ellas se usa la entrada de errorMessages del mismo código
+var errorMessagesWithArgs = map[string]translations{
+	"course_not_found":   {"es": "no se encontró el curso con ID %s", "pt": "curso com ID %s não encontrado"},
+	"session_not_found":  {"es": "no se encontró la sesión con ID %s", "pt": "sessão com ID %s não encontrada"},
+	"tag_not_found":      {"es": "no se encontró el tag con ID %s", "pt": "tag com ID %s não encontrada"},
+	"category_not_found": {"es": "no se encontró la categoría con ID %s", "pt": "categoria com ID %s não encontrada"},
+	"webhook_subscription_not_found": {
+		"es": "no se encontró la suscripción de webhook con ID %s",
+		"pt": "assinatura de webhook com ID %s não encontrada"},
+	"webhook_delivery_not_found": {
+		"es": "no se encontró el envío de webhook con ID %s",
+		"pt": "entrega de webhook com ID %s não encontrada"},
+}
+
+// errorLanguages son los idiomas del catálogo, más el inglés de los propios errores
+var errorLanguages = catalogLanguages(errorMessages, errorMessagesWithArgs)
+
+func catalogLanguages(catalogs ...map[string]translations) map[string]bool {
+	languages := map[string]bool{"en": true}
+	for _, catalog := range catalogs {
+		for _, messages := range catalog {
+			for lang := range messages {
+				languages[lang] = true
+			}
+		}
+	}
+	return languages
+}
+
+// errorLanguage elige el primer idioma de Accept-Language que tenga el catálogo de
+// errores, aunque el servicio no traduzca cursos a él; sin ninguno queda en inglés
+func errorLanguage(ctx context.Context) string {
+	if preference, ok := locale.FromContext(ctx); ok {
+		for _, requested := range preference.Requested {
+			if errorLanguages[requested] {
+				return requested
+			}
+		}
+	}
+	return "en"
+}
+
+// localizeError traduce el mensaje del error al idioma de la request. Los errores
+// envueltos ("failed to create course: invalid start date ...") traducen cada parte
+// con código; lo que no tiene código queda igual.
+func localizeError(ctx context.Context, err error) string {
+	lang := errorLanguage(ctx)
+	if lang == "en" {
+		return err.Error()
+	}
+	return translateError(err, err.Error(), lang)
+}
+
+// translateError recorre la cadena de err y reemplaza en text el mensaje de cada
+// error con código por su traducción
+func translateError(err error, text, lang string) string {
+	if coded, ok := err.(codedError); ok {
+		if translated, ok := translation(coded, lang); ok {
+			return strings.Replace(text, coded.Error(), translated, 1)
+		}
+	}
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := wrapped.Unwrap(); inner != nil {
+			return translateError(inner, text, lang)
+		}
+	case interface{ Unwrap() []error }:
+		for _, inner := range wrapped.Unwrap() {
+			text = translateError(inner, text, lang)
+		}
+	}
+	return text
+}
+
+func translation(err codedError, lang string) (string, bool) {
+	if withArgs, ok := err.(argsError); ok {
+		if args := withArgs.Args(); len(args) > 0 {
+			template, ok := errorMessagesWithArgs[err.Code()][lang]
+			return fmt.Sprintf(template, args...), ok
+		}
+	}
+	text, ok := errorMessages[err.Code()][lang]
+	return text, ok
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/NicoJCastro/gocourse_course/internal/catalog"
+	"github.com/NicoJCastro/gocourse_course/internal/course"
+	"github.com/NicoJCastro/gocourse_course/internal/locale"
+	"github.com/NicoJCastro/gocourse_course/internal/session"
+	"github.com/NicoJCastro/gocourse_course/pkg/apierror"
+)
+
+func TestEncodeError(t *testing.T) {
+	// El servicio sólo traduce cursos a en y es: los errores igual salen en pt
+	supported := []string{"en", "es"}
+	tests := []struct {
+		name           string
+		err            error
+		acceptLanguage string
+		code           string
+		message        string
+	}{
+		{
+			name:           "sentinel",
+			err:            apierror.BadRequest(course.ErrNameRequired),
+			acceptLanguage: "es",
+			code:           "name_required",
+			message:        "el nombre es obligatorio",
+		},
+		{
+			name:           "not found with ID",
+			err:            apierror.NotFound(course.NewErrNotFound("42")),
+			acceptLanguage: "pt-BR,es;q=0.5",
+			code:           "course_not_found",
+			message:        "curso com ID 42 não encontrado",
+		},
+		{
+			name:           "same code without ID",
+			err:            apierror.NotFound(session.ErrCourseNotFound),
+			acceptLanguage: "pt",
+			code:           "course_not_found",
+			message:        "curso não encontrado",
+		},
+		{
+			name:           "code of the resource",
+			err:            apierror.NotFound(catalog.NewErrNotFound("category", "7")),
+			acceptLanguage: "es",
+			code:           "category_not_found",
+			message:        "no se encontró la categoría con ID 7",
+		},
+		{
+			name:           "wrapped errors",
+			err:            apierror.BadRequest(fmt.Errorf("%w: %w", course.ErrFailedToCreateCourse, course.ErrInvalidStartDate)),
+			acceptLanguage: "es",
+			code:           "failed_to_create_course",
+			message:        "no se pudo crear el curso: formato de fecha de inicio inválido, debe ser 2006-01-02 o RFC 3339",
+		},
+		{
+			name:           "detail without code",
+			err:            apierror.BadRequest(fmt.Errorf("%w: %s", course.ErrCategoryNotFound, "7")),
+			acceptLanguage: "es",
+			code:           "category_not_found",
+			message:        "categoría no encontrada: 7",
+		},
+		{
+			name:           "language not in the catalog",
+			err:            apierror.BadRequest(course.ErrNameRequired),
+			acceptLanguage: "fr",
+			code:           "name_required",
+			message:        "name is required",
+		},
+		{
+			name:           "error without code",
+			err:            errors.New("connection refused"),
+			acceptLanguage: "es",
+			code:           "internal_error",
+			message:        "connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := locale.WithPreference(context.Background(), locale.Negotiate(tt.acceptLanguage, supported, "en"))
+			w := httptest.NewRecorder()
+			encodeError(ctx, tt.err, w)
+
+			var body struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Code != tt.code || body.Message != tt.message {
+				t.Errorf("encodeError = %q, %q; want %q, %q", body.Code, body.Message, tt.code, tt.message)
+			}
+		})
+	}
+}
+
+// TestErrorCatalog verifica que cada código que declaran los paquetes esté traducido
+// a todos los idiomas del catálogo
+func TestErrorCatalog(t *testing.T) {
+	files, err := filepath.Glob("../../internal/*/error.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, "errors.go", "admin.go")
+
+	declared := regexp.MustCompile(`newError\("([a-z_]+)"`)
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range declared.FindAllSubmatch(src, -1) {
+			code := string(match[1])
+			for lang := range errorLanguages {
+				if _, ok := errorMessages[code][lang]; !ok && lang != "en" {
+					t.Errorf("%s: code %s has no %s translation", file, code, lang)
+				}
+			}
+		}
+	}
+
+	for _, catalog := range []map[string]translations{errorMessages, errorMessagesWithArgs} {
+		for code, messages := range catalog {
+			for lang := range errorLanguages {
+				if _, ok := messages[lang]; !ok && lang != "en" {
+					t.Errorf("code %s has no %s translation", code, lang)
+				}
+			}
+		}
+	}
+}
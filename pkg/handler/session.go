@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/NicoJCastro/gocourse_course/internal/session"
+	"github.com/NicoJCastro/gocourse_course/pkg/apierror"
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+func NewSessionHTTPServer(ctx context.Context, endpoints session.Endpoint) http.Handler {
+	mux := mux.NewRouter()
+
+	opts := []httptransport.ServerOption{
+		httptransport.ServerBefore(requestContext),
+		httptransport.ServerAfter(requestIDHeader),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// 🎯 POST /courses/{id}/sessions - Crear sesión o serie (con rrule)
+	mux.Handle("/courses/{id}/sessions", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Create),
+		decodeCreateSession,
+		encodeResponse,
+		opts...,
+	)).Methods("POST")
+
+	// 🎯 GETALL /courses/{id}/sessions - Listar sesiones por fecha (paginado, from/to opcionales)
+	mux.Handle("/courses/{id}/sessions", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.GetAll),
+		decodeGetAllSessions,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 GET /courses/{id}/sessions/{session_id} - Obtener sesión
+	mux.Handle("/courses/{id}/sessions/{session_id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Get),
+		decodeGetSession,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 PATCH /courses/{id}/sessions/{session_id} - Modificar una sesión (aunque sea de una serie)
+	mux.Handle("/courses/{id}/sessions/{session_id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Update),
+		decodeUpdateSession,
+		encodeResponse,
+		opts...,
+	)).Methods("PATCH")
+
+	// 🎯 DELETE /courses/{id}/sessions/{session_id} - Eliminar sesión; ?series=true elimina la serie
+	mux.Handle("/courses/{id}/sessions/{session_id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Delete),
+		decodeDeleteSession,
+		encodeResponse,
+		opts...,
+	)).Methods("DELETE")
+
+	return mux
+}
+
+func decodeCreateSession(_ context.Context, r *http.Request) (interface{}, error) {
+	courseID := mux.Vars(r)["id"]
+	if courseID == "" {
+		return nil, apierror.BadRequest(session.ErrCourseIDRequired)
+	}
+	var req session.CreateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	req.CourseID = courseID
+	return req, nil
+}
+
+func decodeGetAllSessions(_ context.Context, r *http.Request) (interface{}, error) {
+	courseID := mux.Vars(r)["id"]
+	if courseID == "" {
+		return nil, apierror.BadRequest(session.ErrCourseIDRequired)
+	}
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	page, _ := strconv.Atoi(query.Get("page"))
+	return session.GetAllReq{
+		CourseID: courseID,
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+		Limit:    limit,
+		Page:     page,
+	}, nil
+}
+
+func decodeGetSession(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	if vars["id"] == "" || vars["session_id"] == "" {
+		return nil, apierror.BadRequest(session.ErrIDRequired)
+	}
+	return session.GetReq{CourseID: vars["id"], ID: vars["session_id"]}, nil
+}
+
+func decodeUpdateSession(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	if vars["id"] == "" || vars["session_id"] == "" {
+		return nil, apierror.BadRequest(session.ErrIDRequired)
+	}
+	var req session.UpdateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	req.CourseID = vars["id"]
+	req.ID = vars["session_id"]
+	return req, nil
+}
+
+func decodeDeleteSession(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	if vars["id"] == "" || vars["session_id"] == "" {
+		return nil, apierror.BadRequest(session.ErrIDRequired)
+	}
+	return session.DeleteReq{
+		CourseID: vars["id"],
+		ID:       vars["session_id"],
+		Series:   r.URL.Query().Get("series") == "true",
+	}, nil
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/NicoJCastro/go_lib_response/response"
+	"github.com/NicoJCastro/gocourse_course/internal/tenant"
+	"github.com/NicoJCastro/gocourse_course/pkg/apierror"
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// WithTenant resuelve el tenant de cada request (claim del JWT o header) y lo deja
+// en el contexto; los repositorios filtran con él todas las consultas
+func WithTenant(cfg config.Tenancy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID, resp := resolveTenant(cfg, r)
+		if resp != nil {
+			encodeError(r.Context(), resp, w)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(tenant.WithTenant(r.Context(), tenantID)))
+	})
+}
+
+func resolveTenant(cfg config.Tenancy, r *http.Request) (string, response.Response) {
+	var tenantID string
+	if cfg.JWTSecret != "" {
+		// 🔧 Con JWT el header se ignora: un cliente no puede elegir otra organización
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			claims := jwt.MapClaims{}
+			_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+				return []byte(cfg.JWTSecret), nil
+			}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+			if err != nil {
+				return "", apierror.Unauthorized(ErrInvalidBearerToken)
+			}
+			tenantID, _ = claims[cfg.JWTClaim].(string)
+		}
+	} else {
+		tenantID = r.Header.Get(cfg.Header)
+	}
+
+	if tenantID == "" {
+		if cfg.Required {
+			return "", apierror.Unauthorized(ErrTenantRequired)
+		}
+		tenantID = cfg.Default
+	}
+	if tenant.Validate(tenantID) != nil {
+		return "", apierror.BadRequest(ErrInvalidTenant)
+	}
+	return tenantID, nil
+}
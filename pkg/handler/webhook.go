@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/NicoJCastro/gocourse_course/internal/webhook"
+	"github.com/NicoJCastro/gocourse_course/pkg/apierror"
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+func NewWebhookHTTPServer(ctx context.Context, endpoints webhook.Endpoint) http.Handler {
+	mux := mux.NewRouter()
+
+	opts := []httptransport.ServerOption{
+		httptransport.ServerBefore(requestContext),
+		httptransport.ServerAfter(requestIDHeader),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// 🎯 POST /webhooks - Crear suscripción (el secreto sólo se devuelve acá)
+	mux.Handle("/webhooks", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Create),
+		decodeCreateWebhook,
+		encodeResponse,
+		opts...,
+	)).Methods("POST")
+
+	// 🎯 GETALL /webhooks - Listar suscripciones (paginado)
+	mux.Handle("/webhooks", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.GetAll),
+		decodeGetAllWebhooks,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 GET /webhooks/{id} - Obtener suscripción
+	mux.Handle("/webhooks/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Get),
+		decodeGetWebhook,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 PATCH /webhooks/{id} - Actualizar url, event_types, secret o active
+	mux.Handle("/webhooks/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Update),
+		decodeUpdateWebhook,
+		encodeResponse,
+		opts...,
+	)).Methods("PATCH")
+
+	// 🎯 DELETE /webhooks/{id} - Eliminar suscripción
+	mux.Handle("/webhooks/{id}", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Delete),
+		decodeDeleteWebhook,
+		encodeResponse,
+		opts...,
+	)).Methods("DELETE")
+
+	// 🎯 GET /webhooks/{id}/deliveries - Log de envíos de la suscripción (paginado)
+	mux.Handle("/webhooks/{id}/deliveries", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Deliveries),
+		decodeWebhookDeliveries,
+		encodeResponse,
+		opts...,
+	)).Methods("GET")
+
+	// 🎯 POST /webhooks/{id}/deliveries/{delivery_id}/retry - Reencolar un envío en dead letter
+	mux.Handle("/webhooks/{id}/deliveries/{delivery_id}/retry", httptransport.NewServer(
+		endpoint.Endpoint(endpoints.Retry),
+		decodeRetryWebhookDelivery,
+		encodeResponse,
+		opts...,
+	)).Methods("POST")
+
+	return mux
+}
+
+// 🎯 Decoder para CREATE: decodifica el body JSON
+func decodeCreateWebhook(_ context.Context, r *http.Request) (interface{}, error) {
+	var req webhook.CreateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	return req, nil
+}
+
+// 🎯 Decoder para GET ALL: extrae limit y page
+func decodeGetAllWebhooks(_ context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	page, _ := strconv.Atoi(query.Get("page"))
+	return webhook.GetAllReq{Limit: limit, Page: page}, nil
+}
+
+// 🎯 Decoder para GET: extrae el ID de la URL
+func decodeGetWebhook(_ context.Context, r *http.Request) (interface{}, error) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		return nil, apierror.BadRequest(webhook.ErrIDRequired)
+	}
+	return webhook.GetReq{ID: id}, nil
+}
+
+// 🎯 Decoder para UPDATE: extrae ID de la URL y body JSON
+func decodeUpdateWebhook(_ context.Context, r *http.Request) (interface{}, error) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		return nil, apierror.BadRequest(webhook.ErrIDRequired)
+	}
+	var req webhook.UpdateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, apierror.BadRequest(ErrInvalidJSON)
+	}
+	req.ID = id
+	return req, nil
+}
+
+// 🎯 Decoder para DELETE: extrae el ID de la URL
+func decodeDeleteWebhook(_ context.Context, r *http.Request) (interface{}, error) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		return nil, apierror.BadRequest(webhook.ErrIDRequired)
+	}
+	return webhook.DeleteReq{ID: id}, nil
+}
+
+// 🎯 Decoder para DELIVERIES: extrae el ID y la paginación
+func decodeWebhookDeliveries(_ context.Context, r *http.Request) (interface{}, error) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		return nil, apierror.BadRequest(webhook.ErrIDRequired)
+	}
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	page, _ := strconv.Atoi(query.Get("page"))
+	return webhook.DeliveriesReq{ID: id, Limit: limit, Page: page}, nil
+}
+
+// 🎯 Decoder para RETRY: extrae ID de la suscripción y del envío
+func decodeRetryWebhookDelivery(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	if vars["id"] == "" || vars["delivery_id"] == "" {
+		return nil, apierror.BadRequest(webhook.ErrIDRequired)
+	}
+	return webhook.RetryReq{ID: vars["id"], DeliveryID: vars["delivery_id"]}, nil
+}
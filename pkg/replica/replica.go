@@ -0,0 +1,111 @@
+// Package replica enruta a réplicas de MySQL (GORM dbresolver) sólo las consultas
+// marcadas con Read, con read-your-writes dentro del contexto de cada request
+package replica
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+type (
+	contextKey struct{}
+
+	// tracker recuerda la última escritura hecha con un contexto de request
+	tracker struct {
+		lastWrite atomic.Int64
+	}
+)
+
+// settingKey marca en el Statement una consulta que puede ir a una réplica
+const settingKey = "replica:read"
+
+// Read marca la consulta como apta para una réplica: el resto (incluido todo lo
+// que corre en segundo plano, como el outbox) siempre lee del primario
+func Read(db *gorm.DB) *gorm.DB {
+	return db.Set(settingKey, true)
+}
+
+// WithTracker agrega al contexto el registro de escrituras de la request
+func WithTracker(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(contextKey{}).(*tracker); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, &tracker{})
+}
+
+// Register conecta las réplicas y hace que una lectura marcada con Read vaya al
+// primario si el mismo contexto escribió hace menos de window
+func Register(db *gorm.DB, replicas []gorm.Dialector, window time.Duration) (*dbresolver.DBResolver, error) {
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	})
+	if err := db.Use(resolver); err != nil {
+		return nil, err
+	}
+
+	route := func(tx *gorm.DB) {
+		if _, ok := tx.Statement.Settings.Load(settingKey); ok && !wroteRecently(tx.Statement.Context, window) {
+			dbresolver.Read.ModifyStatement(tx.Statement)
+			return
+		}
+		dbresolver.Write.ModifyStatement(tx.Statement)
+	}
+	// dbresolver se registra con Before("*"): GORM antepone cada callback "*"
+	// registrado después, así que route corre justo antes de que elija la conexión
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("*").Register("replica:route", route); err != nil {
+		return nil, err
+	}
+	if err := callbacks.Row().Before("*").Register("replica:route", route); err != nil {
+		return nil, err
+	}
+	if err := callbacks.Raw().Before("*").Register("replica:route", route); err != nil {
+		return nil, err
+	}
+
+	// Exec pasa por Raw: también cuenta como escritura
+	track := func(tx *gorm.DB) {
+		if tx.Error == nil {
+			markWrite(tx.Statement.Context)
+		}
+	}
+	if err := callbacks.Create().After("gorm:create").Register("replica:track", track); err != nil {
+		return nil, err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("replica:track", track); err != nil {
+		return nil, err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("replica:track", track); err != nil {
+		return nil, err
+	}
+	if err := callbacks.Raw().After("gorm:raw").Register("replica:track", track); err != nil {
+		return nil, err
+	}
+	return resolver, nil
+}
+
+func markWrite(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	if t, ok := ctx.Value(contextKey{}).(*tracker); ok {
+		t.lastWrite.Store(time.Now().UnixNano())
+	}
+}
+
+func wroteRecently(ctx context.Context, window time.Duration) bool {
+	if ctx == nil {
+		return false
+	}
+	t, ok := ctx.Value(contextKey{}).(*tracker)
+	if !ok {
+		return false
+	}
+	last := t.lastWrite.Load()
+	return last != 0 && time.Since(time.Unix(0, last)) < window
+}
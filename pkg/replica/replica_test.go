@@ -0,0 +1,100 @@
+package replica_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/pkg/replica"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type item struct {
+	ID   int
+	Name string
+}
+
+// open crea una base con un único item cuyo nombre identifica de qué base se leyó
+func open(t *testing.T, name string) (string, *gorm.DB) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name+".db")
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&item{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&item{ID: 1, Name: name}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return path, db
+}
+
+func TestReadYourWrites(t *testing.T) {
+	_, db := open(t, "primary")
+	replicaPath, _ := open(t, "replica")
+	const window = 100 * time.Millisecond
+	if _, err := replica.Register(db, []gorm.Dialector{sqlite.Open(replicaPath)}, window); err != nil {
+		t.Fatal(err)
+	}
+
+	source := func(ctx context.Context, read bool) string {
+		t.Helper()
+		tx := db.WithContext(ctx)
+		if read {
+			tx = replica.Read(tx)
+		}
+		var got item
+		if err := tx.First(&got, 1).Error; err != nil {
+			t.Fatal(err)
+		}
+		return got.Name
+	}
+
+	ctx := replica.WithTracker(context.Background())
+	if got := source(ctx, true); got != "replica" {
+		t.Errorf("marked read = %s, want replica", got)
+	}
+	if got := source(ctx, false); got != "primary" {
+		t.Errorf("unmarked read = %s, want primary", got)
+	}
+
+	// Después de escribir, la misma request lee del primario hasta que pasa window
+	if err := db.WithContext(ctx).Create(&item{ID: 2, Name: "new"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := source(ctx, true); got != "primary" {
+		t.Errorf("read after a write = %s, want primary", got)
+	}
+	if got := source(replica.WithTracker(context.Background()), true); got != "replica" {
+		t.Errorf("read of another request = %s, want replica", got)
+	}
+	if got := source(context.Background(), true); got != "replica" {
+		t.Errorf("read without a tracker = %s, want replica", got)
+	}
+	time.Sleep(window)
+	if got := source(ctx, true); got != "replica" {
+		t.Errorf("read after the window = %s, want replica", got)
+	}
+
+	// Exec también cuenta como escritura
+	if err := db.WithContext(ctx).Exec("UPDATE items SET name = ? WHERE id = ?", "new", 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := source(ctx, true); got != "primary" {
+		t.Errorf("read after Exec = %s, want primary", got)
+	}
+
+	// Una escritura fallida no fuerza el primario
+	ctx = replica.WithTracker(context.Background())
+	if err := db.WithContext(ctx).Create(&item{ID: 1, Name: "duplicate"}).Error; err == nil {
+		t.Fatal("duplicate Create succeeded")
+	}
+	if got := source(ctx, true); got != "replica" {
+		t.Errorf("read after a failed write = %s, want replica", got)
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+)
+
+// certReloader mantiene el certificado (y las CAs de clientes para mTLS) cargados
+// en memoria y los vuelve a leer cuando cambian los archivos, sin reiniciar
+type certReloader struct {
+	log    *log.Logger
+	config config.TLS
+
+	cert      atomic.Pointer[tls.Certificate]
+	clientCAs atomic.Pointer[x509.CertPool]
+	modTime   time.Time
+}
+
+func newCertReloader(logger *log.Logger, cfg config.TLS) (*certReloader, error) {
+	r := &certReloader{log: logger, config: cfg}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("error loading TLS certificate: %w", err)
+	}
+	if r.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("error reading TLS client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("error loading TLS client CA: no certificates found")
+		}
+		r.clientCAs.Store(pool)
+	}
+	r.cert.Store(&cert)
+	r.modTime = r.latestModTime()
+	return nil
+}
+
+// latestModTime es la modificación más reciente de los archivos; si alguno no se
+// puede leer (ej: en medio de una rotación) devuelve cero y se reintenta después
+func (r *certReloader) latestModTime() time.Time {
+	var latest time.Time
+	for _, file := range []string{r.config.CertFile, r.config.KeyFile, r.config.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// watch revisa los archivos cada interval y recarga si cambiaron. Si la recarga
+// falla se sigue sirviendo el certificado anterior.
+func (r *certReloader) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		modTime := r.latestModTime()
+		if modTime.IsZero() || modTime.Equal(r.modTime) {
+			continue
+		}
+		if err := r.load(); err != nil {
+			r.log.Println("error reloading TLS certificate: ", err)
+			continue
+		}
+		r.log.Println("TLS certificate reloaded")
+	}
+}
+
+func (r *certReloader) tlsConfig(http2 bool) *tls.Config {
+	// NextProtos explícito: GetConfigForClient devuelve una copia de base y no la
+	// que http.Server completa con "h2"
+	nextProtos := []string{"http/1.1"}
+	if http2 {
+		nextProtos = []string{"h2", "http/1.1"}
+	}
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.cert.Load(), nil
+		},
+	}
+	if r.config.ClientCAFile == "" {
+		return base
+	}
+
+	// 🔧 mTLS: cada handshake usa las CAs vigentes, así también se pueden rotar
+	base.ClientAuth = tls.RequireAndVerifyClientCert
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		config := base.Clone()
+		config.GetConfigForClient = nil
+		config.ClientCAs = r.clientCAs.Load()
+		return config, nil
+	}
+	return base
+}
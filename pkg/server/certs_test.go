@@ -0,0 +1,247 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+)
+
+// authority firma los certificados de prueba
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(t *testing.T, name string) *authority {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue firma un certificado de servidor (para 127.0.0.1) o de cliente y devuelve
+// el certificado y la clave en PEM
+func (a *authority) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile escribe el archivo con una fecha de modificación posterior a la
+// anterior, para que watch lo detecte aunque el sistema de archivos tenga poca resolución
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	modTime := time.Now()
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime().Add(time.Second)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serve atiende con la configuración TLS de reloader y devuelve la URL
+func serve(t *testing.T, reloader *certReloader) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{
+		Handler:   http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "ok") }),
+		TLSConfig: reloader.tlsConfig(false),
+		ErrorLog:  log.New(io.Discard, "", 0),
+	}
+	go srv.ServeTLS(ln, "", "")
+	t.Cleanup(func() { srv.Close() })
+	return "https://" + ln.Addr().String()
+}
+
+// get hace un request con un cliente nuevo (sin reusar conexiones) que confía en
+// roots y presenta clientCert si no es nil; devuelve el CN del certificado del servidor
+func get(url string, roots *x509.CertPool, clientCert *tls.Certificate) (string, error) {
+	config := &tls.Config{RootCAs: roots}
+	if clientCert != nil {
+		config.Certificates = []tls.Certificate{*clientCert}
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}, Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		return "", err
+	}
+	return resp.TLS.PeerCertificates[0].Subject.CommonName, nil
+}
+
+func TestCertReload(t *testing.T) {
+	ca := newAuthority(t, "ca")
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	dir := t.TempDir()
+	cfg := config.TLS{CertFile: filepath.Join(dir, "server.crt"), KeyFile: filepath.Join(dir, "server.key")}
+	certPEM, keyPEM := ca.issue(t, "v1", x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.CertFile, certPEM)
+	writeFile(t, cfg.KeyFile, keyPEM)
+
+	reloader, err := newCertReloader(log.New(io.Discard, "", 0), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := serve(t, reloader)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.watch(ctx, 10*time.Millisecond)
+
+	if name, err := get(url, roots, nil); err != nil || name != "v1" {
+		t.Fatalf("certificate = %s, %v; want v1", name, err)
+	}
+
+	// Un certificado roto se ignora y se sigue sirviendo el anterior
+	writeFile(t, cfg.CertFile, []byte("not a certificate"))
+	time.Sleep(50 * time.Millisecond)
+	if name, err := get(url, roots, nil); err != nil || name != "v1" {
+		t.Fatalf("certificate after a failed reload = %s, %v; want v1", name, err)
+	}
+
+	certPEM, keyPEM = ca.issue(t, "v2", x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.KeyFile, keyPEM)
+	writeFile(t, cfg.CertFile, certPEM)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		name, err := get(url, roots, nil)
+		if err == nil && name == "v2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("certificate = %s, %v; want v2 after the reload", name, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newAuthority(t, "ca")
+	clientCA := newAuthority(t, "client ca")
+	otherCA := newAuthority(t, "other ca")
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	dir := t.TempDir()
+	cfg := config.TLS{
+		CertFile:     filepath.Join(dir, "server.crt"),
+		KeyFile:      filepath.Join(dir, "server.key"),
+		ClientCAFile: filepath.Join(dir, "clients.crt"),
+	}
+	certPEM, keyPEM := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.CertFile, certPEM)
+	writeFile(t, cfg.KeyFile, keyPEM)
+	writeFile(t, cfg.ClientCAFile, clientCA.pem)
+
+	reloader, err := newCertReloader(log.New(io.Discard, "", 0), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := serve(t, reloader)
+
+	clientCert := func(a *authority) *tls.Certificate {
+		certPEM, keyPEM := a.issue(t, "client", x509.ExtKeyUsageClientAuth)
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &cert
+	}
+	trusted, untrusted := clientCert(clientCA), clientCert(otherCA)
+
+	if _, err := get(url, roots, nil); err == nil {
+		t.Error("request without a client certificate succeeded")
+	}
+	if _, err := get(url, roots, untrusted); err == nil {
+		t.Error("request with a certificate of another CA succeeded")
+	}
+	if _, err := get(url, roots, trusted); err != nil {
+		t.Errorf("request with a trusted certificate = %v", err)
+	}
+
+	// Rotar las CAs de clientes aplica a los handshakes siguientes
+	writeFile(t, cfg.ClientCAFile, otherCA.pem)
+	if err := reloader.load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := get(url, roots, untrusted); err != nil {
+		t.Errorf("request with the new CA = %v", err)
+	}
+	if _, err := get(url, roots, trusted); err == nil {
+		t.Error("request with the rotated out CA succeeded")
+	}
+
+	// Un archivo de CAs sin certificados no se acepta
+	writeFile(t, cfg.ClientCAFile, []byte("empty"))
+	if err := reloader.load(); err == nil {
+		t.Error("load with an empty client CA file succeeded")
+	}
+}
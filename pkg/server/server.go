@@ -0,0 +1,64 @@
+// Package server arma el http.Server de la API a partir de la configuración:
+// dirección, timeouts, HTTP/2 y TLS (con recarga de certificados y mTLS opcional)
+package server
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/NicoJCastro/gocourse_course/pkg/config"
+)
+
+type Server struct {
+	log    *log.Logger
+	srv    *http.Server
+	certs  *certReloader
+	config config.Server
+}
+
+func New(logger *log.Logger, cfg config.Server, handler http.Handler) (*Server, error) {
+	srv := &http.Server{
+		Handler:           handler,
+		Addr:              net.JoinHostPort(cfg.Host, cfg.Port),
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		ErrorLog:          logger,
+	}
+
+	// 🔧 HTTP/2 va sobre TLS (ALPN) o, sin TLS, en texto plano con prior knowledge (h2c)
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(cfg.HTTP2)
+	protocols.SetUnencryptedHTTP2(cfg.HTTP2 && cfg.TLS.CertFile == "")
+	srv.Protocols = protocols
+
+	s := &Server{log: logger, srv: srv, config: cfg}
+	if cfg.TLS.CertFile == "" {
+		return s, nil
+	}
+
+	certs, err := newCertReloader(logger, cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	s.certs = certs
+	srv.TLSConfig = certs.tlsConfig(cfg.HTTP2)
+	return s, nil
+}
+
+// ListenAndServe atiende en HTTP o HTTPS según la configuración; ctx detiene la
+// recarga de certificados
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	if s.certs == nil {
+		s.log.Println("listen in ", s.srv.Addr)
+		return s.srv.ListenAndServe()
+	}
+	go s.certs.watch(ctx, s.config.TLS.ReloadInterval)
+	s.log.Println("listen (TLS) in ", s.srv.Addr)
+	// Los certificados salen de TLSConfig.GetCertificate para poder recargarlos
+	return s.srv.ListenAndServeTLS("", "")
+}
@@ -0,0 +1,36 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/pkg/breaker"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+// breakerClient corta las consultas mientras el servicio de usuarios falla y
+// responde ErrUnavailable sin llamarlo
+type breakerClient struct {
+	client  Client
+	breaker *breaker.Breaker
+}
+
+func NewBreaker(logger *log.Logger, client Client, threshold int, openTimeout time.Duration) Client {
+	return &breakerClient{
+		client:  client,
+		breaker: breaker.New(logger, "user service", threshold, openTimeout),
+	}
+}
+
+func (b *breakerClient) Get(ctx context.Context, id string) (*domain.User, error) {
+	if ok, _ := b.breaker.Allow(); !ok {
+		return nil, fmt.Errorf("%w: circuit open", ErrUnavailable)
+	}
+	user, err := b.client.Get(ctx, id)
+	// Un usuario inexistente es una respuesta válida del servicio, no un fallo
+	b.breaker.Record(err == nil || errors.Is(err, ErrNotFound))
+	return user, err
+}
@@ -0,0 +1,72 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+type (
+	// cache recuerda durante ttl los usuarios encontrados y los inexistentes; los
+	// errores de disponibilidad no se guardan para no extender una caída
+	cache struct {
+		client Client
+		ttl    time.Duration
+
+		mu      sync.Mutex
+		entries map[string]cacheEntry
+	}
+
+	cacheEntry struct {
+		user    *domain.User
+		err     error
+		expires time.Time
+	}
+)
+
+// maxCacheEntries acota la memoria: al llenarse se descartan las entradas vencidas
+const maxCacheEntries = 10000
+
+func NewCache(client Client, ttl time.Duration) Client {
+	if ttl <= 0 {
+		return client
+	}
+	return &cache{
+		client:  client,
+		ttl:     ttl,
+		entries: map[string]cacheEntry{},
+	}
+}
+
+func (c *cache) Get(ctx context.Context, id string) (*domain.User, error) {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[id]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.user, entry.err
+	}
+
+	user, err := c.client.Get(ctx, id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCacheEntries {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= maxCacheEntries {
+			c.entries = map[string]cacheEntry{}
+		}
+	}
+	c.entries[id] = cacheEntry{user: user, err: err, expires: now.Add(c.ttl)}
+	return user, err
+}
@@ -0,0 +1,64 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+type httpClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPClient consulta GET {baseURL}/users/{id}, que responde con el formato
+// de go_lib_response: {"status": 200, "data": {...}}
+func NewHTTPClient(baseURL string, timeout time.Duration) Client {
+	return &httpClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *httpClient) Get(ctx context.Context, id string) (*domain.User, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/users/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if requestID := requestIDFrom(ctx); requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+		return nil, fmt.Errorf("%w: status %d", ErrUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected user service status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data *domain.User `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: invalid response: %v", ErrUnavailable, err)
+	}
+	if body.Data == nil || body.Data.ID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return body.Data, nil
+}
@@ -0,0 +1,72 @@
+// Package user es el cliente del servicio de usuarios de gocourse
+package user
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+type (
+	// Client consulta usuarios del servicio de usuarios
+	Client interface {
+		// Get devuelve ErrNotFound si el usuario no existe y ErrUnavailable si no
+		// se pudo confirmar (servicio caído, timeout o circuito abierto)
+		Get(ctx context.Context, id string) (*domain.User, error)
+	}
+
+	// Config configura el cliente HTTP y sus protecciones
+	Config struct {
+		// BaseURL del servicio de usuarios, ej: http://localhost:8081
+		BaseURL string
+		// Timeout de cada request
+		Timeout time.Duration
+		// CacheTTL es cuánto se recuerda un usuario encontrado o inexistente
+		CacheTTL time.Duration
+		// FailureThreshold son los fallos seguidos que abren el circuito
+		FailureThreshold int
+		// OpenTimeout es cuánto queda abierto el circuito antes de volver a probar
+		OpenTimeout time.Duration
+	}
+)
+
+type contextKey string
+
+const requestIDKey contextKey = "user_request_id"
+
+var (
+	ErrNotFound    = errors.New("user not found")
+	ErrUnavailable = errors.New("user service unavailable")
+)
+
+// WithRequestID fija el request ID que el cliente envía como X-Request-ID, para
+// seguir la consulta en los logs del servicio de usuarios
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func requestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// DefaultConfig: timeout corto porque la consulta está en el camino de la request
+func DefaultConfig(baseURL string) Config {
+	return Config{
+		BaseURL:          baseURL,
+		Timeout:          2 * time.Second,
+		CacheTTL:         time.Minute,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// New arma el cliente completo: HTTP con timeout, circuit breaker y cache
+func New(logger *log.Logger, config Config) Client {
+	client := NewHTTPClient(config.BaseURL, config.Timeout)
+	client = NewBreaker(logger, client, config.FailureThreshold, config.OpenTimeout)
+	return NewCache(client, config.CacheTTL)
+}
@@ -0,0 +1,126 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_course/pkg/user"
+	"github.com/NicoJCastro/gocourse_course/pkg/user/usertest"
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+const userID = "3f2c1d4e-5b6a-4c7d-8e9f-0a1b2c3d4e5f"
+
+func newClient(t *testing.T, config func(*user.Config)) (user.Client, *usertest.Server) {
+	t.Helper()
+	server := usertest.NewServer(domain.User{ID: userID, FirstName: "Ana"})
+	t.Cleanup(server.Close)
+
+	cfg := user.DefaultConfig(server.URL)
+	config(&cfg)
+	return user.New(log.New(io.Discard, "", 0), cfg), server
+}
+
+func TestGet(t *testing.T) {
+	client, server := newClient(t, func(*user.Config) {})
+	ctx := user.WithRequestID(context.Background(), "req-123")
+
+	found, err := client.Get(ctx, userID)
+	if err != nil || found.FirstName != "Ana" {
+		t.Fatalf("Get = %+v, %v; want Ana", found, err)
+	}
+	if got := server.LastRequestID(); got != "req-123" {
+		t.Errorf("X-Request-ID = %q, want req-123", got)
+	}
+	if _, err := client.Get(ctx, "missing"); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("Get(missing) = %v, want %v", err, user.ErrNotFound)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	client, server := newClient(t, func(cfg *user.Config) { cfg.CacheTTL = 100 * time.Millisecond })
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := client.Get(ctx, userID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Get(ctx, "missing"); !errors.Is(err, user.ErrNotFound) {
+			t.Fatalf("Get(missing) = %v, want %v", err, user.ErrNotFound)
+		}
+	}
+	if hits := server.Hits(); hits != 2 {
+		t.Errorf("hits = %d, want 2: found and missing users are cached", hits)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.Get(ctx, userID); err != nil {
+		t.Fatal(err)
+	}
+	if hits := server.Hits(); hits != 3 {
+		t.Errorf("hits = %d, want 3 after the TTL", hits)
+	}
+
+	// Las caídas no se cachean: cuando el servicio vuelve se consulta otra vez
+	server.SetDown(true)
+	if _, err := client.Get(ctx, "other"); !errors.Is(err, user.ErrUnavailable) {
+		t.Fatalf("Get while down = %v, want %v", err, user.ErrUnavailable)
+	}
+	server.SetDown(false)
+	if _, err := client.Get(ctx, "other"); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("Get after recovery = %v, want %v", err, user.ErrNotFound)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	client, server := newClient(t, func(cfg *user.Config) { cfg.Timeout = 50 * time.Millisecond })
+	server.SetDelay(time.Second)
+
+	start := time.Now()
+	if _, err := client.Get(context.Background(), userID); !errors.Is(err, user.ErrUnavailable) {
+		t.Errorf("Get = %v, want %v", err, user.ErrUnavailable)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Get took %s, want it cut at the timeout", elapsed)
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	client, server := newClient(t, func(cfg *user.Config) {
+		cfg.CacheTTL = 0
+		cfg.FailureThreshold = 2
+		cfg.OpenTimeout = 100 * time.Millisecond
+	})
+	ctx := context.Background()
+
+	// Un usuario inexistente no cuenta como fallo
+	for range 3 {
+		if _, err := client.Get(ctx, "missing"); !errors.Is(err, user.ErrNotFound) {
+			t.Fatalf("Get(missing) = %v, want %v", err, user.ErrNotFound)
+		}
+	}
+
+	server.SetDown(true)
+	for range 4 {
+		if _, err := client.Get(ctx, userID); !errors.Is(err, user.ErrUnavailable) {
+			t.Fatalf("Get while down = %v, want %v", err, user.ErrUnavailable)
+		}
+	}
+	if hits := server.Hits(); hits != 5 {
+		t.Errorf("hits = %d, want 5: the circuit opens after 2 failures", hits)
+	}
+
+	// Pasado OpenTimeout la llamada de prueba llega al servicio y cierra el circuito
+	server.SetDown(false)
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.Get(ctx, userID); err != nil {
+		t.Errorf("Get after OpenTimeout = %v, want the user", err)
+	}
+	if hits := server.Hits(); hits != 6 {
+		t.Errorf("hits = %d, want 6", hits)
+	}
+}
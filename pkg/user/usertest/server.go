@@ -0,0 +1,101 @@
+// Package usertest levanta un servicio de usuarios falso con httptest para
+// probar el cliente y el servicio de cursos sin depender de gocourse_user
+package usertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NicoJCastro/gocourse_domain/domain"
+)
+
+// Server responde GET /users/{id} como el servicio real. Down simula una caída
+// (503) y Delay una respuesta lenta para probar el timeout.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.RWMutex
+	users     map[string]domain.User
+	down      bool
+	delay     time.Duration
+	requestID string
+	hits      atomic.Int64
+}
+
+func NewServer(users ...domain.User) *Server {
+	s := &Server{users: map[string]domain.User{}}
+	for _, user := range users {
+		s.users[user.ID] = user
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *Server) Add(user domain.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user.ID] = user
+}
+
+func (s *Server) SetDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *Server) SetDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = delay
+}
+
+// Hits es la cantidad de requests recibidas, para verificar cache y circuit breaker
+func (s *Server) Hits() int64 {
+	return s.hits.Load()
+}
+
+// LastRequestID es el X-Request-ID de la última request recibida
+func (s *Server) LastRequestID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.requestID
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.hits.Add(1)
+	s.mu.Lock()
+	s.requestID = r.Header.Get("X-Request-ID")
+	down, delay := s.down, s.delay
+	user, found := s.users[strings.TrimPrefix(r.URL.Path, "/users/")]
+	s.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	switch {
+	case down:
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"status": http.StatusServiceUnavailable, "message": "service unavailable"})
+	case r.Method != http.MethodGet || !strings.HasPrefix(r.URL.Path, "/users/"):
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"status": http.StatusNotFound, "message": "not found"})
+	case !found:
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"status": http.StatusNotFound, "message": "user not found"})
+	default:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": http.StatusOK, "message": "success", "data": user})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}